@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
 	emailexchange "event-service/email/echange"
 	"event-service/internal/di"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -18,11 +25,51 @@ func init() {
 }
 
 func startConsumer(_ *cobra.Command, _ []string) {
-	eventUpdateConsumer := di.NewEventUpdateConsumer()
+	defer di.CloseAllExchangeConnections()
+
+	shutdownTracing, tracingErr := di.SetupTracing(context.Background(), "email-service")
+	if tracingErr != nil {
+		log.WithError(tracingErr).Panic("cannot set up tracing")
+	}
+
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.WithError(err).Error("cannot flush spans")
+		}
+	}()
+
+	mailer, err := di.Mailer()
+	if err != nil {
+		log.WithError(err).Panic("cannot create mailer")
+	}
 
-	handler := emailexchange.NewEventQueryHandler()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	if err := eventUpdateConsumer.Consume(handler.Handle); err != nil {
-		panic(err)
+	consumers := map[string]func(ctx context.Context) error{
+		"event update": func(ctx context.Context) error {
+			return di.NewEventUpdateConsumer().Consume(ctx, emailexchange.NewEventQueryHandler(mailer).Handle)
+		},
+		"invitation": func(ctx context.Context) error {
+			return di.NewInvitationConsumer().Consume(ctx, emailexchange.NewInvitationHandler(mailer).Handle)
+		},
 	}
+
+	wg := sync.WaitGroup{}
+	for name, consume := range consumers {
+		wg.Add(1)
+
+		go func(name string, consume func(ctx context.Context) error) {
+			defer wg.Done()
+
+			// the service stops when any of the consumers stops
+			defer stop()
+
+			if err := consume(ctx); err != nil {
+				log.WithError(err).Error(name + " consumer stopped")
+			}
+		}(name, consume)
+	}
+
+	wg.Wait()
 }
@@ -1,12 +1,12 @@
 package cmd
 
 import (
+	"event-service/internal/database"
 	"event-service/internal/database/gorm"
 	"event-service/internal/di"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -38,8 +38,13 @@ func migrationHandler(cmd *cobra.Command, _ []string) {
 		log.WithContext(ctx).WithError(driverErr).Panic("could not get driver")
 	}
 
-	migration, migrationErr := migrate.NewWithDatabaseInstance(
-		"file://migrations",
+	src, srcErr := database.MigrationsSource()
+	if srcErr != nil {
+		log.WithContext(ctx).WithError(srcErr).Panic("could not read migrations")
+	}
+
+	migration, migrationErr := migrate.NewWithInstance(
+		database.MigrationsSourceName, src,
 		di.MysqlDriver(), driver)
 	if migrationErr != nil {
 		log.WithContext(ctx).WithError(migrationErr).Panic("could not get driver")
@@ -1,16 +1,29 @@
 package cmd
 
 import (
-	"net/http"
+	"context"
+	"errors"
+	stdlog "log"
 	_ "net/http/pprof"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"event-service/graph"
+	"event-service/internal/auth"
 	"event-service/internal/config"
 	"event-service/internal/di"
 	ihtttp "event-service/internal/http"
+	"event-service/internal/metrics"
 	"event-service/internal/profiler"
+	"event-service/internal/tracing"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -32,18 +45,76 @@ func init() {
 }
 
 func startApi(cmd *cobra.Command, _ []string) {
-	defer di.CloseAllExchangeConnections()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, tracingErr := di.SetupTracing(ctx, "event-api")
+	if tracingErr != nil {
+		log.WithError(tracingErr).Panic("cannot set up tracing")
+	}
+
+	verifier, verifierErr := di.TokenVerifier()
+	if verifierErr != nil {
+		log.WithError(verifierErr).Panic("cannot create token verifier")
+	}
 
 	r := chi.NewRouter()
 	r.Use(
 		middleware.RequestID,
 		middleware.RealIP,
-		middleware.Logger,
+		// tokens of the calendar links are not written to the access logs
+		middleware.RequestLogger(ihtttp.RedactingLogFormatter{
+			Formatter: &middleware.DefaultLogFormatter{Logger: stdlog.New(os.Stdout, "", stdlog.LstdFlags)},
+		}),
 		middleware.Recoverer,
-		ihtttp.GORMConnectionMiddleware(di.GORM()),
+		ihtttp.TracingMiddleware,
+		ihtttp.AuthenticationMiddleware(verifier),
 	)
 
+	if !di.UsesInMemoryStorage() {
+		r.Use(ihtttp.GORMConnectionMiddleware(di.GORM()))
+	}
+
 	r.Mount("/debug/pprof", profiler.Router())
+	r.Handle("/metrics", metrics.Handler())
+
+	checker, checkerErr := di.HealthChecker()
+	if checkerErr != nil {
+		log.WithError(checkerErr).Panic("cannot create health checker")
+	}
+
+	readiness := &ihtttp.Readiness{}
+	r.Get("/livez", ihtttp.LivenessHandler())
+	r.Get("/healthz", ihtttp.HealthHandler(checker))
+	r.Get("/readyz", ihtttp.ReadinessHandler(readiness, checker))
+
+	// resources are closed in order after in-flight requests were drained, subscriptions are ended first
+	// so that clients reconnect to another replica
+	lifecycle := []ihtttp.ServerConfiguration{
+		ihtttp.WithReadiness(readiness),
+		ihtttp.WithDrainTimeout(config.GetDuration("API.SHUTDOWN.DRAIN_TIMEOUT")),
+		ihtttp.WithReadinessDelay(config.GetDuration("API.SHUTDOWN.READINESS_DELAY")),
+		ihtttp.WithCloser("subscriptions", runSubscriptions()),
+	}
+
+	if di.UsesInMemoryStorage() && di.AmpqEnabled() {
+		lifecycle = append(lifecycle, ihtttp.WithCloser("outbox relay", relayInMemoryOutbox()))
+	}
+
+	lifecycle = append(lifecycle, ihtttp.WithCloser("amqp connection", func(context.Context) error {
+		di.CloseAllExchangeConnections()
+
+		return nil
+	}))
+
+	if !di.UsesInMemoryStorage() {
+		lifecycle = append(lifecycle, ihtttp.WithCloser("database connection", func(context.Context) error {
+			return di.CloseGORM()
+		}))
+	}
+
+	// spans of the drained requests are flushed at the end
+	lifecycle = append(lifecycle, ihtttp.WithCloser("tracer provider", shutdownTracing))
 
 	port := config.GetStringOrFallback("API.PORT", defaultPort)
 
@@ -52,11 +123,115 @@ func startApi(cmd *cobra.Command, _ []string) {
 		log.WithError(err).Panic("cannot create graphql resolver")
 	}
 
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
+	finder, finderErr := di.DefaultEventsListHandler()
+	if finderErr != nil {
+		log.WithError(finderErr).Panic("cannot create events finder")
+	}
+
+	calendarHandler := ihtttp.NewCalendarHandler(finder)
+	r.Group(func(r chi.Router) {
+		r.Use(ihtttp.CalendarTokenMiddleware(di.CalendarTokens()))
+		r.Get("/events/{id}.ics", calendarHandler.Event)
+		r.Get("/users/{id}/calendar.ics", calendarHandler.UserCalendar)
+	})
+
+	srv := newGraphQLServer(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}), verifier)
+	srv.SetErrorPresenter(graph.ErrorPresenter)
+	srv.Use(metrics.GraphQLExtension{})
+	srv.Use(tracing.GraphQLExtension{})
+	srv.Use(graph.LoadersExtension{Finder: resolver.FindEventsHandler})
 
 	r.Handle("/", playground.Handler("GraphQL playground", "/query"))
 	r.Handle("/query", srv)
 
 	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
-	log.Fatal(http.ListenAndServe(":"+port, r))
+
+	if err := ihtttp.NewServer(":"+port, r, lifecycle...).Run(ctx); err != nil {
+		log.WithError(err).Fatal("api stopped with error")
+	}
+
+	log.Info("api stopped")
+}
+
+// relayInMemoryOutbox publishes messages of the in memory outbox, they are not visible to the event service process.
+// The returned closer stops the relay and publishes messages written while the api was draining
+func relayInMemoryOutbox() func(ctx context.Context) error {
+	relay, err := di.OutboxRelay()
+	if err != nil {
+		log.WithError(err).Panic("cannot create outbox relay")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if err := relay.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.WithError(err).Error("outbox relay stopped")
+		}
+	}()
+
+	return func(ctx context.Context) error {
+		cancel()
+		<-done
+
+		_, err := relay.RelayPending(ctx)
+
+		return err
+	}
+}
+
+// newGraphQLServer serves the transports of the default server, subscriptions authenticate with the token
+// of the websocket connection init payload
+func newGraphQLServer(es graphql.ExecutableSchema, verifier auth.TokenVerifier) *handler.Server {
+	srv := handler.New(es)
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc:              ihtttp.WebsocketInitFunc(verifier),
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New(1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New(100),
+	})
+
+	return srv
+}
+
+// runSubscriptions feeds subscriptions with changes made through other replicas when the message broker is configured.
+// The returned closer stops the feed and ends all subscriptions
+func runSubscriptions() func(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	if di.AmpqEnabled() {
+		bridge := di.SubscriptionsBridge()
+
+		go func() {
+			defer close(done)
+
+			if err := bridge.Run(ctx); err != nil {
+				log.WithError(err).Error("subscriptions bridge stopped")
+			}
+		}()
+	} else {
+		close(done)
+	}
+
+	return func(context.Context) error {
+		cancel()
+		<-done
+
+		di.SubscriptionBroker().Close()
+
+		return nil
+	}
 }
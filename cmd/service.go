@@ -1,12 +1,22 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"event-service/internal/database/gorm"
+	"event-service/internal/di"
+
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 var serviceCmd = &cobra.Command{
 	Use:   "start-event-service",
-	Short: "cli that starts events service",
+	Short: "cli that relays messages from the outbox to the message broker",
 	Run:   startEventService,
 }
 
@@ -15,5 +25,34 @@ func init() {
 }
 
 func startEventService(_ *cobra.Command, _ []string) {
-	//ctx := gorm.ContextWithConnection(cmd.Context(), di.GORM())
+	defer di.CloseAllExchangeConnections()
+
+	if di.UsesInMemoryStorage() {
+		log.Fatal("in memory outbox is relayed by the api process, the event service requires mysql storage")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, tracingErr := di.SetupTracing(context.Background(), "event-service")
+	if tracingErr != nil {
+		log.WithError(tracingErr).Panic("cannot set up tracing")
+	}
+
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.WithError(err).Error("cannot flush spans")
+		}
+	}()
+
+	relay, err := di.OutboxRelay()
+	if err != nil {
+		log.WithError(err).Panic("cannot create outbox relay")
+	}
+
+	log.Info("relaying outbox messages")
+
+	if err := relay.Run(gorm.ContextWithConnection(ctx, di.GORM())); err != nil && !errors.Is(err, context.Canceled) {
+		log.WithError(err).Error("outbox relay stopped")
+	}
 }
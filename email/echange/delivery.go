@@ -0,0 +1,68 @@
+package echange
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultDeliveryRetention outlasts all retries of the message with the default consumer settings
+const DefaultDeliveryRetention = 24 * time.Hour
+
+// MessageKey identifies the message by its body, retried messages are republished with the same body
+func MessageKey(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+type delivery struct {
+	key  string
+	user uuid.UUID
+}
+
+// DeliveryLog remembers users who have received the message, so that the retry of the message
+// sent to many users does not email again those who already got it. The log is kept in memory,
+// so only a restart of the service between the retries repeats the delivered emails
+type DeliveryLog struct {
+	mu        sync.Mutex
+	retention time.Duration
+	delivered map[delivery]time.Time
+	prunedAt  time.Time
+	now       func() time.Time
+}
+
+func NewDeliveryLog(retention time.Duration) *DeliveryLog {
+	return &DeliveryLog{retention: retention, delivered: map[delivery]time.Time{}, now: time.Now}
+}
+
+// Add records the message delivered to the user, deliveries older than the retention are forgotten once a minute
+func (l *DeliveryLog) Add(key string, user uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.prunedAt) > time.Minute {
+		for d, at := range l.delivered {
+			if now.Sub(at) > l.retention {
+				delete(l.delivered, d)
+			}
+		}
+
+		l.prunedAt = now
+	}
+
+	l.delivered[delivery{key: key, user: user}] = now
+}
+
+func (l *DeliveryLog) Delivered(key string, user uuid.UUID) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	at, ok := l.delivered[delivery{key: key, user: user}]
+
+	return ok && l.now().Sub(at) <= l.retention
+}
@@ -1,13 +1,64 @@
 package echange
 
-import log "github.com/sirupsen/logrus"
+import (
+	"context"
+	"encoding/json"
+	"errors"
 
-type EventQueryHandler struct{}
+	"event-service/email"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/exchange"
+	"event-service/internal/exchange/event"
 
-func NewEventQueryHandler() *EventQueryHandler {
-	return &EventQueryHandler{}
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrMessageWithoutEvent = errors.New("event update message without event")
+
+type Notifier interface {
+	Notify(ctx context.Context, t email.Template, user uuid.UUID, e *aggregate.Event) error
+}
+
+// EventQueryHandler emails participants of the updated event
+type EventQueryHandler struct {
+	notifier  Notifier
+	delivered *DeliveryLog
 }
 
-func (h *EventQueryHandler) Handle(data []byte) {
-	log.Printf("Event handled: %s", data)
+func NewEventQueryHandler(notifier Notifier) *EventQueryHandler {
+	return &EventQueryHandler{notifier: notifier, delivered: NewDeliveryLog(DefaultDeliveryRetention)}
+}
+
+// Handle emails all participants, failed emails make the message retried, malformed messages are not retried.
+// The retried message is emailed only to the participants who have not received it yet
+func (h *EventQueryHandler) Handle(ctx context.Context, data []byte) error {
+	msg := eventupdate.Message{}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return exchange.Permanent(err)
+	}
+
+	if msg.Event == nil || msg.Event.Event == nil {
+		return exchange.Permanent(ErrMessageWithoutEvent)
+	}
+
+	key := MessageKey(data)
+
+	var errs []error
+	for _, participant := range msg.Event.Participants {
+		if h.delivered.Delivered(key, participant) {
+			continue
+		}
+
+		if err := h.notifier.Notify(ctx, email.TemplateUpdated, participant, msg.Event); err != nil {
+			log.WithError(err).WithField("user", participant).Error("cannot send event update email")
+			errs = append(errs, err)
+
+			continue
+		}
+
+		h.delivered.Add(key, participant)
+	}
+
+	return errors.Join(errs...)
 }
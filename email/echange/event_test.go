@@ -0,0 +1,124 @@
+package echange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"event-service/email"
+	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/exchange"
+	"event-service/internal/exchange/event"
+
+	"github.com/google/uuid"
+)
+
+type notification struct {
+	template email.Template
+	user     uuid.UUID
+	start    time.Time
+}
+
+type notifierMock struct {
+	sent    []notification
+	failing map[uuid.UUID]bool
+}
+
+func (n *notifierMock) Notify(_ context.Context, t email.Template, user uuid.UUID, e *aggregate.Event) error {
+	if n.failing[user] {
+		return errors.New("mailbox unavailable")
+	}
+
+	n.sent = append(n.sent, notification{template: t, user: user, start: e.EventPeriod.Start()})
+
+	return nil
+}
+
+func TestEventQueryHandler_Handle(t *testing.T) {
+	start := time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC)
+	period, _ := valueobject.EventPeriod{}.WithStartAndDuration(start, time.Hour)
+	participants := []uuid.UUID{uuid.New(), uuid.New()}
+
+	body, _ := json.Marshal(eventupdate.Message{Event: &aggregate.Event{
+		UserID:       uuid.New(),
+		Event:        &entity.Event{ExternalID: uuid.New(), Name: "meetup"},
+		EventPeriod:  period,
+		Participants: participants,
+	}})
+
+	tests := []struct {
+		name          string
+		data          []byte
+		want          []notification
+		wantPermanent bool
+	}{
+		{
+			name: "participants are notified",
+			data: body,
+			want: []notification{
+				{template: email.TemplateUpdated, user: participants[0], start: start},
+				{template: email.TemplateUpdated, user: participants[1], start: start},
+			},
+		},
+		{
+			name:          "message without event",
+			data:          []byte(`{}`),
+			wantPermanent: true,
+		},
+		{
+			name:          "invalid message",
+			data:          []byte(`not json`),
+			wantPermanent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &notifierMock{}
+			err := NewEventQueryHandler(notifier).Handle(context.Background(), tt.data)
+			if errors.Is(err, exchange.ErrPermanent) != tt.wantPermanent {
+				t.Errorf("Handle() error = %v, want permanent %v", err, tt.wantPermanent)
+			}
+
+			if !reflect.DeepEqual(notifier.sent, tt.want) {
+				t.Errorf("Handle() sent = %v, want %v", notifier.sent, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventQueryHandler_HandleRetryOfFailedRecipient(t *testing.T) {
+	period, _ := valueobject.EventPeriod{}.WithStartAndDuration(time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC), time.Hour)
+	participants := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	body, _ := json.Marshal(eventupdate.Message{Event: &aggregate.Event{
+		UserID:       uuid.New(),
+		Event:        &entity.Event{ExternalID: uuid.New(), Name: "meetup"},
+		EventPeriod:  period,
+		Participants: participants,
+	}})
+
+	notifier := &notifierMock{failing: map[uuid.UUID]bool{participants[1]: true}}
+	handler := NewEventQueryHandler(notifier)
+
+	if err := handler.Handle(context.Background(), body); err == nil || errors.Is(err, exchange.ErrPermanent) {
+		t.Fatalf("Handle() error = %v, want retried failure", err)
+	}
+
+	if len(notifier.sent) != 2 {
+		t.Fatalf("Handle() sent %d emails, want 2 despite the failed recipient", len(notifier.sent))
+	}
+
+	notifier.failing = nil
+	if err := handler.Handle(context.Background(), body); err != nil {
+		t.Fatalf("Handle() of the retry error = %v", err)
+	}
+
+	if len(notifier.sent) != 3 || notifier.sent[2].user != participants[1] {
+		t.Errorf("Handle() of the retry sent = %v, want only the failed recipient", notifier.sent[2:])
+	}
+}
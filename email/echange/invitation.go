@@ -0,0 +1,76 @@
+package echange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"event-service/email"
+	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/exchange"
+	"event-service/internal/exchange/eventinvitation"
+)
+
+var ErrUnknownSchemaVersion = errors.New("unknown invitation message schema version")
+
+// invitationTemplates maps the invitation transitions to the emails sent to the invited user,
+// the other transitions are acknowledged without any email
+var invitationTemplates = map[eventinvitation.MessageType]email.Template{
+	eventinvitation.UserInvited:  email.TemplateInvited,
+	eventinvitation.UserJoined:   email.TemplateJoined,
+	eventinvitation.UserAccepted: email.TemplateAccepted,
+	eventinvitation.UserRevoked:  email.TemplateRevoked,
+}
+
+// InvitationHandler emails the user whose invitation has changed, messages are published only
+// after the change is committed, so no email describes a rolled back change
+type InvitationHandler struct {
+	notifier Notifier
+}
+
+func NewInvitationHandler(notifier Notifier) *InvitationHandler {
+	return &InvitationHandler{notifier: notifier}
+}
+
+// Handle sends a single email, malformed messages and messages of unknown schema version are not retried
+func (h *InvitationHandler) Handle(ctx context.Context, data []byte) error {
+	msg := eventinvitation.Message{}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return exchange.Permanent(err)
+	}
+
+	if msg.Version != eventinvitation.SchemaVersion {
+		return exchange.Permanent(fmt.Errorf("%w: %d", ErrUnknownSchemaVersion, msg.Version))
+	}
+
+	template, ok := invitationTemplates[msg.Type]
+	if !ok {
+		return nil
+	}
+
+	return h.notifier.Notify(ctx, template, msg.UserID, eventOfInvitationMessage(msg))
+}
+
+// eventOfInvitationMessage restores the part of the event described in the emails
+func eventOfInvitationMessage(msg eventinvitation.Message) *aggregate.Event {
+	e := &aggregate.Event{
+		UserID: msg.OrganizerID,
+		Event: &entity.Event{
+			ExternalID:  msg.EventID,
+			Name:        msg.EventName,
+			Description: msg.EventDescription,
+		},
+	}
+
+	// messages published before the event details were added have no period
+	e.EventPeriod, _ = e.EventPeriod.WithStartAndEndDate(msg.EventStart, msg.EventEnd)
+
+	if msg.EventLocation != nil {
+		e.Location = &aggregate.Location{Spot: valueobject.NewLocation(msg.EventLocation.Latitude, msg.EventLocation.Longitude)}
+	}
+
+	return e
+}
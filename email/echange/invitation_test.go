@@ -0,0 +1,85 @@
+package echange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"event-service/email"
+	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/exchange"
+	"event-service/internal/exchange/eventinvitation"
+
+	"github.com/google/uuid"
+)
+
+func TestInvitationHandler_Handle(t *testing.T) {
+	start := time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC)
+	period, _ := valueobject.EventPeriod{}.WithStartAndDuration(start, time.Hour)
+	user := uuid.New()
+
+	ia := aggregate.Invitation{
+		Event: &aggregate.Event{
+			UserID:      uuid.New(),
+			Event:       &entity.Event{ExternalID: uuid.New(), Name: "meetup"},
+			EventPeriod: period,
+		},
+		InvitedUser: user,
+	}
+
+	messageOf := func(messageType eventinvitation.MessageType) []byte {
+		body, _ := json.Marshal(eventinvitation.NewMessage(messageType, ia, uuid.Nil, start))
+
+		return body
+	}
+
+	tests := []struct {
+		name          string
+		data          []byte
+		want          []notification
+		wantPermanent bool
+	}{
+		{
+			name: "invited user is notified",
+			data: messageOf(eventinvitation.UserInvited),
+			want: []notification{{template: email.TemplateInvited, user: user, start: start}},
+		},
+		{
+			name: "revoked invitation is notified",
+			data: messageOf(eventinvitation.UserRevoked),
+			want: []notification{{template: email.TemplateRevoked, user: user, start: start}},
+		},
+		{
+			name: "transition without email",
+			data: messageOf(eventinvitation.UserDeclined),
+		},
+		{
+			name:          "unknown schema version",
+			data:          []byte(`{"type":"invitation.invited","version":2}`),
+			wantPermanent: true,
+		},
+		{
+			name:          "invalid message",
+			data:          []byte(`not json`),
+			wantPermanent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &notifierMock{}
+			err := NewInvitationHandler(notifier).Handle(context.Background(), tt.data)
+			if errors.Is(err, exchange.ErrPermanent) != tt.wantPermanent {
+				t.Errorf("Handle() error = %v, want permanent %v", err, tt.wantPermanent)
+			}
+
+			if !reflect.DeepEqual(notifier.sent, tt.want) {
+				t.Errorf("Handle() sent = %v, want %v", notifier.sent, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package email
+
+import (
+	"context"
+	"strings"
+
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/services"
+
+	"github.com/google/uuid"
+)
+
+var ServiceName = "mailer"
+
+// AddressBook resolves email address of the user, the service knows users only by their ids
+type AddressBook interface {
+	Address(ctx context.Context, user uuid.UUID) (string, error)
+}
+
+// PatternAddressBook builds the address by replacing {user} placeholder of the pattern with the user id
+type PatternAddressBook string
+
+func (p PatternAddressBook) Address(_ context.Context, user uuid.UUID) (string, error) {
+	return strings.ReplaceAll(string(p), "{user}", user.String()), nil
+}
+
+type Configuration func(*Mailer) error
+
+func WithSender(sender Sender) Configuration {
+	return func(m *Mailer) error {
+		m.sender = sender
+
+		return nil
+	}
+}
+
+func WithAddressBook(addresses AddressBook) Configuration {
+	return func(m *Mailer) error {
+		m.addresses = addresses
+
+		return nil
+	}
+}
+
+func WithFrom(from string) Configuration {
+	return func(m *Mailer) error {
+		m.from = from
+
+		return nil
+	}
+}
+
+// WithBaseURL sets public url of the api, emails link to the calendar export of the event when it is set
+func WithBaseURL(url string) Configuration {
+	return func(m *Mailer) error {
+		m.baseURL = url
+
+		return nil
+	}
+}
+
+// Mailer renders emails about the event and sends them to the users
+type Mailer struct {
+	sender    Sender
+	addresses AddressBook
+	renderer  *Renderer
+	from      string
+	baseURL   string
+}
+
+func NewMailer(configuration ...Configuration) (*Mailer, error) {
+	renderer, err := NewRenderer()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Mailer{renderer: renderer}
+
+	for _, cfg := range configuration {
+		if err := cfg(m); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.validateRequiredResources(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m Mailer) validateRequiredResources() error {
+	if m.sender == nil {
+		return services.NewErrResourceIsRequired(ServiceName, "sender")
+	}
+
+	if m.addresses == nil {
+		return services.NewErrResourceIsRequired(ServiceName, "address book")
+	}
+
+	if m.from == "" {
+		return services.NewErrResourceIsRequired(ServiceName, "from address")
+	}
+
+	return nil
+}
+
+// Notify sends email of the given template about the event to the user
+func (m Mailer) Notify(ctx context.Context, t Template, user uuid.UUID, e *aggregate.Event) error {
+	msg, err := m.renderer.Render(t, NewData(e, m.baseURL))
+	if err != nil {
+		return err
+	}
+
+	to, err := m.addresses.Address(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	msg.From, msg.To = m.from, []string{to}
+
+	return m.sender.Send(ctx, msg)
+}
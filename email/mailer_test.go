@@ -0,0 +1,194 @@
+package email
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event/aggregate"
+
+	"github.com/google/uuid"
+)
+
+func newEventMock() *aggregate.Event {
+	period, _ := valueobject.EventPeriod{}.WithStartAndDuration(time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC), 2*time.Hour)
+
+	return &aggregate.Event{
+		UserID: uuid.New(),
+		Event: &entity.Event{
+			ExternalID:  uuid.MustParse("0b9e4a52-3c55-4d6f-8a7b-1c2d3e4f5a6b"),
+			Name:        "Paris <meetup>",
+			Description: "Talks and beer",
+			Status:      entity.EventStatusScheduled,
+		},
+		Location:    &aggregate.Location{Spot: valueobject.NewLocation(48.8584, 2.2945)},
+		EventPeriod: period,
+	}
+}
+
+func TestMailer_Notify(t *testing.T) {
+	user := uuid.MustParse("c5a1f0e9-7b6a-4d3c-8e2f-1a0b9c8d7e6f")
+
+	tests := []struct {
+		name        string
+		template    Template
+		event       func() *aggregate.Event
+		wantSubject string
+		wantText    []string
+		wantHTML    []string
+		wantErr     bool
+	}{
+		{
+			name:        "invitation",
+			template:    TemplateInvited,
+			event:       newEventMock,
+			wantSubject: "You are invited to Paris <meetup>",
+			wantText: []string{
+				"accept the invitation",
+				"When:  Tue, 01 Jan 2030 18:00 UTC - Tue, 01 Jan 2030 20:00 UTC",
+				"Where: 48.85840, 2.29450",
+				"http://localhost:8080/events/0b9e4a52-3c55-4d6f-8a7b-1c2d3e4f5a6b.ics",
+			},
+			wantHTML: []string{"<h2>Paris &lt;meetup&gt;</h2>", "<p>Talks and beer</p>"},
+		},
+		{
+			name:        "joined event",
+			template:    TemplateJoined,
+			event:       newEventMock,
+			wantSubject: "You joined Paris <meetup>",
+			wantText:    []string{"You have joined the event"},
+		},
+		{
+			name:        "accepted invitation",
+			template:    TemplateAccepted,
+			event:       newEventMock,
+			wantSubject: "Invitation to Paris <meetup> accepted",
+		},
+		{
+			name:        "revoked invitation",
+			template:    TemplateRevoked,
+			event:       newEventMock,
+			wantSubject: "Invitation to Paris <meetup> revoked",
+			wantText:    []string{"withdrawn your invitation"},
+		},
+		{
+			name:        "updated event",
+			template:    TemplateUpdated,
+			event:       newEventMock,
+			wantSubject: "Paris <meetup> has been updated",
+		},
+		{
+			name:     "cancelled event",
+			template: TemplateUpdated,
+			event: func() *aggregate.Event {
+				e := newEventMock()
+				_ = e.Cancel("venue closed")
+
+				return e
+			},
+			wantSubject: "Paris <meetup> has been cancelled",
+			wantText:    []string{"Reason: venue closed"},
+		},
+		{
+			name:     "unknown template",
+			template: Template("unknown"),
+			event:    newEventMock,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := NewInMemorySender()
+			m, _ := NewMailer(
+				WithSender(sender),
+				WithAddressBook(PatternAddressBook("{user}@example.com")),
+				WithFrom("events@example.com"),
+				WithBaseURL("http://localhost:8080/"),
+			)
+
+			if err := m.Notify(context.Background(), tt.template, user, tt.event()); (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			messages := sender.Messages()
+			if len(messages) != 1 {
+				t.Fatalf("Notify() sent %d messages, want 1", len(messages))
+			}
+
+			msg := messages[0]
+			if msg.From != "events@example.com" || len(msg.To) != 1 || msg.To[0] != user.String()+"@example.com" {
+				t.Errorf("Notify() from = %v, to = %v", msg.From, msg.To)
+			}
+
+			if msg.Subject != tt.wantSubject {
+				t.Errorf("Notify() subject = %q, want %q", msg.Subject, tt.wantSubject)
+			}
+
+			for _, want := range tt.wantText {
+				if !strings.Contains(msg.Text, want) {
+					t.Errorf("Notify() text does not contain %q:\n%s", want, msg.Text)
+				}
+			}
+
+			for _, want := range tt.wantHTML {
+				if !strings.Contains(msg.HTML, want) {
+					t.Errorf("Notify() html does not contain %q:\n%s", want, msg.HTML)
+				}
+			}
+		})
+	}
+}
+
+func TestNewMailer_RequiredResources(t *testing.T) {
+	if _, err := NewMailer(WithSender(NewInMemorySender()), WithFrom("events@example.com")); err == nil {
+		t.Errorf("NewMailer() without address book should fail")
+	}
+}
+
+func TestFileSender_Send(t *testing.T) {
+	dir := t.TempDir()
+
+	sender, err := NewFileSender(dir)
+	if err != nil {
+		t.Fatalf("NewFileSender() error = %v", err)
+	}
+
+	if err := sender.Send(context.Background(), Message{
+		From:    "events@example.com",
+		To:      []string{"user@example.com"},
+		Subject: "Zaproszenie na spotkanie",
+		Text:    "plain body",
+		HTML:    "<p>html body</p>",
+	}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("Send() wrote %d files, want 1", len(files))
+	}
+
+	data, _ := os.ReadFile(dir + "/" + files[0].Name())
+	for _, want := range []string{
+		"From: events@example.com\r\n",
+		"To: user@example.com\r\n",
+		"Subject: Zaproszenie na spotkanie\r\n",
+		"Content-Type: multipart/alternative;",
+		"Content-Type: text/plain; charset=utf-8",
+		"plain body",
+		"Content-Type: text/html; charset=utf-8",
+		"<p>html body</p>",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Send() message does not contain %q:\n%s", want, data)
+		}
+	}
+}
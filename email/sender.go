@@ -0,0 +1,130 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a rendered email with plain text and html alternatives
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender delivers rendered messages
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// Bytes returns the message encoded as multipart/alternative MIME document
+func (m Message) Bytes() ([]byte, error) {
+	body := &bytes.Buffer{}
+	parts := multipart.NewWriter(body)
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	msg := &bytes.Buffer{}
+	fmt.Fprintf(msg, "From: %s\r\n", m.From)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// InMemorySender keeps sent messages, it is meant for tests
+type InMemorySender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewInMemorySender() *InMemorySender {
+	return &InMemorySender{}
+}
+
+func (s *InMemorySender) Send(_ context.Context, m Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, m)
+
+	return nil
+}
+
+// Messages returns copy of the sent messages in order of sending
+func (s *InMemorySender) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
+
+// FileSender writes each message as .eml file into the directory, it is meant for local development
+type FileSender struct {
+	directory string
+	mu        sync.Mutex
+	sent      int
+}
+
+func NewFileSender(directory string) (*FileSender, error) {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileSender{directory: directory}, nil
+}
+
+func (s *FileSender) Send(_ context.Context, m Message) error {
+	data, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sent++
+	name := fmt.Sprintf("%s-%04d.eml", time.Now().UTC().Format("20060102T150405.000000000"), s.sent)
+
+	return os.WriteFile(filepath.Join(s.directory, name), data, 0o644)
+}
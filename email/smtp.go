@@ -0,0 +1,44 @@
+package email
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+// SMTPSender delivers messages through the SMTP server, authentication is used when the username is set
+type SMTPSender struct {
+	address string
+	auth    smtp.Auth
+}
+
+type SMTPParameters struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+func NewSMTPSender(p SMTPParameters) *SMTPSender {
+	s := &SMTPSender{address: net.JoinHostPort(p.Host, strconv.Itoa(p.Port))}
+
+	if p.Username != "" {
+		s.auth = smtp.PlainAuth("", p.Username, p.Password, p.Host)
+	}
+
+	return s
+}
+
+func (s SMTPSender) Send(ctx context.Context, m Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(s.address, s.auth, m.From, m.To, data)
+}
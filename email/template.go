@@ -0,0 +1,124 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"event-service/internal/domain/event/aggregate"
+)
+
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+// Template names the kind of the email, each kind has text and html variant in the templates directory
+type Template string
+
+const (
+	TemplateInvited  Template = "invited"
+	TemplateJoined   Template = "joined"
+	TemplateAccepted Template = "accepted"
+	TemplateUpdated  Template = "updated"
+	// TemplateRevoked tells the user the organizer has withdrawn the invitation
+	TemplateRevoked Template = "revoked"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// Data is passed to the templates
+type Data struct {
+	EventID            string
+	Name               string
+	Description        string
+	Start              time.Time
+	End                time.Time
+	Latitude           float64
+	Longitude          float64
+	Cancelled          bool
+	CancellationReason string
+	CalendarURL        string
+}
+
+// NewData describes the event for the templates, calendar url is left empty when the base url is not given
+func NewData(e *aggregate.Event, baseURL string) Data {
+	d := Data{
+		EventID:            e.Event.ExternalID.String(),
+		Name:               e.Event.Name,
+		Description:        e.Event.Description,
+		Start:              e.EventPeriod.Start(),
+		End:                e.EventPeriod.End(),
+		Cancelled:          e.IsCancelled(),
+		CancellationReason: e.Event.CancellationReason,
+	}
+
+	if e.Location != nil {
+		d.Latitude, d.Longitude = e.Location.Spot.Lat(), e.Location.Spot.Long()
+	}
+
+	if baseURL != "" {
+		d.CalendarURL = strings.TrimSuffix(baseURL, "/") + "/events/" + d.EventID + ".ics"
+	}
+
+	return d
+}
+
+// Renderer renders subject, text and html body of the emails
+type Renderer struct {
+	text map[Template]*texttemplate.Template
+	html map[Template]*htmltemplate.Template
+}
+
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{
+		text: map[Template]*texttemplate.Template{},
+		html: map[Template]*htmltemplate.Template{},
+	}
+
+	for _, t := range []Template{TemplateInvited, TemplateJoined, TemplateAccepted, TemplateUpdated, TemplateRevoked} {
+		text, err := texttemplate.ParseFS(templatesFS, "templates/layout.txt", "templates/"+string(t)+".txt")
+		if err != nil {
+			return nil, err
+		}
+
+		html, err := htmltemplate.ParseFS(templatesFS, "templates/layout.html", "templates/"+string(t)+".html")
+		if err != nil {
+			return nil, err
+		}
+
+		r.text[t], r.html[t] = text, html
+	}
+
+	return r, nil
+}
+
+// Render returns message without sender and recipients
+func (r Renderer) Render(t Template, data Data) (Message, error) {
+	text, html := r.text[t], r.html[t]
+	if text == nil || html == nil {
+		return Message{}, ErrUnknownTemplate
+	}
+
+	subject, textBody, htmlBody := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+
+	if err := text.ExecuteTemplate(subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+
+	if err := text.ExecuteTemplate(textBody, "layout", data); err != nil {
+		return Message{}, err
+	}
+
+	if err := html.ExecuteTemplate(htmlBody, "layout", data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    textBody.String(),
+		HTML:    htmlBody.String(),
+	}, nil
+}
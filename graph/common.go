@@ -1,5 +1,7 @@
 package graph
 
+import "context"
+
 func getValueIfNotNull[K comparable](pointer *K) (value K) {
 	if pointer != nil {
 		return *pointer
@@ -7,3 +9,44 @@ func getValueIfNotNull[K comparable](pointer *K) (value K) {
 
 	return
 }
+
+func getValuesIfNotNull[K comparable](pointers []*K) []K {
+	values := make([]K, 0, len(pointers))
+	for _, pointer := range pointers {
+		if pointer != nil {
+			values = append(values, *pointer)
+		}
+	}
+
+	return values
+}
+
+func getPointers[K any](values []K) []*K {
+	copied := append([]K{}, values...)
+
+	pointers := make([]*K, len(copied))
+	for i := range copied {
+		pointers[i] = &copied[i]
+	}
+
+	return pointers
+}
+
+// convertStream passes values of the subscription converted to the models, until the stream or the context ends
+func convertStream[T, M any](ctx context.Context, values <-chan T, convert func(T) M) <-chan M {
+	models := make(chan M)
+
+	go func() {
+		defer close(models)
+
+		for v := range values {
+			select {
+			case models <- convert(v):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return models
+}
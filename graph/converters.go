@@ -1,17 +1,26 @@
 package graph
 
 import (
+	"errors"
 	"time"
 
 	"event-service/graph/model"
+	"event-service/internal/domain/common/validation"
+	"event-service/internal/domain/event"
 	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/valueobject"
+	"event-service/internal/exchange/eventinvitation"
 	"event-service/internal/services/eventcreator"
+	"event-service/internal/services/eventfinder"
+	"event-service/internal/services/eventsubscription"
 	"event-service/internal/services/eventupdater"
+
+	"github.com/google/uuid"
+	"github.com/markphelps/optional"
 )
 
 func ConvertNewEventToRequest(e model.NewEvent) eventcreator.Request {
-	return eventcreator.Request{
-		User:                e.User,
+	r := eventcreator.Request{
 		Name:                e.Name,
 		Description:         getValueIfNotNull(e.Description),
 		Capacity:            e.Capacity,
@@ -22,6 +31,13 @@ func ConvertNewEventToRequest(e model.NewEvent) eventcreator.Request {
 		DateRegistrationEnd: getValueIfNotNull(e.RegistrationEndDate),
 		Public:              e.Public,
 	}
+
+	if e.Recurrence != nil {
+		r.RecurrenceRule = e.Recurrence.Rule
+		r.RecurrenceExceptions = getValuesIfNotNull(e.Recurrence.Exceptions)
+	}
+
+	return r
 }
 
 func ConvertEventEntryToModel(entry *aggregate.Event) *model.Event {
@@ -37,6 +53,22 @@ func ConvertEventEntryToModel(entry *aggregate.Event) *model.Event {
 		RegistrationStartDate: entry.RegistrationPeriod.Start(),
 		RegistrationEndDate:   entry.RegistrationPeriod.End(),
 		Public:                entry.Event.Public,
+		Status:                model.EventStatusScheduled,
+		RegistrationOpen:      entry.RegistrationOpen(),
+		CancelledAt:           entry.Event.CancelledAt,
+		Version:               int(entry.Version),
+	}
+
+	if entry.IsRecurring() {
+		e.Recurrence = &model.Recurrence{
+			Rule:       entry.Recurrence.Rule(),
+			Exceptions: getPointers(entry.Recurrence.Exceptions()),
+		}
+	}
+
+	if entry.IsCancelled() {
+		e.Status = model.EventStatusCancelled
+		e.CancellationReason = &entry.Event.CancellationReason
 	}
 
 	if l := entry.Location; l != nil {
@@ -44,24 +76,130 @@ func ConvertEventEntryToModel(entry *aggregate.Event) *model.Event {
 		e.Longitude = l.Spot.Long()
 	}
 
-	if entry.ParticipantsNumber() > 0 {
-		for _, p := range entry.Participants {
-			e.Participants = append(e.Participants, &model.Participant{User: p.String()})
+	return e
+}
+
+var participantStatuses = map[aggregate.InvitationStatus]model.ParticipantStatus{
+	aggregate.InvitationInvited:    model.ParticipantStatusInvited,
+	aggregate.InvitationAccepted:   model.ParticipantStatusAccepted,
+	aggregate.InvitationDeclined:   model.ParticipantStatusDeclined,
+	aggregate.InvitationWaitlisted: model.ParticipantStatusWaitlisted,
+	aggregate.InvitationRemoved:    model.ParticipantStatusRemoved,
+	aggregate.InvitationRevoked:    model.ParticipantStatusRevoked,
+}
+
+func ConvertParticipantsRequest(status *model.ParticipantStatus, first *int, after *string) eventfinder.ParticipantsRequest {
+	r := eventfinder.ParticipantsRequest{First: first, After: getValueIfNotNull(after)}
+
+	if status == nil {
+		return r
+	}
+
+	for s, ms := range participantStatuses {
+		if *status == ms {
+			r.Status = &s
+
+			break
 		}
 	}
 
-	return e
+	return r
+}
+
+// ConvertParticipantsToModel converts accepted invitations followed by the waitlisted ones
+func ConvertParticipantsToModel(invitations []*aggregate.Invitation) []*model.Participant {
+	var participants []*model.Participant
+
+	for _, ia := range invitations {
+		participants = append(participants, ConvertInvitationToModel(ia, event.WaitlistPosition(invitations, ia.InvitedUser)))
+	}
+
+	return participants
+}
+
+// ConvertInvitationToModel converts the invitation, the position is set only for waitlisted users
+func ConvertInvitationToModel(ia *aggregate.Invitation, waitlistPosition int) *model.Participant {
+	p := &model.Participant{
+		User:       ia.InvitedUser.String(),
+		Status:     participantStatuses[ia.Status()],
+		InvitedAt:  ia.InvitedAt,
+		AcceptedAt: ia.AcceptedAt,
+	}
+
+	if ia.IsWaitlisted() && waitlistPosition > 0 {
+		p.WaitlistPosition = &waitlistPosition
+	}
+
+	if ia.InvitedBy != uuid.Nil {
+		invitedBy := ia.InvitedBy.String()
+		p.InvitedBy = &invitedBy
+	}
+
+	return p
+}
+
+func ConvertParticipantPageToModel(page event.ParticipantPage) *model.ParticipantConnection {
+	connection := &model.ParticipantConnection{
+		Edges:      make([]*model.ParticipantEdge, len(page.Edges)),
+		PageInfo:   &model.PageInfo{HasNextPage: page.HasNextPage, HasPreviousPage: page.HasPreviousPage},
+		TotalCount: int(page.TotalCount),
+	}
+
+	for i, edge := range page.Edges {
+		connection.Edges[i] = &model.ParticipantEdge{
+			Cursor: edge.Cursor.Encode(),
+			Node:   ConvertInvitationToModel(edge.Invitation, edge.WaitlistPosition),
+		}
+	}
+
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.StartCursor = &connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = &connection.Edges[len(connection.Edges)-1].Cursor
+	}
+
+	return connection
+}
+
+func ConvertOccurrencesToModel(occurrences []aggregate.Occurrence) []*model.Occurrence {
+	items := make([]*model.Occurrence, len(occurrences))
+	for i, o := range occurrences {
+		items[i] = &model.Occurrence{
+			OriginalStartDate: o.OriginalStart,
+			StartDate:         o.Period.Start(),
+			EndDate:           o.Period.End(),
+			Moved:             o.Moved,
+		}
+	}
+
+	return items
 }
 
 func ConvertEventToUpdateRequest(e model.UpdateEvent) eventupdater.Request {
-	r := eventupdater.Request{
-		ID:          e.ID,
-		Name:        getValueIfNotNull(e.Name),
-		Description: getValueIfNotNull(e.Description),
-		Capacity:    getValueIfNotNull(e.Capacity),
-		Longitude:   getValueIfNotNull(e.Longitude),
-		Latitude:    getValueIfNotNull(e.Latitude),
-		Public:      e.Public,
+	r := eventupdater.Request{ID: e.ID, Version: uint(e.Version)}
+
+	// the fields sent explicitly are updated even when empty, e.g. the blank description clears it
+	if e.Name != nil {
+		r.Name = optional.NewString(*e.Name)
+	}
+
+	if e.Description != nil {
+		r.Description = optional.NewString(*e.Description)
+	}
+
+	if e.Capacity != nil {
+		r.Capacity = optional.NewInt(*e.Capacity)
+	}
+
+	if e.Longitude != nil {
+		r.Longitude = optional.NewFloat64(*e.Longitude)
+	}
+
+	if e.Latitude != nil {
+		r.Latitude = optional.NewFloat64(*e.Latitude)
+	}
+
+	if e.Public != nil {
+		r.Public = optional.NewBool(*e.Public)
 	}
 
 	if e.EventDate != nil {
@@ -74,5 +212,159 @@ func ConvertEventToUpdateRequest(e model.UpdateEvent) eventupdater.Request {
 		r.DateRegistrationEnd = &e.RegistrationDate.End
 	}
 
+	if e.Recurrence != nil {
+		r.Recurrence = &eventupdater.RecurrenceRequest{
+			Rule:       e.Recurrence.Rule,
+			Exceptions: getValuesIfNotNull(e.Recurrence.Exceptions),
+		}
+	}
+
+	if o := e.Occurrence; o != nil {
+		r.Occurrence = &eventupdater.OccurrenceRequest{
+			OriginalStart: o.OriginalStartDate,
+			Cancel:        getValueIfNotNull(o.Cancelled),
+		}
+
+		if o.EventDate != nil {
+			r.Occurrence.DateStart = &o.EventDate.Start
+			r.Occurrence.DateEnd = &o.EventDate.End
+		}
+	}
+
+	return r
+}
+
+var eventOrderFields = map[model.EventOrderField]valueobject.OrderField{
+	model.EventOrderFieldStartDate: valueobject.OrderByStartDate,
+	model.EventOrderFieldCreatedAt: valueobject.OrderByCreatedAt,
+	model.EventOrderFieldDistance:  valueobject.OrderByDistance,
+	model.EventOrderFieldName:      valueobject.OrderByName,
+}
+
+func ConvertEventOrderToRequest(o *model.EventOrder) *eventfinder.OrderRequest {
+	if o == nil {
+		return nil
+	}
+
+	r := &eventfinder.OrderRequest{
+		Field:     eventOrderFields[o.Field],
+		Direction: valueobject.OrderAscending,
+	}
+
+	if o.Direction != nil && *o.Direction == model.OrderDirectionDesc {
+		r.Direction = valueobject.OrderDescending
+	}
+
 	return r
 }
+
+var (
+	ErrValueRequired    = errors.New("value is required")
+	ErrDurationPositive = errors.New("duration must be positive")
+)
+
+// ConvertLocationToRequest requires every field of the location, the distance is in kilometers
+func ConvertLocationToRequest(l *model.Location) (*eventfinder.LocationRequest, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	var errs validation.Errors
+
+	if l.Latitude == nil {
+		errs = append(errs, validation.NewFieldError("location.latitude", ErrValueRequired))
+	}
+
+	if l.Longitude == nil {
+		errs = append(errs, validation.NewFieldError("location.longitude", ErrValueRequired))
+	}
+
+	if l.Distance == nil {
+		errs = append(errs, validation.NewFieldError("location.distance", ErrValueRequired))
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return &eventfinder.LocationRequest{
+		Latitude:  *l.Latitude,
+		Longitude: *l.Longitude,
+		Distance:  int64(*l.Distance),
+	}, nil
+}
+
+// ConvertUpcomingToRequest requires every field of the upcoming window, the duration is in seconds
+func ConvertUpcomingToRequest(u *model.Upcoming) (*eventfinder.UpcomingEventRequest, error) {
+	if u == nil {
+		return nil, nil
+	}
+
+	var errs validation.Errors
+
+	if u.Date == nil {
+		errs = append(errs, validation.NewFieldError("upcoming.date", ErrValueRequired))
+	}
+
+	switch {
+	case u.Duration == nil:
+		errs = append(errs, validation.NewFieldError("upcoming.duration", ErrValueRequired))
+	case *u.Duration <= 0:
+		errs = append(errs, validation.NewFieldError("upcoming.duration", ErrDurationPositive))
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return &eventfinder.UpcomingEventRequest{
+		Date:     *u.Date,
+		Interval: time.Duration(*u.Duration) * time.Second,
+	}, nil
+}
+
+func ConvertPageToConnection(page event.Page) *model.EventConnection {
+	c := &model.EventConnection{
+		Edges: make([]*model.EventEdge, len(page.Edges)),
+		PageInfo: &model.PageInfo{
+			HasNextPage:     page.HasNextPage,
+			HasPreviousPage: page.HasPreviousPage,
+		},
+		TotalCount: int(page.TotalCount),
+	}
+
+	for i, edge := range page.Edges {
+		c.Edges[i] = &model.EventEdge{
+			Cursor: edge.Cursor.Encode(),
+			Node:   ConvertEventEntryToModel(edge.Event),
+		}
+	}
+
+	if len(c.Edges) > 0 {
+		c.PageInfo.StartCursor = &c.Edges[0].Cursor
+		c.PageInfo.EndCursor = &c.Edges[len(c.Edges)-1].Cursor
+	}
+
+	return c
+}
+
+var invitationChangeTypes = map[eventinvitation.MessageType]model.InvitationChangeType{
+	eventinvitation.UserInvited:    model.InvitationChangeTypeInvited,
+	eventinvitation.UserAccepted:   model.InvitationChangeTypeAccepted,
+	eventinvitation.UserJoined:     model.InvitationChangeTypeJoined,
+	eventinvitation.UserRemoved:    model.InvitationChangeTypeRemoved,
+	eventinvitation.UserWaitlisted: model.InvitationChangeTypeWaitlisted,
+	eventinvitation.UserPromoted:   model.InvitationChangeTypePromoted,
+	eventinvitation.UserDeclined:   model.InvitationChangeTypeDeclined,
+	eventinvitation.UserRevoked:    model.InvitationChangeTypeRevoked,
+	eventinvitation.UserLeft:       model.InvitationChangeTypeLeft,
+}
+
+func ConvertChangeToModel(c eventsubscription.Change) *model.InvitationChange {
+	return &model.InvitationChange{
+		Type:       invitationChangeTypes[c.Type],
+		User:       c.User.String(),
+		Waitlisted: c.Waitlisted,
+		Event:      ConvertEventEntryToModel(c.Event),
+	}
+}
@@ -0,0 +1,187 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"event-service/internal/auth"
+	"event-service/internal/dataloader"
+	"event-service/internal/domain/common/validation"
+	commonvalueobject "event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
+	"event-service/internal/domain/event/valueobject"
+	"event-service/internal/services"
+	"event-service/internal/services/eventupdater"
+	"event-service/internal/services/invitation"
+
+	"github.com/99designs/gqlgen/graphql"
+	log "github.com/sirupsen/logrus"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+type ErrorCode string
+
+const (
+	ErrorCodeUnauthenticated    ErrorCode = "UNAUTHENTICATED"
+	ErrorCodeForbidden          ErrorCode = "FORBIDDEN"
+	ErrorCodeNotFound           ErrorCode = "NOT_FOUND"
+	ErrorCodeValidation         ErrorCode = "VALIDATION"
+	ErrorCodeConflict           ErrorCode = "CONFLICT"
+	ErrorCodeCapacityFull       ErrorCode = "CAPACITY_FULL"
+	ErrorCodeRegistrationClosed ErrorCode = "REGISTRATION_CLOSED"
+	ErrorCodeInternal           ErrorCode = "INTERNAL"
+)
+
+const (
+	notFoundMessage = "event not found"
+	internalMessage = "internal server error"
+)
+
+// knownError maps the domain error to its code, the field is reported for validation errors of a single input field
+type knownError struct {
+	err   error
+	code  ErrorCode
+	field string
+}
+
+// knownErrors are checked in order, so the more specific errors go first
+var knownErrors = []knownError{
+	{err: auth.ErrUnauthenticated, code: ErrorCodeUnauthenticated},
+	{err: policy.ErrForbidden, code: ErrorCodeForbidden},
+	{err: invitation.ErrEventIsNotPublic, code: ErrorCodeForbidden},
+
+	{err: invitation.ErrInvitationNotFound, code: ErrorCodeNotFound},
+	{err: invitation.ErrParticipantNotFound, code: ErrorCodeNotFound},
+	{err: aggregate.ErrOccurrenceNotFound, code: ErrorCodeNotFound, field: "occurrences"},
+
+	{err: aggregate.ErrCapacityFull, code: ErrorCodeCapacityFull},
+	{err: aggregate.ErrRegistrationClosed, code: ErrorCodeRegistrationClosed},
+
+	{err: invitation.ErrInvitationAlreadyExists, code: ErrorCodeConflict},
+	{err: aggregate.ErrInvitationAlreadyAccepted, code: ErrorCodeConflict},
+	{err: aggregate.ErrAlreadyWaitlisted, code: ErrorCodeConflict},
+	{err: aggregate.ErrNotWaitlisted, code: ErrorCodeConflict},
+	{err: aggregate.ErrInvitationRemoved, code: ErrorCodeConflict},
+	{err: aggregate.ErrInvitationRevoked, code: ErrorCodeConflict},
+	{err: aggregate.ErrInvitationNotPending, code: ErrorCodeConflict},
+	{err: invitation.ErrInvitationDeclined, code: ErrorCodeConflict},
+	{err: aggregate.ErrEventCancelled, code: ErrorCodeConflict},
+	{err: aggregate.ErrVersionConflict, code: ErrorCodeConflict},
+
+	{err: invitation.ErrInvalidUserID, code: ErrorCodeValidation, field: "user"},
+	{err: invitation.ErrInvalidEventID, code: ErrorCodeValidation, field: "event"},
+	{err: services.ErrInvalidID, code: ErrorCodeValidation, field: "id"},
+	{err: aggregate.ErrUserIDRequired, code: ErrorCodeValidation, field: "user"},
+	{err: aggregate.ErrInvitedUserIDRequired, code: ErrorCodeValidation, field: "user"},
+	{err: aggregate.ErrEventNameRequired, code: ErrorCodeValidation, field: "name"},
+	{err: aggregate.ErrEventNotRecurring, code: ErrorCodeValidation, field: "occurrences"},
+	{err: eventupdater.ErrEmptyOccurrenceOverride, code: ErrorCodeValidation, field: "occurrences"},
+	{err: commonvalueobject.ErrDurationRequired, code: ErrorCodeValidation, field: "duration"},
+	{err: commonvalueobject.ErrPeriodEmptyDates, code: ErrorCodeValidation, field: "startDate"},
+	{err: commonvalueobject.ErrPeriodInvalidDates, code: ErrorCodeValidation, field: "startDate"},
+	{err: commonvalueobject.ErrInvalidRecurrenceRule, code: ErrorCodeValidation, field: "recurrence.rule"},
+	{err: valueobject.ErrFirstAndLastCombined, code: ErrorCodeValidation, field: "last"},
+	{err: valueobject.ErrInvalidPageSize, code: ErrorCodeValidation, field: "first"},
+	{err: valueobject.ErrDistanceOrderWithoutOrigin, code: ErrorCodeValidation, field: "orderBy"},
+}
+
+// FieldError is a single invalid input field listed in the fields extension of the validation error
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ErrorPresenter adds machine readable code to the extensions of the errors and hides the internal ones,
+// errors of unknown cause are logged and reported as INTERNAL without details
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	// errors of the query parsing and input coercion are reported by gqlgen as they are
+	var reported *gqlerror.Error
+	if errors.As(err, &reported) && reported.Unwrap() == nil {
+		return gqlErr
+	}
+
+	code, message, fields := classify(err)
+	if field, ok := inputField(ctx, gqlErr.Path); ok && code == ErrorCodeInternal {
+		code, message = ErrorCodeValidation, validation.ErrInvalid.Error()
+		fields = []FieldError{{Field: field, Message: gqlErr.Unwrap().Error()}}
+	}
+
+	if code == ErrorCodeInternal {
+		log.WithContext(ctx).WithError(err).WithField("path", gqlErr.Path.String()).Error("graphql request failed")
+	}
+
+	gqlErr.Message = message
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]interface{}{}
+	}
+
+	gqlErr.Extensions["code"] = code
+	if len(fields) > 0 {
+		gqlErr.Extensions["fields"] = fields
+	}
+
+	return gqlErr
+}
+
+// classify returns the code of the error with the message that is safe to be shown to the client
+func classify(err error) (ErrorCode, string, []FieldError) {
+	var validationErrs validation.Errors
+	if errors.As(err, &validationErrs) {
+		return ErrorCodeValidation, validation.ErrInvalid.Error(), fieldErrors(validationErrs...)
+	}
+
+	var fieldErr validation.FieldError
+	if errors.As(err, &fieldErr) {
+		return ErrorCodeValidation, validation.ErrInvalid.Error(), fieldErrors(fieldErr)
+	}
+
+	for _, known := range knownErrors {
+		if !errors.Is(err, known.err) {
+			continue
+		}
+
+		if known.field == "" {
+			return known.code, known.err.Error(), nil
+		}
+
+		return known.code, known.err.Error(), []FieldError{{Field: known.field, Message: known.err.Error()}}
+	}
+
+	if errors.Is(err, event.ErrNotFound) || errors.Is(err, dataloader.ErrNotFound) {
+		return ErrorCodeNotFound, notFoundMessage, nil
+	}
+
+	return ErrorCodeInternal, internalMessage, nil
+}
+
+// inputField returns the argument field of the input that could not be coerced, e.g. the malformed date,
+// such errors are reported on the path below the resolved field
+func inputField(ctx context.Context, path ast.Path) (string, bool) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || len(path) <= len(fc.Path()) {
+		return "", false
+	}
+
+	field := path[len(fc.Path()):]
+	if len(field) > 1 {
+		// the input object argument is skipped, so the field matches the fields of the domain errors
+		field = field[1:]
+	}
+
+	return strings.TrimPrefix(field.String(), "."), true
+}
+
+func fieldErrors(errs ...validation.FieldError) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, FieldError{Field: fe.Field, Message: fe.Err.Error()})
+	}
+
+	return fields
+}
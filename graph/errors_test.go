@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"event-service/internal/auth"
+	"event-service/internal/dataloader"
+	"event-service/internal/domain/common/validation"
+	"event-service/internal/domain/event"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/services"
+	"event-service/internal/services/invitation"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestErrorPresenter(t *testing.T) {
+	_, invalidIDErr := services.ParseID("not-an-id")
+
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    ErrorCode
+		wantMessage string
+		wantFields  []FieldError
+	}{
+		{
+			name:        "unauthenticated",
+			err:         auth.ErrUnauthenticated,
+			wantCode:    ErrorCodeUnauthenticated,
+			wantMessage: auth.ErrUnauthenticated.Error(),
+		},
+		{
+			name:        "wrapped event not found hides repository details",
+			err:         pkgerrors.Wrap(pkgerrors.Wrap(event.ErrNotFound, "events repository find by external ID"), "event not found"),
+			wantCode:    ErrorCodeNotFound,
+			wantMessage: notFoundMessage,
+		},
+		{
+			name:        "event missing in the batch",
+			err:         dataloader.ErrNotFound,
+			wantCode:    ErrorCodeNotFound,
+			wantMessage: notFoundMessage,
+		},
+		{
+			name:        "capacity full",
+			err:         fmt.Errorf("accept: %w", aggregate.ErrCapacityFull),
+			wantCode:    ErrorCodeCapacityFull,
+			wantMessage: aggregate.ErrCapacityFull.Error(),
+		},
+		{
+			name:        "registration closed",
+			err:         aggregate.ErrRegistrationClosed,
+			wantCode:    ErrorCodeRegistrationClosed,
+			wantMessage: aggregate.ErrRegistrationClosed.Error(),
+		},
+		{
+			name:        "invitation already exists",
+			err:         invitation.ErrInvitationAlreadyExists,
+			wantCode:    ErrorCodeConflict,
+			wantMessage: invitation.ErrInvitationAlreadyExists.Error(),
+		},
+		{
+			name:        "outdated version of the event",
+			err:         pkgerrors.Wrap(aggregate.ErrVersionConflict, "creating new event failed"),
+			wantCode:    ErrorCodeConflict,
+			wantMessage: aggregate.ErrVersionConflict.Error(),
+		},
+		{
+			name:        "invalid identifier",
+			err:         invalidIDErr,
+			wantCode:    ErrorCodeValidation,
+			wantMessage: services.ErrInvalidID.Error(),
+			wantFields:  []FieldError{{Field: "id", Message: services.ErrInvalidID.Error()}},
+		},
+		{
+			name: "aggregated field errors",
+			err: validation.Errors{
+				validation.NewFieldError("capacity", errors.New("must be positive")),
+				validation.NewFieldError("latitude", errors.New("must be between -90 and 90")),
+			},
+			wantCode:    ErrorCodeValidation,
+			wantMessage: validation.ErrInvalid.Error(),
+			wantFields: []FieldError{
+				{Field: "capacity", Message: "must be positive"},
+				{Field: "latitude", Message: "must be between -90 and 90"},
+			},
+		},
+		{
+			name:        "unknown error is internal",
+			err:         errors.New("dial tcp 10.0.0.1:3306: connection refused"),
+			wantCode:    ErrorCodeInternal,
+			wantMessage: internalMessage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gqlErr := ErrorPresenter(context.Background(), tt.err)
+
+			if gqlErr.Extensions["code"] != tt.wantCode {
+				t.Errorf("ErrorPresenter() code = %v, want %v", gqlErr.Extensions["code"], tt.wantCode)
+			}
+
+			if gqlErr.Message != tt.wantMessage {
+				t.Errorf("ErrorPresenter() message = %q, want %q", gqlErr.Message, tt.wantMessage)
+			}
+
+			fields, _ := gqlErr.Extensions["fields"].([]FieldError)
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("ErrorPresenter() fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"context"
+
+	"event-service/internal/auth"
+	"event-service/internal/dataloader"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
+	"event-service/internal/services/eventfinder"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+)
+
+type loadersKey struct{}
+
+// Loaders batch loading of the events and their participants requested by the fields of a single response,
+// e.g. participants of all events of the page are loaded at once
+type Loaders struct {
+	Events       *dataloader.Loader[uuid.UUID, *aggregate.Event]
+	Participants *dataloader.Loader[uuid.UUID, []*aggregate.Invitation]
+}
+
+func NewLoaders(finder eventfinder.ListHandler) *Loaders {
+	return &Loaders{
+		Events: dataloader.New(finder.GetByIDs),
+		Participants: dataloader.New(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*aggregate.Invitation, error) {
+			participants, err := finder.ParticipantsOf(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			// nobody takes part in the events left out
+			for _, id := range ids {
+				if _, ok := participants[id]; !ok {
+					participants[id] = nil
+				}
+			}
+
+			return participants, nil
+		}),
+	}
+}
+
+// LoadersExtension provides new loaders for every response, so the responses of a subscription never see
+// values cached for the previous ones
+type LoadersExtension struct {
+	Finder eventfinder.ListHandler
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = LoadersExtension{}
+
+func (LoadersExtension) ExtensionName() string {
+	return "Loaders"
+}
+
+func (LoadersExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (e LoadersExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	return next(context.WithValue(ctx, loadersKey{}, NewLoaders(e.Finder)))
+}
+
+// loaders returns loaders of the response, without the extension values are loaded one by one
+func (r *Resolver) loaders(ctx context.Context) *Loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*Loaders); ok {
+		return l
+	}
+
+	return NewLoaders(r.FindEventsHandler)
+}
+
+// viewEvent loads the event the authenticated or anonymous user can see
+func (r *Resolver) viewEvent(ctx context.Context, id uuid.UUID) (*aggregate.Event, error) {
+	ea, err := r.loaders(ctx).Events.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, _ := auth.ActorFromContext(ctx)
+
+	if err := policy.CanView(ea, actor); err != nil {
+		return nil, err
+	}
+
+	return ea, nil
+}
+
+// viewParticipants loads accepted and waitlisted participants of the event the authenticated or anonymous user can see
+func (r *Resolver) viewParticipants(ctx context.Context, id uuid.UUID) ([]*aggregate.Invitation, error) {
+	ea, err := r.loaders(ctx).Events.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, _ := auth.ActorFromContext(ctx)
+	accepted := aggregate.InvitationAccepted
+
+	if err := policy.CanListInvitations(ea, actor, &accepted); err != nil {
+		return nil, err
+	}
+
+	return r.loaders(ctx).Participants.Load(ctx, id)
+}
@@ -0,0 +1,386 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"event-service/graph/model"
+	"event-service/internal/auth"
+	"event-service/internal/database/inmemmory/repository"
+	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/common/validation"
+	"event-service/internal/domain/event"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/valueobject"
+	"event-service/internal/services/eventfinder"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/google/uuid"
+)
+
+// countingFinder counts queries for the participants of the events
+type countingFinder struct {
+	eventfinder.ListHandler
+	participantsCalls atomic.Int32
+}
+
+func (f *countingFinder) ParticipantsOf(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]*aggregate.Invitation, error) {
+	f.participantsCalls.Add(1)
+
+	return f.ListHandler.ParticipantsOf(ctx, ids)
+}
+
+func TestLoadersExtension_BatchesParticipantsOfThePage(t *testing.T) {
+	storage := repository.NewEventsStorage()
+	invitations := repository.NewLinkedInvitationsStorage(storage)
+	participant := uuid.New()
+
+	for i := 0; i < 5; i++ {
+		e := &aggregate.Event{
+			UserID:   uuid.New(),
+			Event:    &entity.Event{ExternalID: uuid.New(), Name: "Event", Capacity: 10, Public: true},
+			Location: &aggregate.Location{},
+		}
+		e.EventPeriod, _ = e.EventPeriod.WithStartAndDuration(time.Now().Add(time.Hour), time.Hour)
+
+		_ = storage.Add(context.Background(), e)
+
+		acceptedAt := time.Now()
+		_ = invitations.Accept(context.Background(), &aggregate.Invitation{Event: e, InvitedUser: participant, InvitedAt: acceptedAt, AcceptedAt: &acceptedAt})
+	}
+
+	finder, _ := eventfinder.NewEventFinder(
+		eventfinder.WithFinderRepository(storage),
+		eventfinder.WithBatchFinderRepository(storage),
+		eventfinder.WithInvitationListerRepository(invitations),
+	)
+	counting := &countingFinder{ListHandler: finder}
+
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{FindEventsHandler: counting}}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(LoadersExtension{Finder: counting})
+
+	var resp struct {
+		Events struct {
+			Edges []struct {
+				Node struct {
+					Participants []struct{ User, Status string }
+					SpotsLeft    int
+				}
+			}
+		}
+	}
+
+	client.New(srv).MustPost(`{ events { edges { node { participants { user status } spotsLeft } } } }`, &resp)
+
+	if len(resp.Events.Edges) != 5 {
+		t.Fatalf("events = %d, want %d", len(resp.Events.Edges), 5)
+	}
+
+	for _, edge := range resp.Events.Edges {
+		if len(edge.Node.Participants) != 1 || edge.Node.Participants[0].User != participant.String() ||
+			edge.Node.Participants[0].Status != "ACCEPTED" {
+			t.Errorf("participants = %v, want accepted %s", edge.Node.Participants, participant)
+		}
+
+		if edge.Node.SpotsLeft != 9 {
+			t.Errorf("spotsLeft = %d, want %d", edge.Node.SpotsLeft, 9)
+		}
+	}
+
+	if calls := counting.participantsCalls.Load(); calls != 1 {
+		t.Errorf("participants loaded with %d queries, want a single one", calls)
+	}
+}
+
+func TestResolver_PrivateEventIsLoadedOnlyForItsUsers(t *testing.T) {
+	storage := repository.NewEventsStorage()
+	invitations := repository.NewLinkedInvitationsStorage(storage)
+	organizer := uuid.New()
+
+	e := &aggregate.Event{
+		UserID:   organizer,
+		Event:    &entity.Event{ExternalID: uuid.New(), Name: "Event", Capacity: 10},
+		Location: &aggregate.Location{},
+	}
+	e.EventPeriod, _ = e.EventPeriod.WithStartAndDuration(time.Now().Add(time.Hour), time.Hour)
+	_ = storage.Add(context.Background(), e)
+
+	finder, _ := eventfinder.NewEventFinder(
+		eventfinder.WithFinderRepository(storage),
+		eventfinder.WithBatchFinderRepository(storage),
+		eventfinder.WithInvitationListerRepository(invitations),
+	)
+
+	tests := []struct {
+		name    string
+		actor   uuid.UUID
+		query   string
+		wantErr bool
+	}{
+		{name: "organizer sees the event", actor: organizer, query: `{ event(id: "%s") { name } }`},
+		{name: "stranger cannot see the event", actor: uuid.New(), query: `{ event(id: "%s") { name } }`, wantErr: true},
+		{name: "anonymous cannot see the event", query: `{ event(id: "%s") { name } }`, wantErr: true},
+		{name: "organizer sees the participants", actor: organizer, query: `{ event(id: "%s") { participants { user } } }`},
+		{name: "stranger cannot see the participants", actor: uuid.New(), query: `{ event(id: "%s") { participants { user } } }`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{FindEventsHandler: finder}}))
+			srv.AddTransport(transport.POST{})
+			srv.Use(LoadersExtension{Finder: finder})
+
+			var resp map[string]any
+			err := client.New(srv).Post(fmt.Sprintf(tt.query, e.Event.ExternalID), &resp, func(r *client.Request) {
+				if tt.actor != uuid.Nil {
+					r.HTTP = r.HTTP.WithContext(auth.ContextWithIdentity(r.HTTP.Context(), auth.Identity{UserID: tt.actor}))
+				}
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("query error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueryResolver_ParticipantsWaitlistPositions(t *testing.T) {
+	storage := repository.NewEventsStorage()
+	invitations := repository.NewLinkedInvitationsStorage(storage)
+
+	e := &aggregate.Event{
+		UserID:   uuid.New(),
+		Event:    &entity.Event{ExternalID: uuid.New(), Name: "Event", Capacity: 1, Public: true},
+		Location: &aggregate.Location{},
+	}
+	e.EventPeriod, _ = e.EventPeriod.WithStartAndDuration(time.Now().Add(time.Hour), time.Hour)
+	_ = storage.Add(context.Background(), e)
+
+	start := time.Now().Add(-time.Hour)
+	waitlisted := []uuid.UUID{uuid.New(), uuid.New()}
+
+	for i, user := range waitlisted {
+		at := start.Add(time.Duration(i) * time.Minute)
+		_ = invitations.Accept(context.Background(), &aggregate.Invitation{Event: e, InvitedUser: user, InvitedAt: at, WaitlistedAt: &at})
+	}
+
+	finder, _ := eventfinder.NewEventFinder(
+		eventfinder.WithFinderRepository(storage),
+		eventfinder.WithBatchFinderRepository(storage),
+		eventfinder.WithInvitationListerRepository(invitations),
+	)
+
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{FindEventsHandler: finder}}))
+	srv.AddTransport(transport.POST{})
+
+	var resp struct {
+		Participants struct {
+			Edges []struct {
+				Cursor string
+				Node   struct {
+					User             string
+					WaitlistPosition int
+				}
+			}
+		}
+	}
+
+	query := `query($id: String!, $after: String) {
+		participants(eventId: $id, status: WAITLISTED, first: 1, after: $after) { edges { cursor node { user waitlistPosition } } }
+	}`
+	client.New(srv).MustPost(query, &resp, client.Var("id", e.Event.ExternalID.String()))
+
+	if len(resp.Participants.Edges) != 1 {
+		t.Fatalf("edges = %d, want %d", len(resp.Participants.Edges), 1)
+	}
+
+	client.New(srv).MustPost(query, &resp, client.Var("id", e.Event.ExternalID.String()), client.Var("after", resp.Participants.Edges[0].Cursor))
+
+	if len(resp.Participants.Edges) != 1 || resp.Participants.Edges[0].Node.User != waitlisted[1].String() ||
+		resp.Participants.Edges[0].Node.WaitlistPosition != 2 {
+		t.Errorf("edges = %v, want %s at the position %d", resp.Participants.Edges, waitlisted[1], 2)
+	}
+}
+
+func TestQueryResolver_EventsListsOnlyVisibleEvents(t *testing.T) {
+	storage := repository.NewEventsStorage()
+	invitations := repository.NewLinkedInvitationsStorage(storage)
+	organizer, invited := uuid.New(), uuid.New()
+
+	newEvent := func(name string, public bool) *aggregate.Event {
+		e := &aggregate.Event{
+			UserID:   organizer,
+			Event:    &entity.Event{ExternalID: uuid.New(), Name: name, Capacity: 10, Public: public},
+			Location: &aggregate.Location{},
+		}
+		e.EventPeriod, _ = e.EventPeriod.WithStartAndDuration(time.Now().Add(time.Hour), time.Hour)
+		_ = storage.Add(context.Background(), e)
+
+		return e
+	}
+
+	newEvent("public", true)
+	newEvent("private", false)
+	invitedTo := newEvent("invited", false)
+	_ = invitations.Invite(context.Background(), &aggregate.Invitation{Event: invitedTo, InvitedUser: invited, InvitedAt: time.Now()})
+
+	finder, _ := eventfinder.NewEventFinder(
+		eventfinder.WithFinderRepository(storage),
+		eventfinder.WithBatchFinderRepository(storage),
+		eventfinder.WithInvitationListerRepository(invitations),
+	)
+
+	tests := []struct {
+		name  string
+		actor uuid.UUID
+		want  []string
+	}{
+		{name: "stranger sees public events only", actor: uuid.New(), want: []string{"public"}},
+		{name: "anonymous user sees public events only", want: []string{"public"}},
+		{name: "invited user sees the event of the invitation", actor: invited, want: []string{"invited", "public"}},
+		{name: "organizer sees all own events", actor: organizer, want: []string{"invited", "private", "public"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{FindEventsHandler: finder}}))
+			srv.AddTransport(transport.POST{})
+
+			var resp struct {
+				Events struct {
+					Edges []struct{ Node struct{ Name string } }
+				}
+			}
+
+			client.New(srv).MustPost(`{ events(orderBy: {field: NAME}) { edges { node { name } } } }`, &resp, func(r *client.Request) {
+				if tt.actor != uuid.Nil {
+					r.HTTP = r.HTTP.WithContext(auth.ContextWithIdentity(r.HTTP.Context(), auth.Identity{UserID: tt.actor}))
+				}
+			})
+
+			var got []string
+			for _, edge := range resp.Events.Edges {
+				got = append(got, edge.Node.Name)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// requestRecordingFinder keeps the request of the listed events
+type requestRecordingFinder struct {
+	eventfinder.ListHandler
+	request *eventfinder.Request
+}
+
+func (f *requestRecordingFinder) List(_ context.Context, r eventfinder.Request) (event.Page, error) {
+	f.request = &r
+
+	return event.Page{}, nil
+}
+
+func TestQueryResolver_EventsValidatesLocationAndUpcoming(t *testing.T) {
+	latitude, distance, duration, negative := 52.2, 10, 3600, -1
+	date := time.Now()
+
+	tests := []struct {
+		name     string
+		location *model.Location
+		upcoming *model.Upcoming
+		fields   []string
+	}{
+		{name: "location without coordinates", location: &model.Location{Distance: &distance},
+			fields: []string{"location.latitude", "location.longitude"}},
+		{name: "location without distance", location: &model.Location{Latitude: &latitude, Longitude: &latitude},
+			fields: []string{"location.distance"}},
+		{name: "upcoming without date", upcoming: &model.Upcoming{Duration: &duration}, fields: []string{"upcoming.date"}},
+		{name: "upcoming without duration", upcoming: &model.Upcoming{Date: &date}, fields: []string{"upcoming.duration"}},
+		{name: "upcoming with negative duration", upcoming: &model.Upcoming{Date: &date, Duration: &negative},
+			fields: []string{"upcoming.duration"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finder := &requestRecordingFinder{}
+			resolver := (&Resolver{FindEventsHandler: finder}).Query()
+
+			_, err := resolver.Events(context.Background(), nil, nil, nil, tt.location, tt.upcoming, nil, nil, nil, nil, nil)
+
+			var errs validation.Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Events() error = %v, want validation errors", err)
+			}
+
+			var fields []string
+			for _, fieldErr := range errs {
+				fields = append(fields, fieldErr.Field)
+			}
+
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Events() invalid fields = %v, want %v", fields, tt.fields)
+			}
+
+			if finder.request != nil {
+				t.Errorf("Events() listed events with invalid input")
+			}
+		})
+	}
+}
+
+func TestQueryResolver_EventsUpcomingDurationInSeconds(t *testing.T) {
+	finder := &requestRecordingFinder{}
+	duration, date := 3600, time.Now()
+
+	_, err := (&Resolver{FindEventsHandler: finder}).Query().
+		Events(context.Background(), nil, nil, nil, nil, &model.Upcoming{Date: &date, Duration: &duration}, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+
+	if got := finder.request.Upcoming.Interval; got != time.Hour {
+		t.Errorf("Events() upcoming interval = %v, want %v", got, time.Hour)
+	}
+}
+
+func TestQueryResolver_EventsReportsTheInvalidCursorField(t *testing.T) {
+	storage := repository.NewEventsStorage()
+	finder, _ := eventfinder.NewEventFinder(
+		eventfinder.WithFinderRepository(storage),
+		eventfinder.WithBatchFinderRepository(storage),
+		eventfinder.WithInvitationListerRepository(repository.NewLinkedInvitationsStorage(storage)),
+	)
+	resolver := (&Resolver{FindEventsHandler: finder}).Query()
+	nameCursor := valueobject.NewStringCursor(valueobject.OrderByName, 1, "event").Encode()
+	malformedDate := valueobject.NewStringCursor(valueobject.OrderByStartDate, 1, "yesterday").Encode()
+	malformed := "cursor"
+
+	tests := []struct {
+		name    string
+		after   *string
+		before  *string
+		field   string
+		wantErr error
+	}{
+		{name: "malformed after", after: &malformed, field: "after", wantErr: valueobject.ErrInvalidCursor},
+		{name: "malformed before", before: &malformed, field: "before", wantErr: valueobject.ErrInvalidCursor},
+		{name: "before of another ordering", before: &nameCursor, field: "before", wantErr: eventfinder.ErrCursorOrderMismatch},
+		{name: "after with malformed value", after: &malformedDate, field: "after", wantErr: valueobject.ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolver.Events(context.Background(), nil, nil, nil, nil, nil, nil, tt.after, nil, tt.before, nil)
+
+			var errs validation.Errors
+			if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != tt.field || !errors.Is(errs[0].Err, tt.wantErr) {
+				t.Errorf("Events() error = %v, want %v of the %s field", err, tt.wantErr, tt.field)
+			}
+		})
+	}
+}
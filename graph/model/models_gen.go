@@ -3,9 +3,17 @@
 package model
 
 import (
+	"fmt"
+	"io"
+	"strconv"
 	"time"
 )
 
+type CalendarLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
 type Event struct {
 	ID                    string         `json:"id"`
 	User                  string         `json:"user"`
@@ -20,12 +28,44 @@ type Event struct {
 	Latitude              float64        `json:"latitude"`
 	Longitude             float64        `json:"longitude"`
 	Public                bool           `json:"public"`
+	Status                EventStatus    `json:"status"`
+	CancellationReason    *string        `json:"cancellationReason"`
+	CancelledAt           *time.Time     `json:"cancelledAt"`
+	Recurrence            *Recurrence    `json:"recurrence"`
+	Occurrences           []*Occurrence  `json:"occurrences"`
 	Participants          []*Participant `json:"participants"`
+	ParticipantsCount     int            `json:"participantsCount"`
+	SpotsLeft             int            `json:"spotsLeft"`
+	RegistrationOpen      bool           `json:"registrationOpen"`
+	Version               int            `json:"version"`
+}
+
+type EventConnection struct {
+	Edges      []*EventEdge `json:"edges"`
+	PageInfo   *PageInfo    `json:"pageInfo"`
+	TotalCount int          `json:"totalCount"`
+}
+
+type EventEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Event `json:"node"`
+}
+
+type EventOrder struct {
+	Field     EventOrderField `json:"field"`
+	Direction *OrderDirection `json:"direction"`
 }
 
 type Invitation struct {
-	User  string `json:"user"`
-	Event string `json:"event"`
+	User  *string `json:"user"`
+	Event string  `json:"event"`
+}
+
+type InvitationChange struct {
+	Type       InvitationChangeType `json:"type"`
+	User       string               `json:"user"`
+	Waitlisted bool                 `json:"waitlisted"`
+	Event      *Event               `json:"event"`
 }
 
 type Location struct {
@@ -35,20 +75,56 @@ type Location struct {
 }
 
 type NewEvent struct {
-	User                string     `json:"user"`
-	Name                string     `json:"name"`
-	Description         *string    `json:"description"`
-	Capacity            int        `json:"capacity"`
-	Latitude            float64    `json:"latitude"`
-	Longitude           float64    `json:"longitude"`
-	Duration            int        `json:"duration"`
-	StartDate           time.Time  `json:"startDate"`
-	RegistrationEndDate *time.Time `json:"registrationEndDate"`
-	Public              bool       `json:"public"`
+	Name                string           `json:"name"`
+	Description         *string          `json:"description"`
+	Capacity            int              `json:"capacity"`
+	Latitude            float64          `json:"latitude"`
+	Longitude           float64          `json:"longitude"`
+	Duration            int              `json:"duration"`
+	StartDate           time.Time        `json:"startDate"`
+	RegistrationEndDate *time.Time       `json:"registrationEndDate"`
+	Public              bool             `json:"public"`
+	Recurrence          *RecurrenceInput `json:"recurrence"`
+}
+
+type Occurrence struct {
+	OriginalStartDate time.Time `json:"originalStartDate"`
+	StartDate         time.Time `json:"startDate"`
+	EndDate           time.Time `json:"endDate"`
+	Moved             bool      `json:"moved"`
+}
+
+type OccurrenceUpdate struct {
+	OriginalStartDate time.Time `json:"originalStartDate"`
+	Cancelled         *bool     `json:"cancelled"`
+	EventDate         *Period   `json:"eventDate"`
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
 }
 
 type Participant struct {
-	User string `json:"user"`
+	User             string            `json:"user"`
+	Status           ParticipantStatus `json:"status"`
+	WaitlistPosition *int              `json:"waitlistPosition"`
+	InvitedAt        time.Time         `json:"invitedAt"`
+	AcceptedAt       *time.Time        `json:"acceptedAt"`
+	InvitedBy        *string           `json:"invitedBy"`
+}
+
+type ParticipantConnection struct {
+	Edges      []*ParticipantEdge `json:"edges"`
+	PageInfo   *PageInfo          `json:"pageInfo"`
+	TotalCount int                `json:"totalCount"`
+}
+
+type ParticipantEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   *Participant `json:"node"`
 }
 
 type Period struct {
@@ -56,19 +132,263 @@ type Period struct {
 	End   time.Time `json:"end"`
 }
 
+type Recurrence struct {
+	Rule       string       `json:"rule"`
+	Exceptions []*time.Time `json:"exceptions"`
+}
+
+type RecurrenceInput struct {
+	Rule       string       `json:"rule"`
+	Exceptions []*time.Time `json:"exceptions"`
+}
+
 type Upcoming struct {
 	Date     *time.Time `json:"date"`
 	Duration *int       `json:"duration"`
 }
 
 type UpdateEvent struct {
-	ID               string   `json:"id"`
-	Name             *string  `json:"name"`
-	Description      *string  `json:"description"`
-	Capacity         *int     `json:"capacity"`
-	Latitude         *float64 `json:"latitude"`
-	Longitude        *float64 `json:"longitude"`
-	EventDate        *Period  `json:"eventDate"`
-	RegistrationDate *Period  `json:"registrationDate"`
-	Public           *bool    `json:"public"`
+	ID               string            `json:"id"`
+	Version          int               `json:"version"`
+	Name             *string           `json:"name"`
+	Description      *string           `json:"description"`
+	Capacity         *int              `json:"capacity"`
+	Latitude         *float64          `json:"latitude"`
+	Longitude        *float64          `json:"longitude"`
+	EventDate        *Period           `json:"eventDate"`
+	RegistrationDate *Period           `json:"registrationDate"`
+	Public           *bool             `json:"public"`
+	Recurrence       *RecurrenceInput  `json:"recurrence"`
+	Occurrence       *OccurrenceUpdate `json:"occurrence"`
+}
+
+type EventOrderField string
+
+const (
+	EventOrderFieldStartDate EventOrderField = "START_DATE"
+	EventOrderFieldCreatedAt EventOrderField = "CREATED_AT"
+	EventOrderFieldDistance  EventOrderField = "DISTANCE"
+	EventOrderFieldName      EventOrderField = "NAME"
+)
+
+var AllEventOrderField = []EventOrderField{
+	EventOrderFieldStartDate,
+	EventOrderFieldCreatedAt,
+	EventOrderFieldDistance,
+	EventOrderFieldName,
+}
+
+func (e EventOrderField) IsValid() bool {
+	switch e {
+	case EventOrderFieldStartDate, EventOrderFieldCreatedAt, EventOrderFieldDistance, EventOrderFieldName:
+		return true
+	}
+	return false
+}
+
+func (e EventOrderField) String() string {
+	return string(e)
+}
+
+func (e *EventOrderField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EventOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EventOrderField", str)
+	}
+	return nil
+}
+
+func (e EventOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type EventStatus string
+
+const (
+	EventStatusScheduled EventStatus = "SCHEDULED"
+	EventStatusCancelled EventStatus = "CANCELLED"
+)
+
+var AllEventStatus = []EventStatus{
+	EventStatusScheduled,
+	EventStatusCancelled,
+}
+
+func (e EventStatus) IsValid() bool {
+	switch e {
+	case EventStatusScheduled, EventStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (e EventStatus) String() string {
+	return string(e)
+}
+
+func (e *EventStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EventStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EventStatus", str)
+	}
+	return nil
+}
+
+func (e EventStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type InvitationChangeType string
+
+const (
+	InvitationChangeTypeInvited    InvitationChangeType = "INVITED"
+	InvitationChangeTypeAccepted   InvitationChangeType = "ACCEPTED"
+	InvitationChangeTypeJoined     InvitationChangeType = "JOINED"
+	InvitationChangeTypeRemoved    InvitationChangeType = "REMOVED"
+	InvitationChangeTypeWaitlisted InvitationChangeType = "WAITLISTED"
+	InvitationChangeTypePromoted   InvitationChangeType = "PROMOTED"
+	InvitationChangeTypeDeclined   InvitationChangeType = "DECLINED"
+	InvitationChangeTypeRevoked    InvitationChangeType = "REVOKED"
+	InvitationChangeTypeLeft       InvitationChangeType = "LEFT"
+)
+
+var AllInvitationChangeType = []InvitationChangeType{
+	InvitationChangeTypeInvited,
+	InvitationChangeTypeAccepted,
+	InvitationChangeTypeJoined,
+	InvitationChangeTypeRemoved,
+	InvitationChangeTypeWaitlisted,
+	InvitationChangeTypePromoted,
+	InvitationChangeTypeDeclined,
+	InvitationChangeTypeRevoked,
+	InvitationChangeTypeLeft,
+}
+
+func (e InvitationChangeType) IsValid() bool {
+	switch e {
+	case InvitationChangeTypeInvited, InvitationChangeTypeAccepted, InvitationChangeTypeJoined, InvitationChangeTypeRemoved, InvitationChangeTypeWaitlisted, InvitationChangeTypePromoted, InvitationChangeTypeDeclined, InvitationChangeTypeRevoked, InvitationChangeTypeLeft:
+		return true
+	}
+	return false
+}
+
+func (e InvitationChangeType) String() string {
+	return string(e)
+}
+
+func (e *InvitationChangeType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = InvitationChangeType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid InvitationChangeType", str)
+	}
+	return nil
+}
+
+func (e InvitationChangeType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OrderDirection string
+
+const (
+	OrderDirectionAsc  OrderDirection = "ASC"
+	OrderDirectionDesc OrderDirection = "DESC"
+)
+
+var AllOrderDirection = []OrderDirection{
+	OrderDirectionAsc,
+	OrderDirectionDesc,
+}
+
+func (e OrderDirection) IsValid() bool {
+	switch e {
+	case OrderDirectionAsc, OrderDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e OrderDirection) String() string {
+	return string(e)
+}
+
+func (e *OrderDirection) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderDirection", str)
+	}
+	return nil
+}
+
+func (e OrderDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ParticipantStatus string
+
+const (
+	ParticipantStatusInvited    ParticipantStatus = "INVITED"
+	ParticipantStatusAccepted   ParticipantStatus = "ACCEPTED"
+	ParticipantStatusDeclined   ParticipantStatus = "DECLINED"
+	ParticipantStatusWaitlisted ParticipantStatus = "WAITLISTED"
+	ParticipantStatusRemoved    ParticipantStatus = "REMOVED"
+	ParticipantStatusRevoked    ParticipantStatus = "REVOKED"
+)
+
+var AllParticipantStatus = []ParticipantStatus{
+	ParticipantStatusInvited,
+	ParticipantStatusAccepted,
+	ParticipantStatusDeclined,
+	ParticipantStatusWaitlisted,
+	ParticipantStatusRemoved,
+	ParticipantStatusRevoked,
+}
+
+func (e ParticipantStatus) IsValid() bool {
+	switch e {
+	case ParticipantStatusInvited, ParticipantStatusAccepted, ParticipantStatusDeclined, ParticipantStatusWaitlisted, ParticipantStatusRemoved, ParticipantStatusRevoked:
+		return true
+	}
+	return false
+}
+
+func (e ParticipantStatus) String() string {
+	return string(e)
+}
+
+func (e *ParticipantStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ParticipantStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ParticipantStatus", str)
+	}
+	return nil
+}
+
+func (e ParticipantStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
@@ -1,10 +1,16 @@
 package graph
 
 import (
+	"time"
+
 	"event-service/internal/services/eventcreator"
 	"event-service/internal/services/eventfinder"
+	"event-service/internal/services/eventremover"
+	"event-service/internal/services/eventsubscription"
 	"event-service/internal/services/eventupdater"
 	"event-service/internal/services/invitation"
+
+	"github.com/google/uuid"
 )
 
 // This file will not be regenerated automatically.
@@ -12,8 +18,17 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	AddEventHandler    eventcreator.Handler
-	FindEventsHandler  eventfinder.ListHandler
-	UpdateEventHandler eventupdater.Handler
-	InvitationHandler  invitation.Handler
+	AddEventHandler     eventcreator.Handler
+	FindEventsHandler   eventfinder.ListHandler
+	UpdateEventHandler  eventupdater.Handler
+	InvitationHandler   invitation.Handler
+	RemoveEventHandler  eventremover.Handler
+	SubscriptionHandler eventsubscription.Handler
+	CalendarTokens      CalendarTokenIssuer
+	APIURL              string // public url of the api the calendar links point to
+}
+
+// CalendarTokenIssuer issues the token of the user for the calendar at the path
+type CalendarTokenIssuer interface {
+	Issue(user uuid.UUID, path string) (string, time.Time, error)
 }
@@ -6,12 +6,93 @@ package graph
 
 import (
 	"context"
+	"net/url"
+	"strings"
 	"time"
 
 	"event-service/graph/model"
+	"event-service/internal/auth"
+	"event-service/internal/calendar"
+	"event-service/internal/domain/common/validation"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/services"
 	"event-service/internal/services/eventfinder"
 )
 
+// Occurrences is the resolver for the occurrences field.
+func (r *eventResolver) Occurrences(ctx context.Context, obj *model.Event, from *time.Time, to *time.Time) ([]*model.Occurrence, error) {
+	id, err := services.ParseID(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := r.loaders(ctx).Events.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if from != nil {
+		start = *from
+	}
+
+	end := start.AddDate(1, 0, 0)
+	if to != nil {
+		end = *to
+	}
+
+	if end.Sub(start) > aggregate.MaxOccurrencesRange {
+		return nil, validation.Errors{validation.NewFieldError("to", aggregate.ErrOccurrencesRangeTooLong)}
+	}
+
+	return ConvertOccurrencesToModel(item.Occurrences(start, end)), nil
+}
+
+// Participants is the resolver for the participants field.
+func (r *eventResolver) Participants(ctx context.Context, obj *model.Event) ([]*model.Participant, error) {
+	id, err := services.ParseID(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := r.viewParticipants(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return ConvertParticipantsToModel(participants), nil
+}
+
+// ParticipantsCount is the resolver for the participantsCount field.
+func (r *eventResolver) ParticipantsCount(ctx context.Context, obj *model.Event) (int, error) {
+	id, err := services.ParseID(obj.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	item, err := r.loaders(ctx).Events.Load(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return item.ParticipantsNumber(), nil
+}
+
+// SpotsLeft is the resolver for the spotsLeft field.
+func (r *eventResolver) SpotsLeft(ctx context.Context, obj *model.Event) (int, error) {
+	id, err := services.ParseID(obj.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	item, err := r.loaders(ctx).Events.Load(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return item.SpotsLeft(), nil
+}
+
 // CreateEvent is the resolver for the createEvent field.
 func (r *mutationResolver) CreateEvent(ctx context.Context, input model.NewEvent) (*model.Event, error) {
 	newEvent, addErr := r.AddEventHandler.CreateEvent(ctx, ConvertNewEventToRequest(input))
@@ -34,7 +115,7 @@ func (r *mutationResolver) UpdateEvent(ctx context.Context, input model.UpdateEv
 
 // JoinEvent is the resolver for the joinEvent field.
 func (r *mutationResolver) JoinEvent(ctx context.Context, input model.Invitation) (bool, error) {
-	if err := r.InvitationHandler.Join(ctx, input.Event, input.User); err != nil {
+	if err := r.InvitationHandler.Join(ctx, input.Event, getValueIfNotNull(input.User)); err != nil {
 		return false, err
 	}
 
@@ -43,7 +124,7 @@ func (r *mutationResolver) JoinEvent(ctx context.Context, input model.Invitation
 
 // InviteParticipant is the resolver for the inviteParticipant field.
 func (r *mutationResolver) InviteParticipant(ctx context.Context, input model.Invitation) (bool, error) {
-	if err := r.InvitationHandler.Invite(ctx, input.Event, input.User); err != nil {
+	if err := r.InvitationHandler.Invite(ctx, input.Event, getValueIfNotNull(input.User)); err != nil {
 		return false, err
 	}
 
@@ -52,7 +133,25 @@ func (r *mutationResolver) InviteParticipant(ctx context.Context, input model.In
 
 // AcceptParticipant is the resolver for the acceptParticipant field.
 func (r *mutationResolver) AcceptParticipant(ctx context.Context, input model.Invitation) (bool, error) {
-	if err := r.InvitationHandler.Accept(ctx, input.Event, input.User); err != nil {
+	if err := r.InvitationHandler.Accept(ctx, input.Event, getValueIfNotNull(input.User)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeclineInvitation is the resolver for the declineInvitation field.
+func (r *mutationResolver) DeclineInvitation(ctx context.Context, input model.Invitation) (bool, error) {
+	if err := r.InvitationHandler.Decline(ctx, input.Event, getValueIfNotNull(input.User)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RevokeInvitation is the resolver for the revokeInvitation field.
+func (r *mutationResolver) RevokeInvitation(ctx context.Context, input model.Invitation) (bool, error) {
+	if err := r.InvitationHandler.Revoke(ctx, input.Event, getValueIfNotNull(input.User)); err != nil {
 		return false, err
 	}
 
@@ -61,69 +160,170 @@ func (r *mutationResolver) AcceptParticipant(ctx context.Context, input model.In
 
 // RemoveParticipant is the resolver for the removeParticipant field.
 func (r *mutationResolver) RemoveParticipant(ctx context.Context, input model.Invitation) (bool, error) {
-	if err := r.InvitationHandler.Remove(ctx, input.Event, input.User); err != nil {
+	if err := r.InvitationHandler.Remove(ctx, input.Event, getValueIfNotNull(input.User)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// LeaveEvent is the resolver for the leaveEvent field.
+func (r *mutationResolver) LeaveEvent(ctx context.Context, eventID string) (bool, error) {
+	if err := r.InvitationHandler.Leave(ctx, eventID); err != nil {
 		return false, err
 	}
 
 	return true, nil
 }
 
+// CancelEvent is the resolver for the cancelEvent field.
+func (r *mutationResolver) CancelEvent(ctx context.Context, id string, reason *string) (*model.Event, error) {
+	cancelledEvent, cancelErr := r.RemoveEventHandler.CancelEvent(ctx, id, getValueIfNotNull(reason))
+	if cancelErr != nil {
+		return nil, cancelErr
+	}
+
+	return ConvertEventEntryToModel(cancelledEvent), nil
+}
+
+// DeleteEvent is the resolver for the deleteEvent field.
+func (r *mutationResolver) DeleteEvent(ctx context.Context, id string) (bool, error) {
+	if err := r.RemoveEventHandler.DeleteEvent(ctx, id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreateCalendarLink is the resolver for the createCalendarLink field.
+func (r *mutationResolver) CreateCalendarLink(ctx context.Context, eventID *string) (*model.CalendarLink, error) {
+	actor, err := auth.ActorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := calendar.UserPath(actor)
+	if eventID != nil {
+		id, err := services.ParseID(*eventID)
+		if err != nil {
+			return nil, err
+		}
+
+		path = calendar.EventPath(id)
+	}
+
+	token, expiresAt, err := r.CalendarTokens.Issue(actor, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CalendarLink{
+		URL:       strings.TrimSuffix(r.APIURL, "/") + path + "?" + url.Values{calendar.AccessTokenParameter: {token}}.Encode(),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
 // Events is the resolver for the events field.
-func (r *queryResolver) Events(ctx context.Context, user *string, name *string, public *bool, location *model.Location, upcoming *model.Upcoming) ([]*model.Event, error) {
+func (r *queryResolver) Events(ctx context.Context, user *string, name *string, public *bool, location *model.Location, upcoming *model.Upcoming, first *int, after *string, last *int, before *string, orderBy *model.EventOrder) (*model.EventConnection, error) {
+	locationRequest, err := ConvertLocationToRequest(location)
+	if err != nil {
+		return nil, err
+	}
+
+	upcomingRequest, err := ConvertUpcomingToRequest(upcoming)
+	if err != nil {
+		return nil, err
+	}
+
 	request := eventfinder.Request{
 		User:     getValueIfNotNull(user),
 		Name:     getValueIfNotNull(name),
-		Location: nil,
-		Upcoming: nil,
+		Location: locationRequest,
+		Upcoming: upcomingRequest,
+		Public:   public,
+		First:    first,
+		Last:     last,
+		After:    getValueIfNotNull(after),
+		Before:   getValueIfNotNull(before),
+		OrderBy:  ConvertEventOrderToRequest(orderBy),
 	}
 
-	if location != nil {
-		request.Location = &eventfinder.LocationRequest{
-			Latitude:  *location.Latitude,
-			Longitude: *location.Longitude,
-			Distance:  int64(*location.Distance),
-		}
+	page, err := r.FindEventsHandler.List(ctx, request)
+	if err != nil {
+		return nil, err
 	}
 
-	if upcoming != nil {
-		request.Upcoming = &eventfinder.UpcomingEventRequest{
-			Date:     *upcoming.Date,
-			Interval: time.Duration(*upcoming.Duration),
-		}
+	return ConvertPageToConnection(page), nil
+}
+
+// Event is the resolver for the event field.
+func (r *queryResolver) Event(ctx context.Context, id *string) (*model.Event, error) {
+	eventID, err := services.ParseID(getValueIfNotNull(id))
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := r.viewEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
 	}
 
-	if public != nil {
-		request.Public = *public
+	return ConvertEventEntryToModel(item), nil
+}
+
+// Participants is the resolver for the participants field.
+func (r *queryResolver) Participants(ctx context.Context, eventID string, status *model.ParticipantStatus, first *int, after *string) (*model.ParticipantConnection, error) {
+	page, err := r.FindEventsHandler.Participants(ctx, eventID, ConvertParticipantsRequest(status, first, after))
+	if err != nil {
+		return nil, err
 	}
 
-	collection, err := r.FindEventsHandler.List(ctx, request)
+	return ConvertParticipantPageToModel(page), nil
+}
+
+// EventUpdated is the resolver for the eventUpdated field.
+func (r *subscriptionResolver) EventUpdated(ctx context.Context, id string) (<-chan *model.Event, error) {
+	updates, err := r.SubscriptionHandler.EventUpdated(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	items := make([]*model.Event, len(collection))
-	for i := 0; i < len(collection); i++ {
-		items[i] = ConvertEventEntryToModel(collection[i])
+	return convertStream(ctx, updates, ConvertEventEntryToModel), nil
+}
+
+// ParticipantsChanged is the resolver for the participantsChanged field.
+func (r *subscriptionResolver) ParticipantsChanged(ctx context.Context, eventID string) (<-chan *model.InvitationChange, error) {
+	changes, err := r.SubscriptionHandler.ParticipantsChanged(ctx, eventID)
+	if err != nil {
+		return nil, err
 	}
 
-	return items, nil
+	return convertStream(ctx, changes, ConvertChangeToModel), nil
 }
 
-// Event is the resolver for the event field.
-func (r *queryResolver) Event(ctx context.Context, id *string) (*model.Event, error) {
-	item, err := r.FindEventsHandler.GetByID(ctx, *id)
+// MyInvitations is the resolver for the myInvitations field.
+func (r *subscriptionResolver) MyInvitations(ctx context.Context, user *string) (<-chan *model.InvitationChange, error) {
+	changes, err := r.SubscriptionHandler.MyInvitations(ctx, getValueIfNotNull(user))
 	if err != nil {
 		return nil, err
 	}
 
-	return ConvertEventEntryToModel(item), err
+	return convertStream(ctx, changes, ConvertChangeToModel), nil
 }
 
+// Event returns EventResolver implementation.
+func (r *Resolver) Event() EventResolver { return &eventResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+type eventResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// CalendarAudience is the audience of the calendar tokens, they are not accepted by the rest of the api
+	CalendarAudience = "calendar"
+
+	DefaultCalendarTokenTTL = time.Hour
+)
+
+var ErrCalendarTokensDisabled = errors.New("calendar tokens are not configured")
+
+// calendarClaims bind the token to the path of a single calendar
+type calendarClaims struct {
+	jwt.RegisteredClaims
+	Path string `json:"path"`
+}
+
+// CalendarTokens issues and verifies short-lived tokens passed in the query of the calendar urls,
+// calendar clients cannot set headers. The token is signed with its own secret and it grants access
+// only to the calendar it was issued for
+type CalendarTokens struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewCalendarTokens(secret []byte, ttl time.Duration) *CalendarTokens {
+	if ttl <= 0 {
+		ttl = DefaultCalendarTokenTTL
+	}
+
+	return &CalendarTokens{secret: secret, ttl: ttl, now: time.Now}
+}
+
+// Issue returns the token of the user for the calendar at the path, e.g. /users/{id}/calendar.ics
+func (c CalendarTokens) Issue(user uuid.UUID, path string) (string, time.Time, error) {
+	if len(c.secret) == 0 {
+		return "", time.Time{}, ErrCalendarTokensDisabled
+	}
+
+	now := c.now()
+	expiresAt := now.Add(c.ttl)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, calendarClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.String(),
+			Audience:  jwt.ClaimStrings{CalendarAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Path: path,
+	}).SignedString(c.secret)
+
+	return token, expiresAt, err
+}
+
+// Verify returns identity of the token issued for the calendar at the path
+func (c CalendarTokens) Verify(token, path string) (Identity, error) {
+	if len(c.secret) == 0 {
+		return Identity{}, ErrCalendarTokensDisabled
+	}
+
+	claims := calendarClaims{}
+
+	if _, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return c.secret, nil
+	},
+		jwt.WithValidMethods([]string{AlgorithmHS256}),
+		jwt.WithExpirationRequired(),
+		jwt.WithAudience(CalendarAudience),
+		jwt.WithTimeFunc(c.now),
+	); err != nil {
+		return Identity{}, errors.Join(ErrInvalidToken, err)
+	}
+
+	if claims.Path != path {
+		return Identity{}, ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return Identity{}, errors.Join(ErrInvalidToken, err)
+	}
+
+	return Identity{UserID: userID}, nil
+}
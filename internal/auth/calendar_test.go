@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCalendarTokens_Verify(t *testing.T) {
+	user := uuid.New()
+	path := "/users/" + user.String() + "/calendar.ics"
+
+	tokens := NewCalendarTokens([]byte("calendar-secret"), time.Hour)
+	token, expiresAt, err := tokens.Issue(user, path)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	if expiresAt.After(time.Now().Add(time.Hour)) {
+		t.Errorf("Issue() expiresAt = %v, want within an hour", expiresAt)
+	}
+
+	expired := NewCalendarTokens([]byte("calendar-secret"), time.Hour)
+	expired.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+	expiredToken, _, _ := expired.Issue(user, path)
+
+	apiToken, _, _ := NewCalendarTokens([]byte("api-secret"), time.Hour).Issue(user, path)
+
+	tests := []struct {
+		name    string
+		tokens  *CalendarTokens
+		token   string
+		path    string
+		want    Identity
+		wantErr error
+	}{
+		{
+			name:   "token of the calendar",
+			tokens: tokens,
+			token:  token,
+			path:   path,
+			want:   Identity{UserID: user},
+		},
+		{
+			name:    "token of another calendar",
+			tokens:  tokens,
+			token:   token,
+			path:    "/events/" + uuid.NewString() + ".ics",
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "expired token",
+			tokens:  tokens,
+			token:   expiredToken,
+			path:    path,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "token signed with another secret",
+			tokens:  tokens,
+			token:   apiToken,
+			path:    path,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "calendar tokens are not configured",
+			tokens:  NewCalendarTokens(nil, time.Hour),
+			token:   token,
+			path:    path,
+			wantErr: ErrCalendarTokensDisabled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.tokens.Verify(tt.token, tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnauthenticated = errors.New("authentication is required")
+
+type ContextKey string
+
+const (
+	identityKey ContextKey = "identity"
+)
+
+// Identity describes the authenticated caller of the api
+type Identity struct {
+	UserID uuid.UUID
+}
+
+func ContextWithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey, identity)
+}
+
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey).(Identity)
+
+	return identity, ok && identity.UserID != uuid.Nil
+}
+
+// ActorFromContext returns id of the authenticated user that performs the action
+func ActorFromContext(ctx context.Context) (uuid.UUID, error) {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return uuid.Nil, ErrUnauthenticated
+	}
+
+	return identity.UserID, nil
+}
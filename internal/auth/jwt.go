@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+var (
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrUnsupportedKeyType = errors.New("unsupported signing algorithm")
+)
+
+type TokenVerifier interface {
+	Verify(token string) (Identity, error)
+}
+
+// JWTVerifier validates signed tokens, the subject claim of the token must be the uuid of the user
+type JWTVerifier struct {
+	algorithm string
+	key       any
+	options   []jwt.ParserOption
+}
+
+type VerifierConfiguration func(*JWTVerifier)
+
+func NewHS256Verifier(secret []byte, configuration ...VerifierConfiguration) *JWTVerifier {
+	return newVerifier(AlgorithmHS256, secret, configuration)
+}
+
+func NewRS256Verifier(publicKey *rsa.PublicKey, configuration ...VerifierConfiguration) *JWTVerifier {
+	return newVerifier(AlgorithmRS256, publicKey, configuration)
+}
+
+func newVerifier(algorithm string, key any, configuration []VerifierConfiguration) *JWTVerifier {
+	v := &JWTVerifier{
+		algorithm: algorithm,
+		key:       key,
+		options: []jwt.ParserOption{
+			jwt.WithValidMethods([]string{algorithm}),
+			jwt.WithExpirationRequired(),
+		},
+	}
+
+	for _, cfg := range configuration {
+		cfg(v)
+	}
+
+	return v
+}
+
+func WithIssuer(issuer string) VerifierConfiguration {
+	return func(v *JWTVerifier) {
+		if issuer != "" {
+			v.options = append(v.options, jwt.WithIssuer(issuer))
+		}
+	}
+}
+
+func WithAudience(audience string) VerifierConfiguration {
+	return func(v *JWTVerifier) {
+		if audience != "" {
+			v.options = append(v.options, jwt.WithAudience(audience))
+		}
+	}
+}
+
+func (v JWTVerifier) Verify(token string) (Identity, error) {
+	claims := jwt.RegisteredClaims{}
+
+	if _, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return v.key, nil
+	}, v.options...); err != nil {
+		return Identity{}, errors.Join(ErrInvalidToken, err)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return Identity{}, errors.Join(ErrInvalidToken, err)
+	}
+
+	return Identity{UserID: userID}, nil
+}
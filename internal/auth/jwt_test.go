@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	secret := []byte("secret")
+	userID := uuid.New()
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	sign := func(method jwt.SigningMethod, key any, claims jwt.RegisteredClaims) string {
+		token, _ := jwt.NewWithClaims(method, claims).SignedString(key)
+		return token
+	}
+
+	validClaims := jwt.RegisteredClaims{
+		Subject:   userID.String(),
+		Issuer:    "casper",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+
+	tests := []struct {
+		name     string
+		verifier TokenVerifier
+		token    string
+		want     Identity
+		wantErr  bool
+	}{
+		{
+			name:     "valid HS256 token",
+			verifier: NewHS256Verifier(secret, WithIssuer("casper")),
+			token:    sign(jwt.SigningMethodHS256, secret, validClaims),
+			want:     Identity{UserID: userID},
+		},
+		{
+			name:     "valid RS256 token",
+			verifier: NewRS256Verifier(&rsaKey.PublicKey),
+			token:    sign(jwt.SigningMethodRS256, rsaKey, validClaims),
+			want:     Identity{UserID: userID},
+		},
+		{
+			name:     "token signed with different secret",
+			verifier: NewHS256Verifier(secret),
+			token:    sign(jwt.SigningMethodHS256, []byte("other"), validClaims),
+			wantErr:  true,
+		},
+		{
+			name:     "token signed with not allowed algorithm",
+			verifier: NewRS256Verifier(&rsaKey.PublicKey),
+			token:    sign(jwt.SigningMethodHS256, secret, validClaims),
+			wantErr:  true,
+		},
+		{
+			name:     "expired token",
+			verifier: NewHS256Verifier(secret),
+			token: sign(jwt.SigningMethodHS256, secret, jwt.RegisteredClaims{
+				Subject:   userID.String(),
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+			}),
+			wantErr: true,
+		},
+		{
+			name:     "token without expiration",
+			verifier: NewHS256Verifier(secret),
+			token:    sign(jwt.SigningMethodHS256, secret, jwt.RegisteredClaims{Subject: userID.String()}),
+			wantErr:  true,
+		},
+		{
+			name:     "subject is not an uuid",
+			verifier: NewHS256Verifier(secret),
+			token: sign(jwt.SigningMethodHS256, secret, jwt.RegisteredClaims{
+				Subject:   "user",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			}),
+			wantErr: true,
+		},
+		{
+			name:     "unexpected issuer",
+			verifier: NewHS256Verifier(secret, WithIssuer("other")),
+			token:    sign(jwt.SigningMethodHS256, secret, validClaims),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.verifier.Verify(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil && !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify() error = %v, want %v", err, ErrInvalidToken)
+			}
+
+			if got != tt.want {
+				t.Errorf("Verify() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
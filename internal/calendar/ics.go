@@ -0,0 +1,185 @@
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"event-service/internal/domain/event/aggregate"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+	ProductID   = "-//casper//event-service//EN"
+	UIDDomain   = "event-service"
+
+	// AccessTokenParameter carries the calendar token in the query of the calendar url, calendar clients cannot set headers
+	AccessTokenParameter = "access_token"
+
+	dateFormat = "20060102T150405Z"
+	lineLength = 75
+)
+
+// EventPath is the path of the calendar with the single event
+func EventPath(id uuid.UUID) string {
+	return "/events/" + id.String() + ".ics"
+}
+
+// UserPath is the path of the feed of the events the user takes part in
+func UserPath(id uuid.UUID) string {
+	return "/users/" + id.String() + "/calendar.ics"
+}
+
+// Calendar renders events in the iCalendar format (RFC 5545). Every event keeps the same UID
+// and increases SEQUENCE with each revision, so calendar clients refresh already imported entries
+type Calendar struct {
+	Name   string
+	Events []*aggregate.Event
+	Stamp  time.Time
+}
+
+func (c Calendar) WriteTo(w io.Writer) (int64, error) {
+	b := &builder{}
+
+	b.line("BEGIN", "VCALENDAR")
+	b.line("VERSION", "2.0")
+	b.line("PRODID", ProductID)
+	b.line("CALSCALE", "GREGORIAN")
+	b.line("METHOD", "PUBLISH")
+
+	if c.Name != "" {
+		b.line("X-WR-CALNAME", escape(c.Name))
+	}
+
+	for _, e := range c.Events {
+		c.writeEvent(b, e)
+	}
+
+	b.line("END", "VCALENDAR")
+
+	n, err := w.Write(b.Bytes())
+
+	return int64(n), err
+}
+
+func UID(e *aggregate.Event) string {
+	return fmt.Sprintf("%s@%s", e.Event.ExternalID, UIDDomain)
+}
+
+func (c Calendar) writeEvent(b *builder, e *aggregate.Event) {
+	b.line("BEGIN", "VEVENT")
+	c.writeCommon(b, e)
+	b.line("DTSTART", formatDate(e.EventPeriod.Start()))
+	b.line("DTEND", formatDate(e.EventPeriod.End()))
+
+	if e.IsRecurring() {
+		b.line("RRULE", e.Recurrence.Rule())
+
+		for _, exception := range e.Recurrence.Exceptions() {
+			b.line("EXDATE", formatDate(exception))
+		}
+
+		for _, o := range e.Overrides {
+			if o.Cancelled {
+				b.line("EXDATE", formatDate(o.OriginalStart))
+			}
+		}
+	}
+
+	b.line("END", "VEVENT")
+
+	for _, o := range e.Overrides {
+		if o.Cancelled {
+			continue
+		}
+
+		b.line("BEGIN", "VEVENT")
+		c.writeCommon(b, e)
+		b.line("RECURRENCE-ID", formatDate(o.OriginalStart))
+		b.line("DTSTART", formatDate(o.Period.Start()))
+		b.line("DTEND", formatDate(o.Period.End()))
+		b.line("END", "VEVENT")
+	}
+}
+
+func (c Calendar) writeCommon(b *builder, e *aggregate.Event) {
+	b.line("UID", UID(e))
+	b.line("DTSTAMP", formatDate(c.Stamp))
+
+	if !e.CreatedAt.IsZero() {
+		b.line("CREATED", formatDate(e.CreatedAt))
+	}
+
+	b.line("SEQUENCE", fmt.Sprint(e.Sequence))
+	b.line("SUMMARY", escape(e.Event.Name))
+
+	if e.Event.Description != "" {
+		b.line("DESCRIPTION", escape(e.Event.Description))
+	}
+
+	if e.Location != nil {
+		b.line("GEO", fmt.Sprintf("%f;%f", e.Location.Spot.Lat(), e.Location.Spot.Long()))
+	}
+
+	if e.IsCancelled() {
+		b.line("STATUS", "CANCELLED")
+	} else {
+		b.line("STATUS", "CONFIRMED")
+	}
+
+	if e.Event.Public {
+		b.line("CLASS", "PUBLIC")
+	} else {
+		b.line("CLASS", "PRIVATE")
+	}
+
+	b.line("ORGANIZER", "urn:uuid:"+e.UserID.String())
+
+	for _, p := range e.Participants {
+		b.line("ATTENDEE;PARTSTAT=ACCEPTED", "urn:uuid:"+p.String())
+	}
+}
+
+type builder struct {
+	bytes.Buffer
+}
+
+// line writes the content line folded to 75 octets, continuation lines start with a single space
+func (b *builder) line(name, value string) {
+	line := name + ":" + value
+	limit := lineLength
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = lineLength - 1
+	}
+
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+func formatDate(t time.Time) string {
+	return t.UTC().Format(dateFormat)
+}
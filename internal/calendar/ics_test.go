@@ -0,0 +1,137 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event/aggregate"
+
+	"github.com/google/uuid"
+)
+
+func newEventMock() *aggregate.Event {
+	start := time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC)
+	period, _ := valueobject.EventPeriod{}.WithStartAndDuration(start, 2*time.Hour)
+
+	return &aggregate.Event{
+		UserID: uuid.MustParse("6a1c7b8e-2f1d-4c1a-9d1e-0c3b2a1f0e9d"),
+		Event: &entity.Event{
+			ExternalID:  uuid.MustParse("0b9e4a52-3c55-4d6f-8a7b-1c2d3e4f5a6b"),
+			Name:        "Meetup; talks, beer",
+			Description: "First line\nsecond line",
+			Public:      true,
+		},
+		Location:     &aggregate.Location{Spot: valueobject.NewLocation(48.8584, 2.2945)},
+		EventPeriod:  period,
+		Participants: []uuid.UUID{uuid.MustParse("c5a1f0e9-7b6a-4d3c-8e2f-1a0b9c8d7e6f")},
+		Sequence:     3,
+	}
+}
+
+func TestCalendar_WriteTo(t *testing.T) {
+	stamp := time.Date(2029, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		event     func() *aggregate.Event
+		wantLines []string
+	}{
+		{
+			name:  "single event",
+			event: newEventMock,
+			wantLines: []string{
+				"BEGIN:VEVENT",
+				"UID:0b9e4a52-3c55-4d6f-8a7b-1c2d3e4f5a6b@event-service",
+				"DTSTAMP:20291201T100000Z",
+				"SEQUENCE:3",
+				`SUMMARY:Meetup\; talks\, beer`,
+				`DESCRIPTION:First line\nsecond line`,
+				"GEO:48.858400;2.294500",
+				"STATUS:CONFIRMED",
+				"DTSTART:20300101T180000Z",
+				"DTEND:20300101T200000Z",
+				"ORGANIZER:urn:uuid:6a1c7b8e-2f1d-4c1a-9d1e-0c3b2a1f0e9d",
+				"ATTENDEE;PARTSTAT=ACCEPTED:urn:uuid:c5a1f0e9-7b6a-4d3c-8e2f-1a0b9c8d7e6f",
+				"END:VEVENT",
+			},
+		},
+		{
+			name: "cancelled event",
+			event: func() *aggregate.Event {
+				e := newEventMock()
+				_ = e.Cancel("")
+
+				return e
+			},
+			wantLines: []string{"STATUS:CANCELLED", "SEQUENCE:4"},
+		},
+		{
+			name: "recurring event with overrides",
+			event: func() *aggregate.Event {
+				e := newEventMock()
+				e.Recurrence, _ = valueobject.NewRecurrence("FREQ=WEEKLY;COUNT=3")
+				_ = e.CancelOccurrence(time.Date(2030, 1, 8, 18, 0, 0, 0, time.UTC))
+
+				moved, _ := valueobject.EventPeriod{}.WithStartAndDuration(time.Date(2030, 1, 16, 18, 0, 0, 0, time.UTC), time.Hour)
+				_ = e.MoveOccurrence(time.Date(2030, 1, 15, 18, 0, 0, 0, time.UTC), moved)
+
+				return e
+			},
+			wantLines: []string{
+				"RRULE:FREQ=WEEKLY;COUNT=3",
+				"EXDATE:20300108T180000Z",
+				"RECURRENCE-ID:20300115T180000Z",
+				"DTSTART:20300116T180000Z",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if _, err := (Calendar{Events: []*aggregate.Event{tt.event()}, Stamp: stamp}).WriteTo(buf); err != nil {
+				t.Fatalf("WriteTo() error = %v", err)
+			}
+
+			lines := strings.Split(buf.String(), "\r\n")
+			if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-2] != "END:VCALENDAR" {
+				t.Errorf("WriteTo() is not a calendar: %q", buf.String())
+			}
+
+			for _, want := range tt.wantLines {
+				if !containsLine(lines, want) {
+					t.Errorf("WriteTo() missing line %q in %q", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestBuilder_line(t *testing.T) {
+	b := &builder{}
+	b.line("DESCRIPTION", strings.Repeat("ż", 100))
+
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(line) > lineLength {
+			t.Errorf("line() line is longer than %d octets: %q", lineLength, line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+	if unfolded != "DESCRIPTION:"+strings.Repeat("ż", 100)+"\r\n" {
+		t.Errorf("line() unfolded = %q", unfolded)
+	}
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+
+	return false
+}
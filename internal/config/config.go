@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"time"
 
 	"github.com/knadh/koanf"
 	"github.com/knadh/koanf/parsers/yaml"
@@ -51,6 +52,19 @@ func GetInt(key string) int {
 	return config.Int(key)
 }
 
+// GetDuration parses duration strings like "1s" or "5m"
+func GetDuration(key string) time.Duration {
+	loadConfig()
+
+	return config.Duration(key)
+}
+
+func GetFloat(key string) float64 {
+	loadConfig()
+
+	return config.Float64(key)
+}
+
 func GetBool(key string) bool {
 	loadConfig()
 
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	dbgrom "event-service/internal/database/gorm"
+
+	mysqldriver "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// statement is a query received by the recording driver
+type statement struct {
+	query string
+	args  []driver.NamedValue
+}
+
+// recorder is a database/sql driver keeping the statements instead of running them,
+// queries return the rows registered for the beginning of the query
+type recorder struct {
+	mu           sync.Mutex
+	statements   []statement
+	rows         map[string]*rows
+	rowsAffected int64
+	lastInsertID int64
+}
+
+func newRecorder() *recorder {
+	return &recorder{rows: map[string]*rows{}, rowsAffected: 1, lastInsertID: 1}
+}
+
+// connect returns context holding gorm connection to the recorder
+func (r *recorder) connect(t *testing.T) context.Context {
+	t.Helper()
+
+	db, err := gorm.Open(mysqldriver.New(mysqldriver.Config{
+		Conn:                      sql.OpenDB(r),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dbgrom.ContextWithConnection(context.Background(), db)
+}
+
+// returning registers rows returned by the queries starting with the prefix
+func (r *recorder) returning(prefix string, columns []string, values ...[]driver.Value) {
+	r.rows[prefix] = &rows{columns: columns, values: values}
+}
+
+// find returns the statements starting with the prefix
+func (r *recorder) find(prefix string) []statement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var found []statement
+	for _, s := range r.statements {
+		if strings.HasPrefix(s.query, prefix) {
+			found = append(found, s)
+		}
+	}
+
+	return found
+}
+
+func (r *recorder) record(query string, args []driver.NamedValue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.statements = append(r.statements, statement{query: query, args: args})
+}
+
+func (r *recorder) Connect(context.Context) (driver.Conn, error) { return r, nil }
+func (r *recorder) Driver() driver.Driver                        { return r }
+func (r *recorder) Open(string) (driver.Conn, error)             { return r, nil }
+func (r *recorder) Close() error                                 { return nil }
+func (r *recorder) Begin() (driver.Tx, error)                    { return r, nil }
+func (r *recorder) Commit() error                                { return nil }
+func (r *recorder) Rollback() error                              { return nil }
+
+func (r *recorder) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (r *recorder) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	r.record(query, args)
+
+	return r, nil
+}
+
+func (r *recorder) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	r.record(query, args)
+
+	for prefix, registered := range r.rows {
+		if strings.HasPrefix(query, prefix) {
+			return &rows{columns: registered.columns, values: registered.values}, nil
+		}
+	}
+
+	return &rows{}, nil
+}
+
+func (r *recorder) LastInsertId() (int64, error) { return r.lastInsertID, nil }
+func (r *recorder) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+
+	return nil
+}
+
+// arg returns value of the argument bound to the column in the update statement
+func (s statement) arg(column string) (driver.Value, bool) {
+	set := s.query[strings.Index(s.query, " SET ")+len(" SET "):]
+	if where := strings.Index(set, " WHERE "); where >= 0 {
+		set = set[:where]
+	}
+
+	for i, assignment := range strings.Split(set, ",") {
+		if strings.TrimSpace(assignment) == "`"+column+"`=?" && i < len(s.args) {
+			return s.args[i].Value, true
+		}
+	}
+
+	return nil, false
+}
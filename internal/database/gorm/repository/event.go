@@ -2,16 +2,21 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"time"
 
 	dbgrom "event-service/internal/database/gorm"
 	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/event"
 	"event-service/internal/domain/event/aggregate"
 	"event-service/internal/domain/event/valueobject"
+	"event-service/internal/metrics"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Event struct {
@@ -28,8 +33,18 @@ type Event struct {
 	RegistrationStartDate time.Time
 	RegistrationEndDate   time.Time
 	Public                bool
-	Location              Location `gorm:"foreignKey:LocationID"`
+	Status                entity.EventStatus
+	CancellationReason    string
+	CancelledAt           *time.Time
+	RecurrenceRule        string
+	RecurrenceExceptions  string
+	RecurrenceEnd         *time.Time
+	Sequence              uint
+	Version               uint
+	Overrides             []OccurrenceOverride `gorm:"foreignKey:EventID"`
+	Location              Location             `gorm:"foreignKey:LocationID"`
 	Invitations           []Invitation
+	Distance              float64 `gorm:"->;-:migration"`
 }
 
 func (e *Event) BeforeCreate(tx *gorm.DB) (err error) {
@@ -47,13 +62,19 @@ func (e *Event) ToEventAggregate() (entry *aggregate.Event, err error) {
 		ID:     e.ID,
 		UserID: e.User,
 		Event: &entity.Event{
-			ExternalID:  e.ExternalID,
-			Name:        e.Name,
-			Description: e.Description,
-			Capacity:    e.Capacity,
-			Public:      e.Public,
+			ExternalID:         e.ExternalID,
+			Name:               e.Name,
+			Description:        e.Description,
+			Capacity:           e.Capacity,
+			Public:             e.Public,
+			Status:             e.Status,
+			CancellationReason: e.CancellationReason,
+			CancelledAt:        e.CancelledAt,
 		},
-		Location: e.Location.toLocationAggregate(),
+		Location:  e.Location.toLocationAggregate(),
+		Sequence:  e.Sequence,
+		Version:   e.Version,
+		CreatedAt: e.CreatedAt,
 	}
 
 	entry.EventPeriod, err = entry.EventPeriod.WithStartAndEndDate(e.StartDate, e.EndDate)
@@ -66,17 +87,64 @@ func (e *Event) ToEventAggregate() (entry *aggregate.Event, err error) {
 		return
 	}
 
-	if e.Invitations != nil {
-		for _, invitation := range e.Invitations {
-			entry.Participants = append(entry.Participants, invitation.UserID)
+	if entry.Recurrence, err = recurrenceFromRecord(*e); err != nil {
+		return
+	}
+
+	for _, o := range e.Overrides {
+		override, overrideErr := o.toAggregate()
+		if overrideErr != nil {
+			return nil, overrideErr
+		}
+
+		entry.Overrides = append(entry.Overrides, override)
+	}
+
+	accepted, waitlisted := attendanceOf(e.Invitations)
+
+	for _, invitation := range accepted {
+		entry.Participants = append(entry.Participants, invitation.UserID)
+	}
+
+	for _, invitation := range waitlisted {
+		entry.Waitlist = append(entry.Waitlist, invitation.UserID)
+	}
+
+	for _, invitation := range e.Invitations {
+		if invitation.AcceptedAt == nil && invitation.WaitlistedAt == nil && invitation.RemovedAt == nil && invitation.RevokedAt == nil {
+			entry.Invitees = append(entry.Invitees, invitation.UserID)
 		}
 	}
 
 	return entry, nil
 }
 
+// attendanceOf splits the invitations into accepted ones and the waitlist ordered by time of joining it
+// and by the user for users who joined at the same time, pending invitations are left out
+func attendanceOf(invitations []Invitation) (accepted, waitlisted []Invitation) {
+	for _, invitation := range invitations {
+		switch {
+		case invitation.AcceptedAt != nil:
+			accepted = append(accepted, invitation)
+		case invitation.WaitlistedAt != nil:
+			waitlisted = append(waitlisted, invitation)
+		}
+	}
+
+	sort.SliceStable(waitlisted, func(i, j int) bool {
+		if !waitlisted[i].WaitlistedAt.Equal(*waitlisted[j].WaitlistedAt) {
+			return waitlisted[i].WaitlistedAt.Before(*waitlisted[j].WaitlistedAt)
+		}
+
+		return waitlisted[i].UserID.String() < waitlisted[j].UserID.String()
+	})
+
+	return accepted, waitlisted
+}
+
 func RecordFromEventAggregate(e aggregate.Event) Event {
 	event := Event{
+		BaseModel:             BaseModel{CreatedAt: e.CreatedAt},
 		ID:                    e.ID,
 		ExternalID:            e.Event.ExternalID,
 		User:                  e.UserID,
@@ -88,6 +156,24 @@ func RecordFromEventAggregate(e aggregate.Event) Event {
 		RegistrationStartDate: e.RegistrationPeriod.Start(),
 		RegistrationEndDate:   e.RegistrationPeriod.End(),
 		Public:                e.Event.Public,
+		Status:                e.Event.Status,
+		CancellationReason:    e.Event.CancellationReason,
+		CancelledAt:           e.Event.CancelledAt,
+		Sequence:              e.Sequence,
+		Version:               e.Version,
+	}
+
+	if e.Recurrence.IsSet() {
+		event.RecurrenceRule = e.Recurrence.Rule()
+		event.RecurrenceExceptions = encodeRecurrenceExceptions(e.Recurrence.Exceptions())
+	}
+
+	if end, ok := e.RecurrenceEnd(); ok {
+		event.RecurrenceEnd = &end
+	}
+
+	for _, o := range e.Overrides {
+		event.Overrides = append(event.Overrides, RecordFromOccurrenceOverride(e.ID, o))
 	}
 
 	if e.Location != nil {
@@ -101,78 +187,325 @@ func RecordFromEventAggregate(e aggregate.Event) Event {
 	return event
 }
 
+// attendingInvitations selects invitations of the participants and waitlisted users
+const attendingInvitations = "invitations.accepted_at IS NOT NULL OR invitations.waitlisted_at IS NOT NULL"
+
+// activeInvitations selects invitations of the users who can see the event, the participants, waitlisted users
+// and users with pending or declined invitations
+const activeInvitations = attendingInvitations + " OR (invitations.removed_at IS NULL AND invitations.revoked_at IS NULL)"
+
 type EventRepository struct{}
 
 func NewEventRepository() *EventRepository {
 	return &EventRepository{}
 }
 
-func (r EventRepository) Update(ctx context.Context, entry *aggregate.Event) error {
+// Update stores the event only when its version has not moved since the event was fetched,
+// otherwise aggregate.ErrVersionConflict is returned and nothing is changed
+func (r EventRepository) Update(ctx context.Context, entry *aggregate.Event) (err error) {
+	defer metrics.ObserveRepositoryCall("events", "Update", time.Now(), &err)
+
 	db, dbErr := dbgrom.ConnectionFromContext(ctx)
 	if dbErr != nil {
 		return errors.Wrap(dbErr, "events repository")
 	}
 
 	event := RecordFromEventAggregate(*entry)
+	event.Version++
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if entry.Location != nil {
+			location, err := resolveLocation(tx, event.Location)
+			if err != nil {
+				return errors.Wrap(err, "events repository update location")
+			}
 
-	if err := db.Updates(&event).Error; err != nil {
-		return errors.Wrap(err, "events repository update")
+			event.Location, event.LocationID = location, location.ID
+		}
+
+		// all columns are written, so the fields changed to zero values are stored as well
+		result := tx.Select("*").Omit(clause.Associations, "ExternalID", "User", "CreatedAt").
+			Where("version = ?", entry.Version).Updates(&event)
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "events repository update")
+		}
+
+		if result.RowsAffected == 0 {
+			return aggregate.ErrVersionConflict
+		}
+
+		if err := tx.Where("event_id = ?", event.ID).Delete(&OccurrenceOverride{}).Error; err != nil {
+			return errors.Wrap(err, "events repository update overrides")
+		}
+
+		if len(event.Overrides) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(&event.Overrides).Error; err != nil {
+			return errors.Wrap(err, "events repository update overrides")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	entry.Version = event.Version
+	if entry.Location != nil {
+		entry.Location.ID = event.LocationID
 	}
 
 	return nil
 }
 
-func (r EventRepository) FindBy(ctx context.Context, request valueobject.ListRequest) ([]*aggregate.Event, error) {
+// Delete removes the event with all of its invitations in a single transaction
+func (r EventRepository) Delete(ctx context.Context, entry *aggregate.Event) (err error) {
+	defer metrics.ObserveRepositoryCall("events", "Delete", time.Now(), &err)
+
 	db, dbErr := dbgrom.ConnectionFromContext(ctx)
 	if dbErr != nil {
-		return nil, errors.Wrap(dbErr, "events repository")
+		return errors.Wrap(dbErr, "events repository")
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ?", entry.ID).Delete(&Invitation{}).Error; err != nil {
+			return errors.Wrap(err, "events repository delete invitations")
+		}
+
+		if err := tx.Where("event_id = ?", entry.ID).Delete(&OccurrenceOverride{}).Error; err != nil {
+			return errors.Wrap(err, "events repository delete overrides")
+		}
+
+		if err := tx.Delete(&Event{}, entry.ID).Error; err != nil {
+			return errors.Wrap(err, "events repository delete")
+		}
+
+		return nil
+	})
+}
+
+func (r EventRepository) FindBy(ctx context.Context, request valueobject.ListRequest) (_ event.Page, err error) {
+	defer metrics.ObserveRepositoryCall("events", "FindBy", time.Now(), &err)
+
+	db, dbErr := dbgrom.ConnectionFromContext(ctx)
+	if dbErr != nil {
+		return event.Page{}, errors.Wrap(dbErr, "events repository")
 	}
 
+	db = applyListFilters(db.Model(&Event{}), request).Session(&gorm.Session{})
+
+	if interval, ok := request.Interval(); ok {
+		idle, idleErr := seriesWithoutOccurrences(db, interval.GetStartDate(), interval.GetEndDate())
+		if idleErr != nil {
+			return event.Page{}, idleErr
+		}
+
+		if len(idle) > 0 {
+			db = db.Where("events.id NOT IN ?", idle).Session(&gorm.Session{})
+		}
+	}
+
+	var totalCount int64
+	if countErr := db.Count(&totalCount).Error; countErr != nil {
+		return event.Page{}, errors.Wrap(countErr, "events repository count")
+	}
+
+	order, pagination := request.Order(), request.Pagination()
+
+	column, columnArgs, orderErr := orderColumn(order.Field(), request)
+	if orderErr != nil {
+		return event.Page{}, errors.Wrap(orderErr, "events repository find by")
+	}
+
+	if order.Field() == valueobject.OrderByDistance {
+		db = db.Joins("JOIN locations ON locations.id = events.location_id").
+			Select("events.*, "+column+" AS distance", columnArgs...)
+	}
+
+	if after, ok := pagination.After(); ok {
+		query, args, err := keysetCondition(column, columnArgs, after, order.IsDescending())
+		if err != nil {
+			return event.Page{}, err
+		}
+
+		db = db.Where(query, args...)
+	}
+
+	if before, ok := pagination.Before(); ok {
+		query, args, err := keysetCondition(column, columnArgs, before, !order.IsDescending())
+		if err != nil {
+			return event.Page{}, err
+		}
+
+		db = db.Where(query, args...)
+	}
+
+	queryOrder := order
+	if pagination.Backward() {
+		queryOrder = order.Reversed()
+	}
+
+	db = db.Clauses(clause.OrderBy{Expression: clause.Expr{
+		SQL:  fmt.Sprintf("%s %s, events.id %s", column, queryOrder.Direction(), queryOrder.Direction()),
+		Vars: columnArgs,
+	}})
+
+	var items []Event
+	if findErr := db.Preload("Location").Preload("Overrides").Limit(pagination.Limit() + 1).Find(&items).Error; findErr != nil {
+		return event.Page{}, errors.Wrap(findErr, "events repository find by")
+	}
+
+	edges := make([]event.Edge, len(items))
+
+	for i := 0; i < len(items); i++ {
+		entry, convertErr := items[i].ToEventAggregate()
+		if convertErr != nil {
+			return event.Page{}, convertErr
+		}
+
+		edges[i] = event.Edge{
+			Cursor: event.CursorFor(order.Field(), entry, items[i].Distance),
+			Event:  entry,
+		}
+	}
+
+	return event.NewPage(edges, totalCount, pagination), nil
+}
+
+func applyListFilters(db *gorm.DB, request valueobject.ListRequest) *gorm.DB {
 	if name, ok := request.Name(); ok {
-		db = db.Where("name like ?", "%"+name+"%")
+		db = db.Where("events.name like ?", "%"+name+"%")
 	}
 
 	if user, ok := request.User(); ok {
-		db = db.Where("user = ?", user)
+		db = db.Where("events.user = ?", user)
 	}
 
 	if public, ok := request.Public(); ok {
-		db = db.Where("public = ?", public)
+		db = db.Where("events.public = ?", public)
+	}
+
+	if viewer, ok := request.Viewer(); ok {
+		db = db.Where("(events.public = ? OR events.user = ? OR events.id IN (SELECT event_id FROM invitations WHERE user_id = ? AND ("+
+			activeInvitations+")))", true, viewer, viewer)
+	}
+
+	if participant, ok := request.Participant(); ok {
+		db = db.Where("events.id IN (SELECT event_id FROM invitations WHERE user_id = ? AND accepted_at IS NOT NULL)", participant)
 	}
 
 	if interval, ok := request.Interval(); ok {
-		db = db.Where("start_date BETWEEN ? AND ?", interval.GetStartDate(), interval.GetEndDate())
-		interval.GetStartDate()
+		// recurring events are narrowed by the range of the series and by the moved occurrences,
+		// FindBy drops the series without an occurrence in the interval
+		db = db.Where(`((events.recurrence_rule = '' AND events.start_date BETWEEN ? AND ?) OR
+			(events.recurrence_rule <> '' AND events.start_date <= ? AND (events.recurrence_end IS NULL OR events.recurrence_end >= ?)) OR
+			(events.recurrence_rule <> '' AND events.id IN (SELECT event_id FROM event_occurrence_overrides WHERE cancelled = false AND start_date BETWEEN ? AND ?)))`,
+			interval.GetStartDate(), interval.GetEndDate(), interval.GetEndDate(), interval.GetStartDate(),
+			interval.GetStartDate(), interval.GetEndDate())
 	}
 
 	if distance, ok := request.Distance(); ok {
-		db.Where(`location_id IN (SELECT id FROM (SELECT id, (6371 *
+		db = db.Where(`events.location_id IN (SELECT id FROM (SELECT id, `+distanceExpression("latitude", "longitude")+
+			` AS distance FROM locations HAVING distance < ? ) AS nearby)`,
+			distance.InitLatitude(), distance.InitLongitude(), distance.InitLatitude(), distance.Distance())
+	}
+
+	return db
+}
+
+// seriesWithoutOccurrences returns the recurring events matched by the filters which have no occurrence
+// between the dates, e.g. the dates fall between two occurrences or the only occurrence is cancelled
+func seriesWithoutOccurrences(db *gorm.DB, from, to time.Time) ([]uint, error) {
+	var series []Event
+	if err := db.Select("events.*").Where("events.recurrence_rule <> ''").Preload("Overrides").Find(&series).Error; err != nil {
+		return nil, errors.Wrap(err, "events repository find series")
+	}
+
+	idle := make([]uint, 0)
+
+	for i := 0; i < len(series); i++ {
+		entry, err := series[i].ToEventAggregate()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(entry.Occurrences(from, to)) == 0 {
+			idle = append(idle, series[i].ID)
+		}
+	}
+
+	return idle, nil
+}
+
+// distanceExpression returns haversine formula in kilometers, it expects origin latitude, longitude and latitude as arguments
+func distanceExpression(latitudeColumn, longitudeColumn string) string {
+	return fmt.Sprintf(`(6371 *
 				acos(cos(radians(?)) *
-				cos(radians(latitude)) *
-				cos(radians(longitude) -
+				cos(radians(%[1]s)) *
+				cos(radians(%[2]s) -
 				radians(?)) +
 				sin(radians(?)) *
-				sin(radians(latitude)))
-		) AS distance FROM locations HAVING distance < ? ) AS nearby)`,
-			distance.InitLatitude(), distance.InitLongitude(), distance.InitLatitude(), distance.Distance())
-	}
+				sin(radians(%[1]s)))
+		)`, latitudeColumn, longitudeColumn)
+}
 
-	var items []Event
-	if findErr := db.Preload("Location").Find(&items).Error; findErr != nil {
-		return nil, errors.Wrap(findErr, "events repository find by")
+func orderColumn(field valueobject.OrderField, request valueobject.ListRequest) (string, []any, error) {
+	switch field {
+	case valueobject.OrderByCreatedAt:
+		return "events.created_at", nil, nil
+	case valueobject.OrderByName:
+		return "events.name", nil, nil
+	case valueobject.OrderByDistance:
+		origin, ok := request.Origin()
+		if !ok {
+			return "", nil, valueobject.ErrDistanceOrderWithoutOrigin
+		}
+
+		return distanceExpression("locations.latitude", "locations.longitude"), []any{origin.Lat(), origin.Long(), origin.Lat()}, nil
+	default:
+		return "events.start_date", nil, nil
 	}
+}
 
-	entries := make([]*aggregate.Event, len(items))
+// keysetCondition returns condition selecting rows placed after the cursor in the given direction
+func keysetCondition(column string, columnArgs []any, cursor valueobject.Cursor, descending bool) (string, []any, error) {
+	var value any
 
-	for i := 0; i < len(items); i++ {
-		entries[i], _ = items[i].ToEventAggregate()
+	switch cursor.Field {
+	case valueobject.OrderByStartDate, valueobject.OrderByCreatedAt:
+		t, err := cursor.TimeValue()
+		if err != nil {
+			return "", nil, err
+		}
+
+		value = t
+	case valueobject.OrderByDistance:
+		f, err := cursor.FloatValue()
+		if err != nil {
+			return "", nil, err
+		}
+
+		value = f
+	default:
+		value = cursor.Value
+	}
+
+	operator := ">"
+	if descending {
+		operator = "<"
 	}
 
-	return entries, nil
+	args := append(append([]any{}, columnArgs...), value)
+	args = append(append(args, columnArgs...), value, cursor.ID)
 
+	return fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND events.id %[2]s ?))", column, operator), args, nil
 }
 
-func (r EventRepository) FindByExternalID(ctx context.Context, id uuid.UUID) (*aggregate.Event, error) {
+func (r EventRepository) FindByExternalID(ctx context.Context, id uuid.UUID) (_ *aggregate.Event, err error) {
+	defer metrics.ObserveRepositoryCall("events", "FindByExternalID", time.Now(), &err)
+
 	db, dbErr := dbgrom.ConnectionFromContext(ctx)
 	if dbErr != nil {
 		return nil, errors.Wrap(dbErr, "events repository")
@@ -180,14 +513,48 @@ func (r EventRepository) FindByExternalID(ctx context.Context, id uuid.UUID) (*a
 
 	item := Event{}
 
-	if findErr := db.Preload("Location").Preload("Invitations").First(&item, "external_id = ?", id).Error; findErr != nil {
+	if findErr := db.Preload("Location").Preload("Invitations", activeInvitations).Preload("Overrides").
+		First(&item, "external_id = ?", id).Error; findErr != nil {
+		if errors.Is(findErr, gorm.ErrRecordNotFound) {
+			return nil, errors.Wrap(event.ErrNotFound, "events repository find by external ID")
+		}
+
 		return nil, errors.Wrap(findErr, "events repository find by external ID")
 	}
 
 	return item.ToEventAggregate()
 }
 
-func (r EventRepository) Add(ctx context.Context, entry *aggregate.Event) error {
+func (r EventRepository) FindByExternalIDs(ctx context.Context, ids []uuid.UUID) (_ []*aggregate.Event, err error) {
+	defer metrics.ObserveRepositoryCall("events", "FindByExternalIDs", time.Now(), &err)
+
+	db, dbErr := dbgrom.ConnectionFromContext(ctx)
+	if dbErr != nil {
+		return nil, errors.Wrap(dbErr, "events repository")
+	}
+
+	var items []Event
+	if findErr := db.Preload("Location").Preload("Invitations", activeInvitations).Preload("Overrides").
+		Find(&items, "external_id IN ?", ids).Error; findErr != nil {
+		return nil, errors.Wrap(findErr, "events repository find by external IDs")
+	}
+
+	events := make([]*aggregate.Event, 0, len(items))
+	for _, item := range items {
+		entry, convertErr := item.ToEventAggregate()
+		if convertErr != nil {
+			return nil, convertErr
+		}
+
+		events = append(events, entry)
+	}
+
+	return events, nil
+}
+
+func (r EventRepository) Add(ctx context.Context, entry *aggregate.Event) (err error) {
+	defer metrics.ObserveRepositoryCall("events", "Add", time.Now(), &err)
+
 	db, dbErr := dbgrom.ConnectionFromContext(ctx)
 	if dbErr != nil {
 		return errors.Wrap(dbErr, "events repository")
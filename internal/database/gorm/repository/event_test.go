@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"event-service/internal/domain/common/entity"
+	commonvalueobject "event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/valueobject"
+
+	"github.com/google/uuid"
+)
+
+func TestEventRepository_UpdateZeroValuesAndLocation(t *testing.T) {
+	db := newRecorder()
+	db.lastInsertID = 7
+	ctx := db.connect(t)
+
+	entry := newEventMock()
+	entry.Event.Capacity, entry.Event.Public, entry.Event.Description = 0, false, ""
+	entry.Location = &aggregate.Location{ID: 3, Spot: commonvalueobject.NewLocation(52.2297, 21.0122)}
+
+	if err := NewEventRepository().Update(ctx, entry); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	updates := db.find("UPDATE `events`")
+	if len(updates) != 1 {
+		t.Fatalf("Update() sent %d event updates, want 1", len(updates))
+	}
+
+	for column, want := range map[string]any{
+		"capacity":    int64(0),
+		"public":      false,
+		"description": "",
+		"location_id": int64(7),
+		"version":     int64(5),
+	} {
+		got, ok := updates[0].arg(column)
+		if !ok {
+			t.Errorf("Update() does not store column %s", column)
+			continue
+		}
+
+		if got != want {
+			t.Errorf("Update() column %s = %v, want %v", column, got, want)
+		}
+	}
+
+	if inserts := db.find("INSERT INTO `locations`"); len(inserts) != 1 {
+		t.Errorf("Update() created %d locations, want 1", len(inserts))
+	}
+
+	if changed := db.find("UPDATE `locations`"); len(changed) > 0 {
+		t.Errorf("Update() changed the shared location row: %v", changed[0].query)
+	}
+
+	if entry.Version != 5 || entry.Location.ID != 7 {
+		t.Errorf("Update() version = %v, location = %v, want 5 and 7", entry.Version, entry.Location.ID)
+	}
+}
+
+func TestEventRepository_UpdateVersionConflict(t *testing.T) {
+	db := newRecorder()
+	db.rowsAffected = 0
+	ctx := db.connect(t)
+
+	if err := NewEventRepository().Update(ctx, newEventMock()); !errors.Is(err, aggregate.ErrVersionConflict) {
+		t.Errorf("Update() error = %v, want %v", err, aggregate.ErrVersionConflict)
+	}
+}
+
+func TestEventRepository_FindByInvalidRecord(t *testing.T) {
+	db := newRecorder()
+	db.returning("SELECT count(*) FROM `events`", []string{"count"}, []driver.Value{int64(1)})
+	db.returning("SELECT * FROM `events`", []string{"id", "external_id", "name", "start_date", "end_date"},
+		[]driver.Value{int64(1), uuid.New().String(), "event", time.Now().Add(time.Hour), time.Now()})
+	ctx := db.connect(t)
+
+	if _, err := NewEventRepository().FindBy(ctx, valueobject.NewListRequest()); !errors.Is(err, commonvalueobject.ErrPeriodInvalidDates) {
+		t.Errorf("FindBy() error = %v, want %v", err, commonvalueobject.ErrPeriodInvalidDates)
+	}
+}
+
+func TestEventRepository_FindByViewer(t *testing.T) {
+	db := newRecorder()
+	db.returning("SELECT count(*) FROM `events`", []string{"count"}, []driver.Value{int64(0)})
+	viewer := uuid.New()
+
+	if _, err := NewEventRepository().FindBy(db.connect(t), valueobject.NewListRequest(valueobject.WithViewer(viewer))); err != nil {
+		t.Fatalf("FindBy() error = %v", err)
+	}
+
+	counts := db.find("SELECT count(*) FROM `events`")
+	if len(counts) != 1 || !strings.Contains(counts[0].query, "events.public = ? OR events.user = ? OR events.id IN (SELECT event_id FROM invitations") {
+		t.Fatalf("FindBy() queries = %v, want events visible to the viewer", counts)
+	}
+
+	if args := counts[0].args; len(args) != 3 || args[0].Value != true || args[1].Value != viewer.String() || args[2].Value != viewer.String() {
+		t.Errorf("FindBy() visibility arguments = %v, want public and the viewer %s", args, viewer)
+	}
+}
+
+func TestEventRepository_FindByUpcomingOccurrences(t *testing.T) {
+	db := newRecorder()
+	monday := time.Date(2026, time.January, 5, 10, 0, 0, 0, time.UTC)
+	registration := monday.AddDate(0, 0, -7)
+	db.returning("SELECT events.* FROM `events`", []string{"id", "external_id", "name", "start_date", "end_date", "registration_start_date", "registration_end_date", "recurrence_rule"},
+		[]driver.Value{int64(1), uuid.New().String(), "between occurrences", monday, monday.Add(time.Hour), registration, monday, "FREQ=WEEKLY;COUNT=4"},
+		[]driver.Value{int64(2), uuid.New().String(), "cancelled occurrence", monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 2).Add(time.Hour), registration, monday.AddDate(0, 0, 2), "FREQ=WEEKLY;COUNT=4"},
+		[]driver.Value{int64(3), uuid.New().String(), "occurrence", monday.AddDate(0, 0, 3), monday.AddDate(0, 0, 3).Add(time.Hour), registration, monday.AddDate(0, 0, 3), "FREQ=WEEKLY;COUNT=4"})
+	db.returning("SELECT * FROM `event_occurrence_overrides`", []string{"event_id", "original_start_date", "cancelled"},
+		[]driver.Value{int64(2), monday.AddDate(0, 0, 2), true})
+	db.returning("SELECT count(*) FROM `events`", []string{"count"}, []driver.Value{int64(1)})
+
+	request := valueobject.NewListRequest(valueobject.WithTimeInterval(monday.AddDate(0, 0, 2).Add(12*time.Hour), 36*time.Hour))
+	if _, err := NewEventRepository().FindBy(db.connect(t), request); err != nil {
+		t.Fatalf("FindBy() error = %v", err)
+	}
+
+	counts := db.find("SELECT count(*) FROM `events`")
+	if len(counts) != 1 || !strings.Contains(counts[0].query, "events.id NOT IN (?,?)") {
+		t.Fatalf("FindBy() queries = %v, want the series without occurrences left out", counts)
+	}
+
+	if args := counts[0].args; args[len(args)-2].Value != int64(1) || args[len(args)-1].Value != int64(2) {
+		t.Errorf("FindBy() left out %v, want the series 1 and 2", args[len(args)-2:])
+	}
+}
+
+func TestEventRepository_FindByExternalIDNotFound(t *testing.T) {
+	ctx := newRecorder().connect(t)
+
+	if _, err := NewEventRepository().FindByExternalID(ctx, uuid.New()); !errors.Is(err, event.ErrNotFound) {
+		t.Errorf("FindByExternalID() error = %v, want %v", err, event.ErrNotFound)
+	}
+}
+
+func TestAttendanceOf_WaitlistTiebreak(t *testing.T) {
+	joinedAt := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	earlier := joinedAt.Add(-time.Microsecond)
+	first, second := uuid.MustParse("00000000-0000-0000-0000-000000000001"), uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	earliest := uuid.MustParse("00000000-0000-0000-0000-000000000003")
+
+	_, waitlisted := attendanceOf([]Invitation{
+		{UserID: second, WaitlistedAt: &joinedAt},
+		{UserID: first, WaitlistedAt: &joinedAt},
+		{UserID: earliest, WaitlistedAt: &earlier},
+	})
+
+	var got []uuid.UUID
+	for _, invitation := range waitlisted {
+		got = append(got, invitation.UserID)
+	}
+
+	if want := []uuid.UUID{earliest, first, second}; !reflect.DeepEqual(got, want) {
+		t.Errorf("attendanceOf() waitlist = %v, want %v", got, want)
+	}
+}
+
+func newEventMock() *aggregate.Event {
+	e := &aggregate.Event{
+		ID:     1,
+		UserID: uuid.New(),
+		Event: &entity.Event{
+			ExternalID:  uuid.New(),
+			Name:        "event",
+			Description: "description",
+			Capacity:    10,
+			Public:      true,
+		},
+		Location: &aggregate.Location{ID: 3, Spot: commonvalueobject.NewLocation(48.8584, 2.2945)},
+		Version:  4,
+	}
+
+	e.EventPeriod, _ = e.EventPeriod.WithStartAndDuration(time.Now().Add(time.Hour), time.Hour)
+	e.RegistrationPeriod, _ = e.RegistrationPeriod.WithStartAndEndDate(time.Now(), time.Now().Add(time.Hour))
+
+	return e
+}
@@ -2,29 +2,34 @@ package repository
 
 import (
 	"context"
-	"gorm.io/gorm"
 	"time"
 
 	dbgrom "event-service/internal/database/gorm"
+	"event-service/internal/domain/event"
 	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/metrics"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Invitation struct {
 	BaseModel
-	EventID    uint      `gorm:"primaryKey;autoIncrement:false"`
-	UserID     uuid.UUID `gorm:"primaryKey;"`
-	AcceptedAt *time.Time
-	Event      Event `gorm:"foreignKey:EventID"`
+	EventID      uint      `gorm:"primaryKey;autoIncrement:false"`
+	UserID       uuid.UUID `gorm:"primaryKey;"`
+	InvitedBy    *uuid.UUID
+	AcceptedAt   *time.Time
+	WaitlistedAt *time.Time
+	DeclinedAt   *time.Time
+	RevokedAt    *time.Time
+	RemovedAt    *time.Time
+	Event        Event `gorm:"foreignKey:EventID"`
 }
 
 func (i Invitation) toAggregate() (ia *aggregate.Invitation, err error) {
-	ia = &aggregate.Invitation{
-		InvitedUser: i.UserID,
-		AcceptedAt:  i.AcceptedAt,
-	}
+	ia = i.toListedAggregate()
 
 	if ia.Event, err = i.Event.ToEventAggregate(); err != nil {
 		return nil, err
@@ -33,12 +38,58 @@ func (i Invitation) toAggregate() (ia *aggregate.Invitation, err error) {
 	return ia, nil
 }
 
+// toListedAggregate returns the invitation without the event, as the listed invitations come without it
+func (i Invitation) toListedAggregate() *aggregate.Invitation {
+	ia := &aggregate.Invitation{
+		InvitedUser:  i.UserID,
+		InvitedAt:    i.CreatedAt,
+		AcceptedAt:   i.AcceptedAt,
+		WaitlistedAt: i.WaitlistedAt,
+		DeclinedAt:   i.DeclinedAt,
+		RevokedAt:    i.RevokedAt,
+		RemovedAt:    i.RemovedAt,
+	}
+
+	if i.InvitedBy != nil {
+		ia.InvitedBy = *i.InvitedBy
+	}
+
+	return ia
+}
+
 func RecordFromInvitationAggregate(i aggregate.Invitation) Invitation {
-	return Invitation{
-		EventID:    i.Event.ID,
-		UserID:     i.InvitedUser,
-		AcceptedAt: i.AcceptedAt,
+	record := Invitation{
+		BaseModel:    BaseModel{CreatedAt: i.InvitedAt},
+		EventID:      i.Event.ID,
+		UserID:       i.InvitedUser,
+		AcceptedAt:   i.AcceptedAt,
+		WaitlistedAt: i.WaitlistedAt,
+		DeclinedAt:   i.DeclinedAt,
+		RevokedAt:    i.RevokedAt,
+		RemovedAt:    i.RemovedAt,
+	}
+
+	if i.InvitedBy != uuid.Nil {
+		invitedBy := i.InvitedBy
+		record.InvitedBy = &invitedBy
 	}
+
+	return record
+}
+
+// invitationStatusConditions select invitations of the status in the same order of precedence
+// aggregate.Invitation applies
+var invitationStatusConditions = map[aggregate.InvitationStatus]string{
+	aggregate.InvitationAccepted:   "invitations.accepted_at IS NOT NULL",
+	aggregate.InvitationWaitlisted: "invitations.accepted_at IS NULL AND invitations.waitlisted_at IS NOT NULL",
+	aggregate.InvitationRemoved: "invitations.accepted_at IS NULL AND invitations.waitlisted_at IS NULL AND " +
+		"invitations.removed_at IS NOT NULL",
+	aggregate.InvitationRevoked: "invitations.accepted_at IS NULL AND invitations.waitlisted_at IS NULL AND " +
+		"invitations.removed_at IS NULL AND invitations.revoked_at IS NOT NULL",
+	aggregate.InvitationDeclined: "invitations.accepted_at IS NULL AND invitations.waitlisted_at IS NULL AND " +
+		"invitations.removed_at IS NULL AND invitations.revoked_at IS NULL AND invitations.declined_at IS NOT NULL",
+	aggregate.InvitationInvited: "invitations.accepted_at IS NULL AND invitations.waitlisted_at IS NULL AND " +
+		"invitations.removed_at IS NULL AND invitations.revoked_at IS NULL AND invitations.declined_at IS NULL",
 }
 
 type InvitationRepository struct{}
@@ -47,7 +98,9 @@ func NewInvitationRepository() *InvitationRepository {
 	return &InvitationRepository{}
 }
 
-func (r InvitationRepository) FindBy(ctx context.Context, eventID, userID uuid.UUID) (*aggregate.Invitation, error) {
+func (r InvitationRepository) FindBy(ctx context.Context, eventID, userID uuid.UUID) (_ *aggregate.Invitation, err error) {
+	defer metrics.ObserveRepositoryCall("invitations", "FindBy", time.Now(), &err)
+
 	db, dbErr := dbgrom.ConnectionFromContext(ctx)
 	if dbErr != nil {
 		return nil, errors.Wrap(dbErr, "invitation repository")
@@ -58,7 +111,8 @@ func (r InvitationRepository) FindBy(ctx context.Context, eventID, userID uuid.U
 	}
 
 	if findErr := db.Joins("JOIN events on events.id = invitations.event_id AND events.external_id = ?", eventID).
-		Preload("Event").
+		Preload("Event.Location").
+		Preload("Event.Invitations", activeInvitations).
 		First(&item).Error; findErr != nil {
 		if errors.Is(findErr, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -70,7 +124,9 @@ func (r InvitationRepository) FindBy(ctx context.Context, eventID, userID uuid.U
 	return item.toAggregate()
 }
 
-func (r InvitationRepository) Invite(ctx context.Context, invitation *aggregate.Invitation) error {
+func (r InvitationRepository) Invite(ctx context.Context, invitation *aggregate.Invitation) (err error) {
+	defer metrics.ObserveRepositoryCall("invitations", "Invite", time.Now(), &err)
+
 	db, dbErr := dbgrom.ConnectionFromContext(ctx)
 	if dbErr != nil {
 		return errors.Wrap(dbErr, "invitation repository")
@@ -78,14 +134,81 @@ func (r InvitationRepository) Invite(ctx context.Context, invitation *aggregate.
 
 	record := RecordFromInvitationAggregate(*invitation)
 
-	if err := db.Create(&record).Error; err != nil {
-		return errors.Wrap(err, "invitation repository invite")
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := reserveSpot(tx, record); err != nil {
+			return err
+		}
+
+		// the removed user is invited again with the new invitation
+		if err := tx.Save(&record).Error; err != nil {
+			return errors.Wrap(err, "invitation repository invite")
+		}
+
+		if record.AcceptedAt == nil && record.WaitlistedAt == nil {
+			return nil
+		}
+
+		return moveEventVersion(tx, record.EventID)
+	})
+}
+
+func (r InvitationRepository) Accept(ctx context.Context, invitation *aggregate.Invitation) (err error) {
+	defer metrics.ObserveRepositoryCall("invitations", "Accept", time.Now(), &err)
+
+	db, dbErr := dbgrom.ConnectionFromContext(ctx)
+	if dbErr != nil {
+		return errors.Wrap(dbErr, "invitation repository")
 	}
 
-	return nil
+	record := RecordFromInvitationAggregate(*invitation)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := reserveSpot(tx, record); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&record).Error; err != nil {
+			return errors.Wrap(err, "invitation repository accept")
+		}
+
+		return moveEventVersion(tx, record.EventID)
+	})
+}
+
+func (r InvitationRepository) Remove(ctx context.Context, invitation *aggregate.Invitation) (err error) {
+	defer metrics.ObserveRepositoryCall("invitations", "Remove", time.Now(), &err)
+
+	db, dbErr := dbgrom.ConnectionFromContext(ctx)
+	if dbErr != nil {
+		return errors.Wrap(dbErr, "invitation repository")
+	}
+
+	record := RecordFromInvitationAggregate(*invitation)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&record).Error; err != nil {
+			return errors.Wrap(err, "invitation repository remove")
+		}
+
+		return moveEventVersion(tx, record.EventID)
+	})
+}
+
+// Decline stores the answer to the pending invitation, participants of the event do not change
+func (r InvitationRepository) Decline(ctx context.Context, invitation *aggregate.Invitation) (err error) {
+	defer metrics.ObserveRepositoryCall("invitations", "Decline", time.Now(), &err)
+
+	return r.savePending(ctx, invitation, "invitation repository decline")
+}
+
+// Revoke stores the withdrawn pending invitation, participants of the event do not change
+func (r InvitationRepository) Revoke(ctx context.Context, invitation *aggregate.Invitation) (err error) {
+	defer metrics.ObserveRepositoryCall("invitations", "Revoke", time.Now(), &err)
+
+	return r.savePending(ctx, invitation, "invitation repository revoke")
 }
 
-func (r InvitationRepository) Accept(ctx context.Context, invitation *aggregate.Invitation) error {
+func (r InvitationRepository) savePending(ctx context.Context, invitation *aggregate.Invitation, operation string) error {
 	db, dbErr := dbgrom.ConnectionFromContext(ctx)
 	if dbErr != nil {
 		return errors.Wrap(dbErr, "invitation repository")
@@ -94,22 +217,128 @@ func (r InvitationRepository) Accept(ctx context.Context, invitation *aggregate.
 	record := RecordFromInvitationAggregate(*invitation)
 
 	if err := db.Save(&record).Error; err != nil {
-		return errors.Wrap(err, "invitation repository accept")
+		return errors.Wrap(err, operation)
 	}
 
 	return nil
 }
 
-func (r InvitationRepository) Remove(ctx context.Context, invitation *aggregate.Invitation) error {
+// FindParticipants loads accepted and waitlisted invitations of all the events with a single query
+func (r InvitationRepository) FindParticipants(ctx context.Context, eventIDs []uuid.UUID) (_ map[uuid.UUID][]*aggregate.Invitation, err error) {
+	defer metrics.ObserveRepositoryCall("invitations", "FindParticipants", time.Now(), &err)
+
 	db, dbErr := dbgrom.ConnectionFromContext(ctx)
 	if dbErr != nil {
-		return errors.Wrap(dbErr, "invitation repository")
+		return nil, errors.Wrap(dbErr, "invitation repository")
 	}
 
-	record := RecordFromInvitationAggregate(*invitation)
+	var rows []struct {
+		EventExternalID uuid.UUID
+		Invitation
+	}
+
+	if findErr := db.Model(&Invitation{}).
+		Select("events.external_id AS event_external_id, invitations.*").
+		Joins("JOIN events ON events.id = invitations.event_id").
+		Where("events.external_id IN ?", eventIDs).
+		Where(attendingInvitations).
+		Order("invitations.created_at, invitations.user_id").
+		Scan(&rows).Error; findErr != nil {
+		return nil, errors.Wrap(findErr, "invitation repository find participants")
+	}
+
+	invitations := make(map[uuid.UUID][]Invitation, len(eventIDs))
+	for _, row := range rows {
+		invitations[row.EventExternalID] = append(invitations[row.EventExternalID], row.Invitation)
+	}
+
+	participants := make(map[uuid.UUID][]*aggregate.Invitation, len(invitations))
+	for id, eventInvitations := range invitations {
+		accepted, waitlisted := attendanceOf(eventInvitations)
+
+		for _, invitation := range append(accepted, waitlisted...) {
+			participants[id] = append(participants[id], invitation.toListedAggregate())
+		}
+	}
+
+	return participants, nil
+}
+
+func (r InvitationRepository) FindByEvent(ctx context.Context, eventID uuid.UUID, request event.ParticipantsRequest) (_ event.ParticipantPage, err error) {
+	defer metrics.ObserveRepositoryCall("invitations", "FindByEvent", time.Now(), &err)
+
+	db, dbErr := dbgrom.ConnectionFromContext(ctx)
+	if dbErr != nil {
+		return event.ParticipantPage{}, errors.Wrap(dbErr, "invitation repository")
+	}
+
+	db = db.Model(&Invitation{}).Joins("JOIN events ON events.id = invitations.event_id AND events.external_id = ?", eventID)
+
+	if request.Status != nil {
+		db = db.Where(invitationStatusConditions[*request.Status])
+	}
+
+	db = db.Session(&gorm.Session{})
+
+	var totalCount int64
+	if countErr := db.Count(&totalCount).Error; countErr != nil {
+		return event.ParticipantPage{}, errors.Wrap(countErr, "invitation repository count")
+	}
+
+	if request.After != nil {
+		db = db.Where("(invitations.created_at > ? OR (invitations.created_at = ? AND invitations.user_id > ?))",
+			request.After.InvitedAt, request.After.InvitedAt, request.After.User)
+	}
+
+	var items []Invitation
+	if findErr := db.Order("invitations.created_at, invitations.user_id").
+		Limit(request.Limit + 1).Find(&items).Error; findErr != nil {
+		return event.ParticipantPage{}, errors.Wrap(findErr, "invitation repository find by event")
+	}
+
+	edges := make([]event.ParticipantEdge, len(items))
+	for i, item := range items {
+		invitation := item.toListedAggregate()
+		edges[i] = event.ParticipantEdge{Cursor: event.ParticipantCursorFor(invitation), Invitation: invitation}
+	}
+
+	return event.NewParticipantPage(edges, totalCount, request), nil
+}
+
+// reserveSpot locks the event row until the end of the transaction, so participants of the event change one
+// at a time and concurrent requests cannot take the same last spot, the accepted invitation that does not fit
+// the event is rejected with aggregate.ErrCapacityFull
+func reserveSpot(tx *gorm.DB, record Invitation) error {
+	var locked Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "capacity").
+		First(&locked, record.EventID).Error; err != nil {
+		return errors.Wrap(err, "invitation repository lock event")
+	}
+
+	if record.AcceptedAt == nil {
+		return nil
+	}
+
+	var accepted int64
+	if err := tx.Model(&Invitation{}).
+		Where("event_id = ? AND user_id <> ? AND accepted_at IS NOT NULL", record.EventID, record.UserID).
+		Count(&accepted).Error; err != nil {
+		return errors.Wrap(err, "invitation repository count participants")
+	}
+
+	if accepted >= int64(locked.Capacity) {
+		return aggregate.ErrCapacityFull
+	}
+
+	return nil
+}
 
-	if err := db.Delete(&record).Error; err != nil {
-		return errors.Wrap(err, "invitation repository accept")
+// moveEventVersion marks the change of the participants, so the updates of the event fetched before fail with conflict
+func moveEventVersion(tx *gorm.DB, eventID uint) error {
+	if err := tx.Model(&Event{}).Where("id = ?", eventID).
+		UpdateColumn("version", gorm.Expr("version + 1")).Error; err != nil {
+		return errors.Wrap(err, "invitation repository move event version")
 	}
 
 	return nil
@@ -3,6 +3,8 @@ package repository
 import (
 	"event-service/internal/domain/common/valueobject"
 	"event-service/internal/domain/event/aggregate"
+
+	"gorm.io/gorm"
 )
 
 type Location struct {
@@ -27,3 +29,15 @@ func RecordFromLocationAggregate(l aggregate.Location) Location {
 		Longitude: l.Spot.Long(),
 	}
 }
+
+// resolveLocation finds the location with the same coordinates or creates it, locations are shared
+// between events, so the row of another event is never changed in place
+func resolveLocation(tx *gorm.DB, l Location) (Location, error) {
+	location := Location{}
+
+	err := tx.Where("latitude = ? AND longitude = ?", l.Latitude, l.Longitude).
+		Attrs(Location{Latitude: l.Latitude, Longitude: l.Longitude}).
+		FirstOrCreate(&location).Error
+
+	return location, err
+}
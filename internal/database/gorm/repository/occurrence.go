@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event/aggregate"
+)
+
+type OccurrenceOverride struct {
+	EventID           uint      `gorm:"primaryKey;autoIncrement:false"`
+	OriginalStartDate time.Time `gorm:"primaryKey"`
+	StartDate         *time.Time
+	EndDate           *time.Time
+	Cancelled         bool
+}
+
+func (OccurrenceOverride) TableName() string {
+	return "event_occurrence_overrides"
+}
+
+func (o OccurrenceOverride) toAggregate() (override aggregate.OccurrenceOverride, err error) {
+	override = aggregate.OccurrenceOverride{
+		OriginalStart: o.OriginalStartDate,
+		Cancelled:     o.Cancelled,
+	}
+
+	if o.Cancelled || o.StartDate == nil || o.EndDate == nil {
+		return override, nil
+	}
+
+	if override.Period, err = override.Period.WithStartAndEndDate(*o.StartDate, *o.EndDate); err != nil {
+		return aggregate.OccurrenceOverride{}, err
+	}
+
+	return override, nil
+}
+
+func RecordFromOccurrenceOverride(eventID uint, o aggregate.OccurrenceOverride) OccurrenceOverride {
+	record := OccurrenceOverride{
+		EventID:           eventID,
+		OriginalStartDate: o.OriginalStart,
+		Cancelled:         o.Cancelled,
+	}
+
+	if !o.Cancelled {
+		start, end := o.Period.Start(), o.Period.End()
+		record.StartDate, record.EndDate = &start, &end
+	}
+
+	return record
+}
+
+// encodeRecurrenceExceptions keeps excluded dates as comma separated RFC3339 dates
+func encodeRecurrenceExceptions(exceptions []time.Time) string {
+	dates := make([]string, len(exceptions))
+	for i, exception := range exceptions {
+		dates[i] = exception.UTC().Format(time.RFC3339)
+	}
+
+	return strings.Join(dates, ",")
+}
+
+func decodeRecurrenceExceptions(value string) []time.Time {
+	if value == "" {
+		return nil
+	}
+
+	exceptions := make([]time.Time, 0)
+	for _, date := range strings.Split(value, ",") {
+		if exception, err := time.Parse(time.RFC3339, date); err == nil {
+			exceptions = append(exceptions, exception)
+		}
+	}
+
+	return exceptions
+}
+
+func recurrenceFromRecord(e Event) (valueobject.Recurrence, error) {
+	return valueobject.NewRecurrence(e.RecurrenceRule, decodeRecurrenceExceptions(e.RecurrenceExceptions)...)
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	dbgrom "event-service/internal/database/gorm"
+	"event-service/internal/outbox"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxMessage struct {
+	ID            uint `gorm:"primaryKey"`
+	Exchange      string
+	RoutingKey    string
+	Type          string
+	Headers       string
+	Body          []byte
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	SentAt        *time.Time
+	DeadAt        *time.Time
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
+
+func (m OutboxMessage) toMessage() (outbox.Message, error) {
+	message := outbox.Message{
+		ID:            m.ID,
+		Exchange:      m.Exchange,
+		RoutingKey:    m.RoutingKey,
+		Type:          m.Type,
+		Body:          m.Body,
+		Attempts:      m.Attempts,
+		LastError:     m.LastError,
+		NextAttemptAt: m.NextAttemptAt,
+		CreatedAt:     m.CreatedAt,
+		SentAt:        m.SentAt,
+		DeadAt:        m.DeadAt,
+	}
+
+	if m.Headers != "" {
+		if err := json.Unmarshal([]byte(m.Headers), &message.Headers); err != nil {
+			return outbox.Message{}, err
+		}
+	}
+
+	return message, nil
+}
+
+func RecordFromOutboxMessage(m outbox.Message) (OutboxMessage, error) {
+	record := OutboxMessage{
+		ID:            m.ID,
+		Exchange:      m.Exchange,
+		RoutingKey:    m.RoutingKey,
+		Type:          m.Type,
+		Body:          m.Body,
+		Attempts:      m.Attempts,
+		LastError:     m.LastError,
+		NextAttemptAt: m.NextAttemptAt,
+		CreatedAt:     m.CreatedAt,
+		SentAt:        m.SentAt,
+		DeadAt:        m.DeadAt,
+	}
+
+	if len(m.Headers) > 0 {
+		headers, err := json.Marshal(m.Headers)
+		if err != nil {
+			return OutboxMessage{}, err
+		}
+
+		record.Headers = string(headers)
+	}
+
+	return record, nil
+}
+
+// OutboxRepository keeps outbox messages in the database, messages are added in the transaction from the context
+type OutboxRepository struct{}
+
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{}
+}
+
+func (r OutboxRepository) Add(ctx context.Context, m *outbox.Message) error {
+	db, dbErr := dbgrom.ConnectionFromContext(ctx)
+	if dbErr != nil {
+		return errors.Wrap(dbErr, "outbox repository")
+	}
+
+	record, recordErr := RecordFromOutboxMessage(*m)
+	if recordErr != nil {
+		return errors.Wrap(recordErr, "outbox repository add")
+	}
+
+	if err := db.Create(&record).Error; err != nil {
+		return errors.Wrap(err, "outbox repository add")
+	}
+
+	m.ID = record.ID
+
+	return nil
+}
+
+func (r OutboxRepository) Pending(ctx context.Context, limit int) ([]outbox.Message, error) {
+	db, dbErr := dbgrom.ConnectionFromContext(ctx)
+	if dbErr != nil {
+		return nil, errors.Wrap(dbErr, "outbox repository")
+	}
+
+	// rows claimed by another relay are skipped, the batch is then published only if no older message is claimed,
+	// otherwise the messages would be published out of order
+	var records []OutboxMessage
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("sent_at IS NULL AND dead_at IS NULL").Order("id").Limit(limit).Find(&records).Error; err != nil {
+		return nil, errors.Wrap(err, "outbox repository pending")
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	var claimed int64
+	if err := db.Model(&OutboxMessage{}).Where("sent_at IS NULL AND dead_at IS NULL AND id < ?", records[0].ID).Count(&claimed).Error; err != nil {
+		return nil, errors.Wrap(err, "outbox repository pending")
+	}
+
+	if claimed > 0 {
+		return nil, nil
+	}
+
+	messages := make([]outbox.Message, 0, len(records))
+	for _, record := range records {
+		m, err := record.toMessage()
+		if err != nil {
+			return nil, errors.Wrap(err, "outbox repository pending")
+		}
+
+		messages = append(messages, m)
+	}
+
+	return messages, nil
+}
+
+func (r OutboxRepository) MarkSent(ctx context.Context, id uint, at time.Time) error {
+	db, dbErr := dbgrom.ConnectionFromContext(ctx)
+	if dbErr != nil {
+		return errors.Wrap(dbErr, "outbox repository")
+	}
+
+	if err := db.Model(&OutboxMessage{ID: id}).Update("sent_at", at).Error; err != nil {
+		return errors.Wrap(err, "outbox repository mark sent")
+	}
+
+	return nil
+}
+
+func (r OutboxRepository) MarkFailed(ctx context.Context, id uint, reason string, retryAt time.Time) error {
+	db, dbErr := dbgrom.ConnectionFromContext(ctx)
+	if dbErr != nil {
+		return errors.Wrap(dbErr, "outbox repository")
+	}
+
+	if err := db.Model(&OutboxMessage{ID: id}).Updates(map[string]any{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      reason,
+		"next_attempt_at": retryAt,
+	}).Error; err != nil {
+		return errors.Wrap(err, "outbox repository mark failed")
+	}
+
+	return nil
+}
+
+func (r OutboxRepository) MarkDead(ctx context.Context, id uint, reason string, at time.Time) error {
+	db, dbErr := dbgrom.ConnectionFromContext(ctx)
+	if dbErr != nil {
+		return errors.Wrap(dbErr, "outbox repository")
+	}
+
+	if err := db.Model(&OutboxMessage{ID: id}).Updates(map[string]any{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": reason,
+		"dead_at":    at,
+	}).Error; err != nil {
+		return errors.Wrap(err, "outbox repository mark dead")
+	}
+
+	return nil
+}
+
+func (r OutboxRepository) Purge(ctx context.Context, sentBefore time.Time) (int64, error) {
+	db, dbErr := dbgrom.ConnectionFromContext(ctx)
+	if dbErr != nil {
+		return 0, errors.Wrap(dbErr, "outbox repository")
+	}
+
+	result := db.Where("sent_at < ? OR dead_at < ?", sentBefore, sentBefore).Delete(&OutboxMessage{})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "outbox repository purge")
+	}
+
+	return result.RowsAffected, nil
+}
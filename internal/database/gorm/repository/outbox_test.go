@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOutboxRepository_Pending(t *testing.T) {
+	columns := []string{"id", "exchange", "routing_key", "type", "headers", "body", "attempts", "last_error", "next_attempt_at", "created_at", "sent_at"}
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		olderPending int64
+		want         int
+	}{
+		{name: "claimed batch is returned", want: 1},
+		{name: "nothing is returned while older messages are claimed by another relay", olderPending: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newRecorder()
+			db.returning("SELECT * FROM `outbox_messages`", columns,
+				[]driver.Value{int64(5), "casper-events", "event-update", "EventUpdated", "", []byte("body"), int64(0), "", now, now, nil})
+			db.returning("SELECT count(*) FROM `outbox_messages`", []string{"count(*)"}, []driver.Value{tt.olderPending})
+
+			messages, err := NewOutboxRepository().Pending(db.connect(t), 10)
+			if err != nil {
+				t.Fatalf("Pending() error = %v", err)
+			}
+
+			if len(messages) != tt.want {
+				t.Errorf("Pending() = %d messages, want %d", len(messages), tt.want)
+			}
+
+			selects := db.find("SELECT * FROM `outbox_messages`")
+			if len(selects) != 1 || !strings.HasSuffix(selects[0].query, "FOR UPDATE SKIP LOCKED") {
+				t.Errorf("Pending() queries = %v, want rows claimed with FOR UPDATE SKIP LOCKED", selects)
+			}
+
+			if !strings.Contains(selects[0].query, "dead_at IS NULL") {
+				t.Errorf("Pending() query = %v, want dead messages skipped", selects[0].query)
+			}
+		})
+	}
+}
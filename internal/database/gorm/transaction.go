@@ -0,0 +1,37 @@
+package gorm
+
+import (
+	"context"
+
+	"event-service/internal/services"
+
+	"gorm.io/gorm"
+)
+
+// Transactor runs functions in the transaction of the connection from the context,
+// the transaction replaces the connection in the context passed to the function.
+// Functions registered with services.AfterCommit run once the transaction is committed
+type Transactor struct{}
+
+func NewTransactor() *Transactor {
+	return &Transactor{}
+}
+
+func (t Transactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	db, err := ConnectionFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, commit := services.WithCommitHooks(ctx)
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return fn(ContextWithConnection(ctx, tx))
+	}); err != nil {
+		return err
+	}
+
+	commit()
+
+	return nil
+}
@@ -2,48 +2,338 @@ package repository
 
 import (
 	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
+	commonvalueobject "event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event"
 	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
 	"event-service/internal/domain/event/valueobject"
 )
 
+var ErrEventNotFound = errors.Wrap(event.ErrNotFound, "events with requested id not found")
+
+// EventsStorage keeps events in memory, it is safe for concurrent use
+// and stores copies of the aggregates so that callers cannot modify stored state
 type EventsStorage struct {
-	items map[uuid.UUID]*aggregate.Event
+	mu          sync.RWMutex
+	items       map[uuid.UUID]*aggregate.Event
+	lastID      uint
+	invitations *InvitationsStorage
 }
 
 func NewEventsStorage() *EventsStorage {
 	return &EventsStorage{items: make(map[uuid.UUID]*aggregate.Event)}
 }
 
-func (e EventsStorage) Update(ctx context.Context, event *aggregate.Event) error {
-	eventToUpdate, err := e.FindByExternalID(ctx, event.Event.ExternalID)
-	if err != nil {
+// Update stores the event when it has not been changed since it was fetched and moves its version,
+// otherwise it fails with aggregate.ErrVersionConflict the same way the sql repository does
+func (e *EventsStorage) Update(_ context.Context, event *aggregate.Event) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	eventToUpdate, ok := e.items[event.Event.ExternalID]
+	if !ok {
+		return ErrEventNotFound
+	}
+
+	if err := eventToUpdate.CheckVersion(event.Version); err != nil {
 		return err
 	}
 
 	event.ID = eventToUpdate.ID
-	e.items[event.Event.ExternalID] = event
+	event.Version++
+	e.items[event.Event.ExternalID] = copyEvent(event)
+
+	return nil
+}
+
+// Delete removes the event, invitations of the event are removed too when the storage is linked with the invitations one
+func (e *EventsStorage) Delete(_ context.Context, event *aggregate.Event) error {
+	e.mu.Lock()
+	_, ok := e.items[event.Event.ExternalID]
+	delete(e.items, event.Event.ExternalID)
+	invitations := e.invitations
+	e.mu.Unlock()
+
+	if !ok {
+		return ErrEventNotFound
+	}
+
+	if invitations != nil {
+		invitations.removeEventInvitations(event.Event.ExternalID)
+	}
 
 	return nil
 }
 
-func (e EventsStorage) FindBy(_ context.Context, request valueobject.ListRequest) ([]*aggregate.Event, error) {
-	panic("implement me")
+func (e *EventsStorage) FindBy(_ context.Context, request valueobject.ListRequest) (event.Page, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	order, pagination := request.Order(), request.Pagination()
+
+	origin, hasOrigin := request.Origin()
+	if order.Field() == valueobject.OrderByDistance && !hasOrigin {
+		return event.Page{}, valueobject.ErrDistanceOrderWithoutOrigin
+	}
+
+	edges := make([]event.Edge, 0, len(e.items))
+	for _, item := range e.items {
+		if !matchesListRequest(item, request) {
+			continue
+		}
+
+		var distance float64
+		if hasOrigin && item.Location != nil {
+			distance = origin.DistanceTo(item.Location.Spot)
+		}
+
+		edges = append(edges, event.Edge{
+			Cursor: event.CursorFor(order.Field(), item, distance),
+			Event:  copyEvent(item),
+		})
+	}
+
+	totalCount := int64(len(edges))
+
+	queryOrder := order
+	if pagination.Backward() {
+		queryOrder = order.Reversed()
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		return compareCursors(edges[i].Cursor, edges[j].Cursor, queryOrder.IsDescending()) < 0
+	})
+
+	filtered := edges[:0]
+	for _, edge := range edges {
+		if after, ok := pagination.After(); ok && compareCursors(edge.Cursor, after, order.IsDescending()) <= 0 {
+			continue
+		}
+
+		if before, ok := pagination.Before(); ok && compareCursors(edge.Cursor, before, order.IsDescending()) >= 0 {
+			continue
+		}
+
+		filtered = append(filtered, edge)
+
+		if len(filtered) > pagination.Limit() {
+			break
+		}
+	}
+
+	return event.NewPage(filtered, totalCount, pagination), nil
+}
+
+// compareCursors compares positions of two cursors in the list with the given direction
+func compareCursors(a, b valueobject.Cursor, descending bool) int {
+	result := compareCursorValues(a, b)
+	if result == 0 {
+		switch {
+		case a.ID < b.ID:
+			result = -1
+		case a.ID > b.ID:
+			result = 1
+		}
+	}
+
+	if descending {
+		return -result
+	}
+
+	return result
+}
+
+func compareCursorValues(a, b valueobject.Cursor) int {
+	switch a.Field {
+	case valueobject.OrderByStartDate, valueobject.OrderByCreatedAt:
+		at, _ := a.TimeValue()
+		bt, _ := b.TimeValue()
+
+		return at.Compare(bt)
+	case valueobject.OrderByDistance:
+		af, _ := a.FloatValue()
+		bf, _ := b.FloatValue()
+
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+
+		return 0
+	default:
+		return strings.Compare(a.Value, b.Value)
+	}
+}
+
+// matchesListRequest checks the event against every filter of the request the same way the sql repository does
+func matchesListRequest(e *aggregate.Event, request valueobject.ListRequest) bool {
+	if name, ok := request.Name(); ok && !strings.Contains(strings.ToLower(e.Event.Name), strings.ToLower(name)) {
+		return false
+	}
+
+	if user, ok := request.User(); ok && e.UserID.String() != user {
+		return false
+	}
+
+	if public, ok := request.Public(); ok && e.Event.Public != public {
+		return false
+	}
+
+	if viewer, ok := request.Viewer(); ok && policy.CanView(e, viewer) != nil {
+		return false
+	}
+
+	if participant, ok := request.Participant(); ok && !slices.ContainsFunc(e.Participants, func(p uuid.UUID) bool {
+		return p.String() == participant
+	}) {
+		return false
+	}
+
+	if interval, ok := request.Interval(); ok && len(e.Occurrences(interval.GetStartDate(), interval.GetEndDate())) == 0 {
+		return false
+	}
+
+	if distance, ok := request.Distance(); ok {
+		if e.Location == nil {
+			return false
+		}
+
+		origin := commonvalueobject.NewLocation(distance.InitLatitude(), distance.InitLongitude())
+		if origin.DistanceTo(e.Location.Spot) >= float64(distance.Distance()) {
+			return false
+		}
+	}
+
+	return true
 }
 
-func (e EventsStorage) FindByExternalID(_ context.Context, id uuid.UUID) (*aggregate.Event, error) {
+func (e *EventsStorage) FindByExternalID(_ context.Context, id uuid.UUID) (*aggregate.Event, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	event, ok := e.items[id]
 	if !ok {
-		return nil, errors.New("events with requested id not found")
+		return nil, ErrEventNotFound
+	}
+
+	return copyEvent(event), nil
+}
+
+func (e *EventsStorage) FindByExternalIDs(_ context.Context, ids []uuid.UUID) ([]*aggregate.Event, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	events := make([]*aggregate.Event, 0, len(ids))
+	for _, id := range ids {
+		if event, ok := e.items[id]; ok {
+			events = append(events, copyEvent(event))
+		}
 	}
 
-	return event, nil
+	return events, nil
+}
+
+func (e *EventsStorage) Add(_ context.Context, event *aggregate.Event) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lastID++
+	event.ID = e.lastID
+	e.items[event.Event.ExternalID] = copyEvent(event)
+
+	return nil
 }
 
-func (e EventsStorage) Add(_ context.Context, event *aggregate.Event) error {
-	event.ID = uint(len(e.items) + 1)
-	e.items[event.Event.ExternalID] = event
+// syncInvitation reflects state of the invitation in participants and waitlist of the stored event,
+// it is used by the linked invitations storage. Changes of the participants move the version of the event.
+// The spot is checked and taken under the lock, so concurrent requests cannot take the same last spot
+func (e *EventsStorage) syncInvitation(invitation *aggregate.Invitation, removed bool) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	stored, ok := e.items[invitation.Event.Event.ExternalID]
+	if !ok {
+		return nil
+	}
+
+	participants := withoutUser(stored.Participants, invitation.InvitedUser)
+	if !removed && invitation.IsAccepted() && len(participants) >= stored.Event.Capacity {
+		return aggregate.ErrCapacityFull
+	}
+
+	stored.Participants = participants
+	stored.Waitlist = withoutUser(stored.Waitlist, invitation.InvitedUser)
+	stored.Invitees = withoutUser(stored.Invitees, invitation.InvitedUser)
+
+	if removed || invitation.IsAccepted() || invitation.IsWaitlisted() {
+		stored.Version++
+	}
+
+	if removed {
+		return nil
+	}
+
+	switch {
+	case invitation.IsAccepted():
+		stored.Participants = append(stored.Participants, invitation.InvitedUser)
+	case invitation.IsWaitlisted():
+		stored.Waitlist = append(stored.Waitlist, invitation.InvitedUser)
+	case invitation.IsActive():
+		stored.Invitees = append(stored.Invitees, invitation.InvitedUser)
+	}
+
 	return nil
 }
+
+func withoutUser(users []uuid.UUID, user uuid.UUID) []uuid.UUID {
+	filtered := make([]uuid.UUID, 0, len(users))
+	for _, u := range users {
+		if u != user {
+			filtered = append(filtered, u)
+		}
+	}
+
+	return filtered
+}
+
+func copyEvent(e *aggregate.Event) *aggregate.Event {
+	c := *e
+
+	if e.Event != nil {
+		entity := *e.Event
+		c.Event = &entity
+	}
+
+	if e.Location != nil {
+		location := *e.Location
+		c.Location = &location
+	}
+
+	if e.Participants != nil {
+		c.Participants = append([]uuid.UUID{}, e.Participants...)
+	}
+
+	if e.Waitlist != nil {
+		c.Waitlist = append([]uuid.UUID{}, e.Waitlist...)
+	}
+
+	if e.Invitees != nil {
+		c.Invitees = append([]uuid.UUID{}, e.Invitees...)
+	}
+
+	if e.Overrides != nil {
+		c.Overrides = append([]aggregate.OccurrenceOverride{}, e.Overrides...)
+	}
+
+	return &c
+}
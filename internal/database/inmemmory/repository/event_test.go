@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"event-service/internal/domain/common/entity"
+	commonvalueobject "event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/valueobject"
+
+	"github.com/google/uuid"
+)
+
+func TestEventsStorage_FindBy(t *testing.T) {
+	organizer := uuid.New()
+	now := time.Now()
+
+	storage := NewEventsStorage()
+	for _, e := range []*aggregate.Event{
+		newEventMock(organizer, "Paris meetup", true, now.Add(24*time.Hour), 48.8584, 2.2945),
+		newEventMock(organizer, "Versailles picnic", false, now.Add(48*time.Hour), 48.8049, 2.1204),
+		newEventMock(uuid.New(), "London meetup", true, now.Add(72*time.Hour), 51.5007, -0.1246),
+	} {
+		_ = storage.Add(context.Background(), e)
+	}
+
+	two := 2
+
+	tests := []struct {
+		name      string
+		request   func() valueobject.ListRequest
+		wantNames []string
+		wantNext  bool
+	}{
+		{
+			name: "name filter is case insensitive",
+			request: func() valueobject.ListRequest {
+				return valueobject.NewListRequest(valueobject.WithName("MEETUP"))
+			},
+			wantNames: []string{"Paris meetup", "London meetup"},
+		},
+		{
+			name: "user and public filters",
+			request: func() valueobject.ListRequest {
+				return valueobject.NewListRequest(valueobject.WithUser(organizer.String()), valueobject.WithPublicParam(false))
+			},
+			wantNames: []string{"Versailles picnic"},
+		},
+		{
+			name: "upcoming events in the interval",
+			request: func() valueobject.ListRequest {
+				return valueobject.NewListRequest(valueobject.WithTimeInterval(now.Add(36*time.Hour), 24*time.Hour))
+			},
+			wantNames: []string{"Paris meetup", "Versailles picnic"},
+		},
+		{
+			name: "events nearby ordered by distance",
+			request: func() valueobject.ListRequest {
+				return valueobject.NewListRequest(
+					valueobject.WithDistance(30, 48.8049, 2.1204),
+					valueobject.WithOrder(valueobject.NewOrder(valueobject.OrderByDistance, valueobject.OrderAscending)),
+				)
+			},
+			wantNames: []string{"Versailles picnic", "Paris meetup"},
+		},
+		{
+			name: "first page ordered by name descending",
+			request: func() valueobject.ListRequest {
+				p, _ := valueobject.NewPagination(&two, nil, nil, nil)
+				return valueobject.NewListRequest(
+					valueobject.WithOrder(valueobject.NewOrder(valueobject.OrderByName, valueobject.OrderDescending)),
+					valueobject.WithPagination(p),
+				)
+			},
+			wantNames: []string{"Versailles picnic", "Paris meetup"},
+			wantNext:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := storage.FindBy(context.Background(), tt.request())
+			if err != nil {
+				t.Fatalf("FindBy() error = %v", err)
+			}
+
+			if len(got.Edges) != len(tt.wantNames) {
+				t.Fatalf("FindBy() got %d events, want %d", len(got.Edges), len(tt.wantNames))
+			}
+
+			for i, name := range tt.wantNames {
+				if got.Edges[i].Event.Event.Name != name {
+					t.Errorf("FindBy() event %d = %v, want %v", i, got.Edges[i].Event.Event.Name, name)
+				}
+			}
+
+			if got.HasNextPage != tt.wantNext {
+				t.Errorf("FindBy() HasNextPage = %v, want %v", got.HasNextPage, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestEventsStorage_ConcurrentAccess(t *testing.T) {
+	storage := NewEventsStorage()
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			_ = storage.Add(context.Background(), newEventMock(uuid.New(), "event", true, time.Now(), 0, 0))
+		}()
+
+		go func() {
+			defer wg.Done()
+			_, _ = storage.FindBy(context.Background(), valueobject.NewListRequest())
+		}()
+	}
+
+	wg.Wait()
+
+	page, _ := storage.FindBy(context.Background(), valueobject.NewListRequest())
+	if page.TotalCount != 50 {
+		t.Errorf("FindBy() TotalCount = %v, want %v", page.TotalCount, 50)
+	}
+}
+
+func TestEventsStorage_FindByRecurringEvents(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	weekly := newEventMock(uuid.New(), "Weekly run", true, now.AddDate(0, 0, -28), 48.8584, 2.2945)
+	weekly.Recurrence, _ = commonvalueobject.NewRecurrence("FREQ=WEEKLY;COUNT=52")
+
+	finished := newEventMock(uuid.New(), "Finished series", true, now.AddDate(0, 0, -28), 48.8584, 2.2945)
+	finished.Recurrence, _ = commonvalueobject.NewRecurrence("FREQ=WEEKLY;COUNT=2")
+
+	storage := NewEventsStorage()
+	_ = storage.Add(context.Background(), weekly)
+	_ = storage.Add(context.Background(), finished)
+
+	tests := []struct {
+		name      string
+		interval  time.Time
+		wantNames []string
+	}{
+		{
+			name:      "series with an occurrence in the interval",
+			interval:  now.AddDate(0, 0, 7),
+			wantNames: []string{"Weekly run"},
+		},
+		{
+			name:     "series without an occurrence in the interval",
+			interval: now.AddDate(0, 0, 3),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := storage.FindBy(context.Background(), valueobject.NewListRequest(valueobject.WithTimeInterval(tt.interval, time.Hour)))
+			if err != nil {
+				t.Fatalf("FindBy() error = %v", err)
+			}
+
+			var names []string
+			for _, e := range page.Events() {
+				names = append(names, e.Event.Name)
+			}
+
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("FindBy() names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestEventsStorage_UpdateVersion(t *testing.T) {
+	ctx := context.Background()
+	events := NewEventsStorage()
+	invitations := NewLinkedInvitationsStorage(events)
+
+	stored := newEventMock(uuid.New(), "event", true, time.Now().Add(time.Hour), 0, 0)
+	_ = events.Add(ctx, stored)
+
+	first, _ := events.FindByExternalID(ctx, stored.Event.ExternalID)
+	second, _ := events.FindByExternalID(ctx, stored.Event.ExternalID)
+
+	if err := events.Update(ctx, first); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if first.Version != 1 {
+		t.Errorf("Update() version = %v, want %v", first.Version, 1)
+	}
+
+	if err := events.Update(ctx, second); !errors.Is(err, aggregate.ErrVersionConflict) {
+		t.Errorf("Update() of the stale event error = %v, want %v", err, aggregate.ErrVersionConflict)
+	}
+
+	now := time.Now()
+	_ = invitations.Accept(ctx, &aggregate.Invitation{Event: first, InvitedUser: uuid.New(), AcceptedAt: &now})
+
+	if err := events.Update(ctx, first); !errors.Is(err, aggregate.ErrVersionConflict) {
+		t.Errorf("Update() of the event with accepted invitation error = %v, want %v", err, aggregate.ErrVersionConflict)
+	}
+}
+
+func newEventMock(user uuid.UUID, name string, public bool, start time.Time, lat, long float64) *aggregate.Event {
+	e := &aggregate.Event{
+		UserID: user,
+		Event: &entity.Event{
+			ExternalID: uuid.New(),
+			Name:       name,
+			Capacity:   10,
+			Public:     public,
+		},
+		Location: &aggregate.Location{
+			Spot: commonvalueobject.NewLocation(lat, long),
+		},
+	}
+
+	e.EventPeriod, _ = e.EventPeriod.WithStartAndDuration(start, time.Hour)
+
+	return e
+}
@@ -2,54 +2,229 @@ package repository
 
 import (
 	"context"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 
+	"event-service/internal/domain/event"
 	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/valueobject"
 
 	"github.com/google/uuid"
 )
 
+// InvitationsStorage keeps invitations in memory, it is safe for concurrent use. When it is linked
+// with the events storage accepted invitations are reflected in the event participants and the invitation
+// that does not fit the event is rejected with aggregate.ErrCapacityFull. Without the link the participants
+// are not tracked, so the capacity is checked by the aggregate only
 type InvitationsStorage struct {
-	items map[string]*aggregate.Invitation
+	mu     sync.RWMutex
+	items  map[string]*aggregate.Invitation
+	events *EventsStorage
 }
 
 func NewInvitationsStorage() *InvitationsStorage {
 	return &InvitationsStorage{items: make(map[string]*aggregate.Invitation)}
 }
 
-func (i InvitationsStorage) FindBy(_ context.Context, eventID, userID uuid.UUID) (*aggregate.Invitation, error) {
-	invitationID := getInvitationID(eventID, userID)
-	invitation, ok := i.items[invitationID]
+func NewLinkedInvitationsStorage(events *EventsStorage) *InvitationsStorage {
+	i := NewInvitationsStorage()
+	i.events = events
+
+	events.mu.Lock()
+	events.invitations = i
+	events.mu.Unlock()
+
+	return i
+}
+
+func (i *InvitationsStorage) FindBy(ctx context.Context, eventID, userID uuid.UUID) (*aggregate.Invitation, error) {
+	i.mu.RLock()
+	invitation, ok := i.items[getInvitationID(eventID, userID)]
+	i.mu.RUnlock()
+
 	if !ok {
 		return nil, nil
 	}
 
-	return invitation, nil
+	found := copyInvitation(invitation)
+
+	if i.events != nil {
+		e, err := i.events.FindByExternalID(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+
+		found.Event = e
+	}
+
+	return found, nil
+}
+
+func (i *InvitationsStorage) Invite(_ context.Context, invitation *aggregate.Invitation) error {
+	return i.Add(invitation)
+}
+
+func (i *InvitationsStorage) Accept(_ context.Context, invitation *aggregate.Invitation) error {
+	return i.Add(invitation)
 }
 
-func (i InvitationsStorage) Invite(_ context.Context, invitation *aggregate.Invitation) error {
+func (i *InvitationsStorage) Decline(_ context.Context, invitation *aggregate.Invitation) error {
 	return i.Add(invitation)
 }
 
-func (i InvitationsStorage) Accept(_ context.Context, invitation *aggregate.Invitation) error {
+func (i *InvitationsStorage) Revoke(_ context.Context, invitation *aggregate.Invitation) error {
 	return i.Add(invitation)
 }
 
-func (i InvitationsStorage) Remove(_ context.Context, invitation *aggregate.Invitation) error {
-	invitationID := getInvitationID(invitation.Event.Event.ExternalID, invitation.InvitedUser)
-	delete(i.items, invitationID)
+// Remove keeps the invitation of the removed user, so it is listed with the removed status
+func (i *InvitationsStorage) Remove(_ context.Context, invitation *aggregate.Invitation) error {
+	i.mu.Lock()
+	i.items[getInvitationID(invitation.Event.Event.ExternalID, invitation.InvitedUser)] = copyInvitation(invitation)
+	i.mu.Unlock()
+
+	if i.events != nil {
+		return i.events.syncInvitation(invitation, true)
+	}
 
 	return nil
 }
 
-func (i InvitationsStorage) Add(invitation *aggregate.Invitation) error {
-	invitationID := getInvitationID(invitation.Event.Event.ExternalID, invitation.InvitedUser)
-	i.items[invitationID] = invitation
+// Add stores the invitation, the accepted one is stored only when the spot in the linked event has been taken
+func (i *InvitationsStorage) Add(invitation *aggregate.Invitation) error {
+	if i.events != nil {
+		if err := i.events.syncInvitation(invitation, false); err != nil {
+			return err
+		}
+	}
+
+	i.mu.Lock()
+	i.items[getInvitationID(invitation.Event.Event.ExternalID, invitation.InvitedUser)] = copyInvitation(invitation)
+	i.mu.Unlock()
 
 	return nil
 }
 
+func (i *InvitationsStorage) FindParticipants(_ context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]*aggregate.Invitation, error) {
+	participants := make(map[uuid.UUID][]*aggregate.Invitation, len(eventIDs))
+
+	for _, id := range eventIDs {
+		invitations := i.eventInvitations(id)
+
+		accepted := slices.DeleteFunc(slices.Clone(invitations), func(ia *aggregate.Invitation) bool { return !ia.IsAccepted() })
+		waitlisted := slices.DeleteFunc(invitations, func(ia *aggregate.Invitation) bool { return !ia.IsWaitlisted() })
+
+		sort.SliceStable(waitlisted, func(i, j int) bool {
+			if !waitlisted[i].WaitlistedAt.Equal(*waitlisted[j].WaitlistedAt) {
+				return waitlisted[i].WaitlistedAt.Before(*waitlisted[j].WaitlistedAt)
+			}
+
+			return waitlisted[i].InvitedUser.String() < waitlisted[j].InvitedUser.String()
+		})
+
+		if len(accepted)+len(waitlisted) > 0 {
+			participants[id] = append(accepted, waitlisted...)
+		}
+	}
+
+	return participants, nil
+}
+
+func (i *InvitationsStorage) FindByEvent(_ context.Context, eventID uuid.UUID, request event.ParticipantsRequest) (event.ParticipantPage, error) {
+	invitations := slices.DeleteFunc(i.eventInvitations(eventID), func(ia *aggregate.Invitation) bool {
+		return !request.Matches(ia)
+	})
+
+	edges := make([]event.ParticipantEdge, 0, len(invitations))
+	for _, ia := range invitations {
+		cursor := event.ParticipantCursorFor(ia)
+		if request.After != nil && compareParticipantCursors(cursor, *request.After) <= 0 {
+			continue
+		}
+
+		edges = append(edges, event.ParticipantEdge{Cursor: cursor, Invitation: ia})
+	}
+
+	return event.NewParticipantPage(edges, int64(len(invitations)), request), nil
+}
+
+// eventInvitations returns copies of the invitations of the event without the event ordered by time of inviting
+func (i *InvitationsStorage) eventInvitations(eventID uuid.UUID) []*aggregate.Invitation {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	invitations := make([]*aggregate.Invitation, 0)
+	for _, invitation := range i.items {
+		if invitation.Event.Event.ExternalID == eventID {
+			listed := copyInvitation(invitation)
+			listed.Event = nil
+			invitations = append(invitations, listed)
+		}
+	}
+
+	sort.Slice(invitations, func(i, j int) bool {
+		return compareParticipantCursors(event.ParticipantCursorFor(invitations[i]), event.ParticipantCursorFor(invitations[j])) < 0
+	})
+
+	return invitations
+}
+
+func compareParticipantCursors(a, b valueobject.ParticipantCursor) int {
+	if c := a.InvitedAt.Compare(b.InvitedAt); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.User.String(), b.User.String())
+}
+
+func (i *InvitationsStorage) removeEventInvitations(eventID uuid.UUID) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for id, invitation := range i.items {
+		if invitation.Event.Event.ExternalID == eventID {
+			delete(i.items, id)
+		}
+	}
+}
+
 // getInvitationID returns a string that is a combination of eventID and userID
 func getInvitationID(eventID, userID uuid.UUID) string {
 	return strings.Join([]string{eventID.String(), userID.String()}, "_")
 }
+
+func copyInvitation(i *aggregate.Invitation) *aggregate.Invitation {
+	c := *i
+
+	if i.Event != nil {
+		c.Event = copyEvent(i.Event)
+	}
+
+	if i.AcceptedAt != nil {
+		acceptedAt := *i.AcceptedAt
+		c.AcceptedAt = &acceptedAt
+	}
+
+	if i.WaitlistedAt != nil {
+		waitlistedAt := *i.WaitlistedAt
+		c.WaitlistedAt = &waitlistedAt
+	}
+
+	if i.DeclinedAt != nil {
+		declinedAt := *i.DeclinedAt
+		c.DeclinedAt = &declinedAt
+	}
+
+	if i.RevokedAt != nil {
+		revokedAt := *i.RevokedAt
+		c.RevokedAt = &revokedAt
+	}
+
+	if i.RemovedAt != nil {
+		removedAt := *i.RemovedAt
+		c.RemovedAt = &removedAt
+	}
+
+	return &c
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"event-service/internal/domain/event"
+	"event-service/internal/domain/event/aggregate"
+
+	"github.com/google/uuid"
+)
+
+func newInvitationMock(e *aggregate.Event, invitedAt time.Time, status aggregate.InvitationStatus) *aggregate.Invitation {
+	ia := &aggregate.Invitation{Event: e, InvitedUser: uuid.New(), InvitedAt: invitedAt}
+
+	switch status {
+	case aggregate.InvitationAccepted:
+		ia.AcceptedAt = &invitedAt
+	case aggregate.InvitationWaitlisted:
+		ia.WaitlistedAt = &invitedAt
+	case aggregate.InvitationRemoved:
+		ia.RemovedAt = &invitedAt
+	}
+
+	return ia
+}
+
+func users(invitations []*aggregate.Invitation) []uuid.UUID {
+	ids := make([]uuid.UUID, len(invitations))
+	for i, ia := range invitations {
+		ids[i] = ia.InvitedUser
+	}
+
+	return ids
+}
+
+func TestInvitationsStorage_FindParticipants(t *testing.T) {
+	ctx := context.Background()
+	events := NewEventsStorage()
+	invitations := NewLinkedInvitationsStorage(events)
+
+	attended := newEventMock(uuid.New(), "attended", true, time.Now().Add(time.Hour), 0, 0)
+	empty := newEventMock(uuid.New(), "empty", true, time.Now().Add(time.Hour), 0, 0)
+	_ = events.Add(ctx, attended)
+	_ = events.Add(ctx, empty)
+
+	start := time.Now().Add(-time.Hour)
+	secondWaitlisted := newInvitationMock(attended, start.Add(2*time.Minute), aggregate.InvitationWaitlisted)
+	firstWaitlisted := newInvitationMock(attended, start.Add(time.Minute), aggregate.InvitationWaitlisted)
+	accepted := newInvitationMock(attended, start.Add(3*time.Minute), aggregate.InvitationAccepted)
+
+	for _, ia := range []*aggregate.Invitation{
+		secondWaitlisted, firstWaitlisted, accepted,
+		newInvitationMock(attended, start, aggregate.InvitationInvited),
+		newInvitationMock(empty, start, aggregate.InvitationRemoved),
+	} {
+		_ = invitations.Add(ia)
+	}
+
+	got, err := invitations.FindParticipants(ctx, []uuid.UUID{attended.Event.ExternalID, empty.Event.ExternalID, uuid.New()})
+	if err != nil {
+		t.Fatalf("FindParticipants() error = %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Errorf("FindParticipants() events = %d, want only the attended one", len(got))
+	}
+
+	want := []uuid.UUID{accepted.InvitedUser, firstWaitlisted.InvitedUser, secondWaitlisted.InvitedUser}
+	if participants := users(got[attended.Event.ExternalID]); !reflect.DeepEqual(participants, want) {
+		t.Errorf("FindParticipants() = %v, want %v", participants, want)
+	}
+}
+
+func TestInvitationsStorage_FindByEvent(t *testing.T) {
+	ctx := context.Background()
+	invitations := NewInvitationsStorage()
+	e := newEventMock(uuid.New(), "event", true, time.Now().Add(time.Hour), 0, 0)
+
+	start := time.Now().Add(-time.Hour)
+	listed := []*aggregate.Invitation{
+		newInvitationMock(e, start, aggregate.InvitationAccepted),
+		newInvitationMock(e, start.Add(time.Minute), aggregate.InvitationInvited),
+		newInvitationMock(e, start.Add(2*time.Minute), aggregate.InvitationAccepted),
+		newInvitationMock(e, start.Add(3*time.Minute), aggregate.InvitationRemoved),
+	}
+
+	for _, ia := range listed {
+		_ = invitations.Add(ia)
+	}
+
+	_ = invitations.Add(newInvitationMock(newEventMock(uuid.New(), "other", true, time.Now(), 0, 0), start, aggregate.InvitationAccepted))
+
+	accepted := aggregate.InvitationAccepted
+	after := event.ParticipantCursorFor(listed[0])
+
+	tests := []struct {
+		name      string
+		request   event.ParticipantsRequest
+		wantUsers []uuid.UUID
+		wantTotal int64
+		wantNext  bool
+	}{
+		{
+			name:      "first page of all invitations",
+			request:   event.ParticipantsRequest{Limit: 2},
+			wantUsers: users(listed[:2]),
+			wantTotal: 4,
+			wantNext:  true,
+		},
+		{
+			name:      "invitations after the cursor",
+			request:   event.ParticipantsRequest{Limit: 5, After: &after},
+			wantUsers: users(listed[1:]),
+			wantTotal: 4,
+		},
+		{
+			name:      "accepted invitations",
+			request:   event.ParticipantsRequest{Limit: 5, Status: &accepted},
+			wantUsers: []uuid.UUID{listed[0].InvitedUser, listed[2].InvitedUser},
+			wantTotal: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := invitations.FindByEvent(ctx, e.Event.ExternalID, tt.request)
+			if err != nil {
+				t.Fatalf("FindByEvent() error = %v", err)
+			}
+
+			got := make([]*aggregate.Invitation, len(page.Edges))
+			for i, edge := range page.Edges {
+				got[i] = edge.Invitation
+			}
+
+			if !reflect.DeepEqual(users(got), tt.wantUsers) {
+				t.Errorf("FindByEvent() users = %v, want %v", users(got), tt.wantUsers)
+			}
+
+			if page.TotalCount != tt.wantTotal || page.HasNextPage != tt.wantNext {
+				t.Errorf("FindByEvent() total = %d next = %v, want %d and %v", page.TotalCount, page.HasNextPage, tt.wantTotal, tt.wantNext)
+			}
+		})
+	}
+}
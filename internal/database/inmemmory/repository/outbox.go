@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"event-service/internal/outbox"
+)
+
+// OutboxStorage keeps outbox messages in memory, it is safe for concurrent use
+type OutboxStorage struct {
+	mu       sync.Mutex
+	messages []outbox.Message
+	purged   uint // number of the purged messages preceding the stored ones
+}
+
+func NewOutboxStorage() *OutboxStorage {
+	return &OutboxStorage{}
+}
+
+func (s *OutboxStorage) Add(_ context.Context, m *outbox.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m.ID = s.purged + uint(len(s.messages)) + 1
+	s.messages = append(s.messages, *m)
+
+	return nil
+}
+
+func (s *OutboxStorage) Pending(_ context.Context, limit int) ([]outbox.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []outbox.Message
+	for _, m := range s.messages {
+		if len(pending) == limit {
+			break
+		}
+
+		if m.SentAt == nil && m.DeadAt == nil {
+			pending = append(pending, m)
+		}
+	}
+
+	return pending, nil
+}
+
+func (s *OutboxStorage) MarkSent(_ context.Context, id uint, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m := s.message(id); m != nil {
+		m.SentAt = &at
+	}
+
+	return nil
+}
+
+func (s *OutboxStorage) MarkFailed(_ context.Context, id uint, reason string, retryAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m := s.message(id); m != nil {
+		m.Attempts++
+		m.LastError = reason
+		m.NextAttemptAt = retryAt
+	}
+
+	return nil
+}
+
+func (s *OutboxStorage) MarkDead(_ context.Context, id uint, reason string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m := s.message(id); m != nil {
+		m.Attempts++
+		m.LastError = reason
+		m.DeadAt = &at
+	}
+
+	return nil
+}
+
+// Purge deletes messages sent or marked dead before the time, messages are relayed in order so only the oldest ones are deleted
+func (s *OutboxStorage) Purge(_ context.Context, sentBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for n < len(s.messages) && relayedBefore(s.messages[n], sentBefore) {
+		n++
+	}
+
+	s.messages = append([]outbox.Message(nil), s.messages[n:]...)
+	s.purged += uint(n)
+
+	return int64(n), nil
+}
+
+func relayedBefore(m outbox.Message, before time.Time) bool {
+	return (m.SentAt != nil && m.SentAt.Before(before)) || (m.DeadAt != nil && m.DeadAt.Before(before))
+}
+
+// message returns stored message with the id, ids are positions of the messages starting from 1 including the purged ones
+func (s *OutboxStorage) message(id uint) *outbox.Message {
+	if id <= s.purged || int(id-s.purged) > len(s.messages) {
+		return nil
+	}
+
+	return &s.messages[id-s.purged-1]
+}
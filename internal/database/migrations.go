@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"event-service/migrations"
+
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+const (
+	// MigrationsSourceName names the source of the embedded migrations for migrate
+	MigrationsSourceName = "iofs"
+	MigrationsTable      = "schema_migrations"
+)
+
+var (
+	ErrPendingMigrations = errors.New("database has pending migrations")
+	ErrDirtyMigration    = errors.New("database migration failed and is dirty")
+)
+
+// MigrationsSource returns the migrations embedded into the binary
+func MigrationsSource() (source.Driver, error) {
+	return iofs.New(migrations.FS, ".")
+}
+
+// LatestMigration returns version of the last migration in the file system
+func LatestMigration(fsys fs.FS) (uint, error) {
+	src, err := iofs.New(fsys, ".")
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		next, nextErr := src.Next(version)
+		if errors.Is(nextErr, fs.ErrNotExist) {
+			return version, nil
+		}
+
+		if nextErr != nil {
+			return 0, nextErr
+		}
+
+		version = next
+	}
+}
+
+// MigrationsApplied reports an error when the schema version of the database is behind the latest migration
+func MigrationsApplied(db *sql.DB, latest uint) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var (
+			version uint
+			dirty   bool
+		)
+
+		err := db.QueryRowContext(ctx, "SELECT version, dirty FROM "+MigrationsTable+" LIMIT 1").Scan(&version, &dirty)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if dirty {
+			return fmt.Errorf("%w: version %d", ErrDirtyMigration, version)
+		}
+
+		if version < latest {
+			return fmt.Errorf("%w: version %d, latest %d", ErrPendingMigrations, version, latest)
+		}
+
+		return nil
+	}
+}
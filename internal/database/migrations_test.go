@@ -0,0 +1,45 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"event-service/migrations"
+)
+
+func TestLatestMigration(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{
+		"000001_create_tables.up.sql",
+		"000001_create_tables.down.sql",
+		"000002_create_invitations.up.sql",
+		"000010_add_event_version.up.sql",
+		"000010_add_event_version.down.sql",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	latest, err := LatestMigration(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("LatestMigration() error = %v", err)
+	}
+
+	if latest != 10 {
+		t.Errorf("LatestMigration() = %d, want 10", latest)
+	}
+}
+
+func TestLatestMigrationEmbedded(t *testing.T) {
+	latest, err := LatestMigration(migrations.FS)
+	if err != nil {
+		t.Fatalf("LatestMigration() error = %v", err)
+	}
+
+	if latest == 0 {
+		t.Error("LatestMigration() found no embedded migrations")
+	}
+}
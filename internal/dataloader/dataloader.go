@@ -0,0 +1,154 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWait is time the loader collects keys of the concurrently resolved fields before fetching them
+	DefaultWait = time.Millisecond
+	// DefaultMaxBatch is the maximal number of keys fetched at once, e.g. the maximal page size
+	DefaultMaxBatch = 100
+)
+
+// ErrNotFound is returned for the key the batch function has not returned value for
+var ErrNotFound = errors.New("not found")
+
+// BatchFunc fetches values of all the keys at once, keys without value are left out of the result
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+type options struct {
+	wait     time.Duration
+	maxBatch int
+}
+
+type Configuration func(*options)
+
+func WithWait(wait time.Duration) Configuration {
+	return func(o *options) {
+		if wait > 0 {
+			o.wait = wait
+		}
+	}
+}
+
+func WithMaxBatch(size int) Configuration {
+	return func(o *options) {
+		if size > 0 {
+			o.maxBatch = size
+		}
+	}
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	ctx     context.Context
+	keys    []K
+	results []*result[V]
+}
+
+// Loader collects keys requested within the wait time and fetches them with a single call of the batch function.
+// Values are cached for the lifetime of the loader, so the loader is meant to serve a single response
+type Loader[K comparable, V any] struct {
+	fetch BatchFunc[K, V]
+	opts  options
+
+	mu      sync.Mutex
+	results map[K]*result[V]
+	batch   *batch[K, V]
+}
+
+func New[K comparable, V any](fetch BatchFunc[K, V], configuration ...Configuration) *Loader[K, V] {
+	l := &Loader[K, V]{
+		fetch:   fetch,
+		opts:    options{wait: DefaultWait, maxBatch: DefaultMaxBatch},
+		results: map[K]*result[V]{},
+	}
+
+	for _, cfg := range configuration {
+		cfg(&l.opts)
+	}
+
+	return l
+}
+
+// Load returns value of the key once the batch it was collected in has been fetched
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+
+	r, ok := l.results[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.results[key] = r
+		l.enqueue(ctx, key, r)
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+
+		return zero, ctx.Err()
+	}
+}
+
+// enqueue adds the key to the collected batch, it must be called with the lock held
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, r *result[V]) {
+	if l.batch == nil {
+		b := &batch[K, V]{ctx: ctx}
+		l.batch = b
+
+		time.AfterFunc(l.opts.wait, func() {
+			l.mu.Lock()
+			if l.batch != b {
+				// the batch has been sent already as it was full
+				l.mu.Unlock()
+
+				return
+			}
+
+			l.batch = nil
+			l.mu.Unlock()
+
+			l.run(b)
+		})
+	}
+
+	l.batch.keys = append(l.batch.keys, key)
+	l.batch.results = append(l.batch.results, r)
+
+	if len(l.batch.keys) >= l.opts.maxBatch {
+		go l.run(l.batch)
+		l.batch = nil
+	}
+}
+
+func (l *Loader[K, V]) run(b *batch[K, V]) {
+	values, err := l.fetch(b.ctx, b.keys)
+
+	for i, key := range b.keys {
+		r := b.results[i]
+
+		switch value, ok := values[key]; {
+		case err != nil:
+			r.err = err
+		case !ok:
+			r.err = ErrNotFound
+		default:
+			r.value = value
+		}
+
+		close(r.done)
+	}
+}
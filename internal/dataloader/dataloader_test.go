@@ -0,0 +1,136 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fetchRecorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (f *fetchRecorder) fetch(_ context.Context, keys []int) (map[int]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sorted := append([]int{}, keys...)
+	sort.Ints(sorted)
+	f.batches = append(f.batches, sorted)
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	values := map[int]string{}
+	for _, k := range keys {
+		if k > 0 {
+			values[k] = string(rune('a' + k - 1))
+		}
+	}
+
+	return values, nil
+}
+
+func loadAll(l *Loader[int, string], keys ...int) ([]string, []error) {
+	values, errs := make([]string, len(keys)), make([]error, len(keys))
+
+	var wg sync.WaitGroup
+	for i, k := range keys {
+		wg.Add(1)
+
+		go func(i, k int) {
+			defer wg.Done()
+			values[i], errs[i] = l.Load(context.Background(), k)
+		}(i, k)
+	}
+
+	wg.Wait()
+
+	return values, errs
+}
+
+func TestLoader_Load(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []Configuration
+		keys        []int
+		fetchErr    error
+		wantValues  []string
+		wantErrs    []error
+		wantBatches [][]int
+	}{
+		{
+			name:        "keys are fetched at once and cached",
+			options:     []Configuration{WithWait(50 * time.Millisecond)},
+			keys:        []int{1, 2, 3, 2},
+			wantValues:  []string{"a", "b", "c", "b"},
+			wantErrs:    []error{nil, nil, nil, nil},
+			wantBatches: [][]int{{1, 2, 3}},
+		},
+		{
+			name:        "key without value",
+			options:     []Configuration{WithWait(50 * time.Millisecond)},
+			keys:        []int{1, -1},
+			wantValues:  []string{"a", ""},
+			wantErrs:    []error{nil, ErrNotFound},
+			wantBatches: [][]int{{-1, 1}},
+		},
+		{
+			name:        "failed fetch",
+			keys:        []int{1},
+			fetchErr:    errors.New("connection lost"),
+			wantValues:  []string{""},
+			wantErrs:    []error{errors.New("connection lost")},
+			wantBatches: [][]int{{1}},
+		},
+		{
+			name:        "full batch is fetched without waiting",
+			options:     []Configuration{WithWait(time.Hour), WithMaxBatch(2)},
+			keys:        []int{1, 2},
+			wantValues:  []string{"a", "b"},
+			wantErrs:    []error{nil, nil},
+			wantBatches: [][]int{{1, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &fetchRecorder{err: tt.fetchErr}
+			loader := New(recorder.fetch, tt.options...)
+
+			values, errs := loadAll(loader, tt.keys...)
+
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("Load() values = %v, want %v", values, tt.wantValues)
+			}
+
+			for i, err := range errs {
+				if (err == nil) != (tt.wantErrs[i] == nil) || (err != nil && err.Error() != tt.wantErrs[i].Error()) {
+					t.Errorf("Load(%d) error = %v, want %v", tt.keys[i], err, tt.wantErrs[i])
+				}
+			}
+
+			if !reflect.DeepEqual(recorder.batches, tt.wantBatches) {
+				t.Errorf("Load() batches = %v, want %v", recorder.batches, tt.wantBatches)
+			}
+		})
+	}
+}
+
+func TestLoader_LoadCancelled(t *testing.T) {
+	loader := New((&fetchRecorder{}).fetch, WithWait(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := loader.Load(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,51 @@
+package di
+
+import (
+	"fmt"
+	"os"
+
+	"event-service/internal/auth"
+	"event-service/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TokenVerifier() (auth.TokenVerifier, error) {
+	cfg := []auth.VerifierConfiguration{
+		auth.WithIssuer(config.GetString("AUTH.ISSUER")),
+		auth.WithAudience(config.GetString("AUTH.AUDIENCE")),
+	}
+
+	switch algorithm := config.GetStringOrFallback("AUTH.ALGORITHM", auth.AlgorithmHS256); algorithm {
+	case auth.AlgorithmHS256:
+		secret := config.GetString("AUTH.SECRET")
+		if secret == "" {
+			return nil, fmt.Errorf("missing configuration parameter AUTH.SECRET for %s tokens", algorithm)
+		}
+
+		return auth.NewHS256Verifier([]byte(secret), cfg...), nil
+	case auth.AlgorithmRS256:
+		pem, readErr := os.ReadFile(config.GetString("AUTH.PUBLIC_KEY"))
+		if readErr != nil {
+			return nil, fmt.Errorf("cannot read AUTH.PUBLIC_KEY for %s tokens: %w", algorithm, readErr)
+		}
+
+		key, keyErr := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if keyErr != nil {
+			return nil, fmt.Errorf("cannot parse AUTH.PUBLIC_KEY: %w", keyErr)
+		}
+
+		return auth.NewRS256Verifier(key, cfg...), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", auth.ErrUnsupportedKeyType, algorithm)
+	}
+}
+
+// CalendarTokens issues tokens of the calendar links, they are signed with their own secret,
+// without it the calendar is available only with the bearer token of the header
+func CalendarTokens() *auth.CalendarTokens {
+	return auth.NewCalendarTokens(
+		[]byte(config.GetString("AUTH.CALENDAR.SECRET")),
+		config.GetDuration("AUTH.CALENDAR.TOKEN_TTL"),
+	)
+}
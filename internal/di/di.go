@@ -1,28 +1,30 @@
 package di
 
 import (
+	"event-service/email"
 	"event-service/graph"
 	"event-service/internal/config"
 	"event-service/internal/database"
 	gorminternal "event-service/internal/database/gorm"
+	inmemmoryrepository "event-service/internal/database/inmemmory/repository"
+	"event-service/internal/exchange"
+	"event-service/internal/pubsub"
+	"event-service/internal/tracing"
 
-	"github.com/streadway/amqp"
 	"gorm.io/gorm"
 )
 
 type Container struct {
-	db             *gorm.DB
-	ampqConnection map[string]*amqp.Connection
-	ampqChannels   map[string]*amqp.Channel
-	ampqQueues     map[string]struct{}
+	db                 *gorm.DB
+	ampqConnection     *exchange.Connection
+	eventsStorage      *inmemmoryrepository.EventsStorage
+	invitationsStorage *inmemmoryrepository.InvitationsStorage
+	outboxStorage      *inmemmoryrepository.OutboxStorage
+	mailer             *email.Mailer
+	subscriptionBroker *pubsub.Broker
 }
 
-var container = &Container{
-	db:             nil,
-	ampqConnection: make(map[string]*amqp.Connection),
-	ampqChannels:   make(map[string]*amqp.Channel),
-	ampqQueues:     make(map[string]struct{}),
-}
+var container = &Container{}
 
 func DatabaseParameters() database.Parameters {
 	return database.Parameters{
@@ -48,11 +50,31 @@ func GORM() *gorm.DB {
 		panic(err)
 	}
 
+	if err := db.Use(tracing.GORMPlugin{}); err != nil {
+		panic(err)
+	}
+
 	container.db = db
 
 	return container.db
 }
 
+// CloseGORM closes the database connection pool, it is opened again on the next use
+func CloseGORM() error {
+	if container.db == nil {
+		return nil
+	}
+
+	sqlDB, err := container.db.DB()
+	if err != nil {
+		return err
+	}
+
+	container.db = nil
+
+	return sqlDB.Close()
+}
+
 func DefaultGraphQLApiResolver() (r *graph.Resolver, err error) {
 	r = &graph.Resolver{}
 
@@ -72,5 +94,15 @@ func DefaultGraphQLApiResolver() (r *graph.Resolver, err error) {
 		return nil, err
 	}
 
+	if r.RemoveEventHandler, err = DefaultEventsRemoveHandler(); err != nil {
+		return nil, err
+	}
+
+	if r.SubscriptionHandler, err = DefaultEventSubscriptionHandler(); err != nil {
+		return nil, err
+	}
+
+	r.CalendarTokens, r.APIURL = CalendarTokens(), config.GetString("API.URL")
+
 	return r, nil
 }
@@ -0,0 +1,52 @@
+package di
+
+import (
+	"fmt"
+
+	"event-service/email"
+	"event-service/internal/config"
+)
+
+const (
+	EmailDriverSMTP   = "smtp"
+	EmailDriverFile   = "file"
+	EmailDriverMemory = "memory"
+)
+
+func EmailSender() (email.Sender, error) {
+	switch driver := config.GetString("EMAIL.DRIVER"); driver {
+	case EmailDriverSMTP:
+		return email.NewSMTPSender(email.SMTPParameters{
+			Host:     config.GetStringOrFallback("EMAIL.SMTP.HOST", "localhost"),
+			Port:     config.GetIntOrFallback("EMAIL.SMTP.PORT", 25),
+			Username: config.GetString("EMAIL.SMTP.USERNAME"),
+			Password: config.GetString("EMAIL.SMTP.PASSWORD"),
+		}), nil
+	case EmailDriverFile:
+		return email.NewFileSender(config.GetStringOrFallback("EMAIL.DIRECTORY", "var/mail"))
+	case EmailDriverMemory:
+		return email.NewInMemorySender(), nil
+	default:
+		return nil, fmt.Errorf("unsupported EMAIL.DRIVER %q", driver)
+	}
+}
+
+func Mailer() (*email.Mailer, error) {
+	if container.mailer != nil {
+		return container.mailer, nil
+	}
+
+	sender, err := EmailSender()
+	if err != nil {
+		return nil, err
+	}
+
+	container.mailer, err = email.NewMailer(
+		email.WithSender(sender),
+		email.WithAddressBook(email.PatternAddressBook(config.GetStringOrFallback("EMAIL.RECIPIENT", "{user}@casper.local"))),
+		email.WithFrom(config.GetStringOrFallback("EMAIL.FROM", "events@casper.local")),
+		email.WithBaseURL(config.GetString("API.URL")),
+	)
+
+	return container.mailer, err
+}
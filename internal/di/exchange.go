@@ -1,120 +1,140 @@
 package di
 
 import (
+	"context"
+	"time"
+
 	"event-service/internal/config"
 	"event-service/internal/exchange"
-	"event-service/internal/exchange/event"
+	eventupdate "event-service/internal/exchange/event"
+	"event-service/internal/exchange/eventcancel"
+	"event-service/internal/exchange/eventdelete"
+	"event-service/internal/exchange/eventinvitation"
+	"event-service/internal/outbox"
 
 	log "github.com/sirupsen/logrus"
-	"github.com/streadway/amqp"
 )
 
-func NewAmpqConnection(connectionString string) *amqp.Connection {
-	if container.ampqConnection[connectionString] != nil {
-		return container.ampqConnection[connectionString]
-	}
-
-	conn, err := amqp.Dial(connectionString)
-	if err != nil {
-		panic(err)
+// AmpqConnection returns the connection manager of the message broker, it reconnects in the background
+// and declares again the exchanges and queues after every reconnection
+func AmpqConnection() *exchange.Connection {
+	if container.ampqConnection != nil {
+		return container.ampqConnection
 	}
 
-	container.ampqConnection[connectionString] = conn
+	container.ampqConnection = exchange.NewConnection(
+		config.GetString("AMPQ"),
+		exchange.WithReconnectDelay(
+			config.GetDuration("AMPQ_RECONNECT.DELAY"),
+			config.GetDuration("AMPQ_RECONNECT.MAX_DELAY"),
+		),
+	)
+	container.ampqConnection.Start()
 
-	return container.ampqConnection[connectionString]
+	return container.ampqConnection
 }
 
-func NewAmpqExchange(name string) *amqp.Channel {
-	if container.ampqChannels[name] != nil {
-		return container.ampqChannels[name]
-	}
-
-	channel, chanErr := NewDefaultAmpqConnection().Channel()
-	if chanErr != nil {
-		log.Panic("Failed to open a channel", chanErr)
-	}
-
-	if err := channel.ExchangeDeclare(
-		name,
-		"topic",
-		true,
-		false,
-		false,
-		false,
-		nil,
-	); err != nil {
+func DeclareAmpqExchange(name string) {
+	if err := AmpqConnection().Declare(exchange.DeclareExchange(name)); err != nil {
 		log.Panic("Failed to declare an exchange", err)
 	}
-
-	container.ampqChannels[name] = channel
-
-	return container.ampqChannels[name]
 }
 
-func NewAmpqQueue(queue, exchangeKey, exchange string) *amqp.Channel {
-	channel := NewAmpqExchange(exchange)
-
-	q, err := channel.QueueDeclare(
-		queue,
-		true,
-		false,
-		false,
-		false,
-		nil,
-	)
-	if err != nil {
+// DeclareAmpqQueue declares the queue bound to the exchange together with its dead letter and retry queues
+func DeclareAmpqQueue(queue, exchangeKey, exchangeName string, retryDelays ...time.Duration) {
+	if err := AmpqConnection().Declare(exchange.DeclareQueue(queue, exchangeKey, exchangeName, retryDelays...)); err != nil {
 		log.Panic("Failed to declare a queue", err)
 	}
+}
 
-	if err = channel.QueueBind(
-		q.Name,
-		exchangeKey,
-		exchange,
-		false,
-		nil,
-	); err != nil {
-		log.Panic("Failed to bind a queue", err)
+func CloseAllExchangeConnections() {
+	if container.ampqConnection == nil {
+		return
 	}
 
-	return channel
+	if err := container.ampqConnection.Close(); err != nil {
+		log.Error("Failed to close the amqp connection", err)
+	}
+
+	container.ampqConnection = nil
 }
 
-func CloseAmpqConnection(connectionString string) {
-	if container.ampqConnection[connectionString] != nil {
-		container.ampqConnection[connectionString].Close()
-	}
+// AmpqEnabled tells if the message broker is configured, without it the api works without publishing messages
+func AmpqEnabled() bool {
+	return config.GetString("AMPQ") != ""
 }
 
-func CloseAmpqExchange(name string) {
-	if container.ampqChannels[name] != nil {
-		container.ampqChannels[name].Close()
-	}
+func NewEventUpdateProducer() exchange.Producer {
+	return newProducer(eventupdate.Route)
 }
 
-func CloseAllExchangeConnections() {
-	for s, channel := range container.ampqChannels {
-		channel.Close()
-		delete(container.ampqChannels, s)
-	}
+func NewEventCancelProducer() exchange.Producer {
+	return newProducer(eventcancel.Route)
+}
 
-	for i, conn := range container.ampqConnection {
-		conn.Close()
-		delete(container.ampqConnection, i)
-	}
+func NewEventDeleteProducer() exchange.Producer {
+	return newProducer(eventdelete.Route)
 }
 
-func NewDefaultAmpqConnection() *amqp.Connection {
-	return NewAmpqConnection(config.GetString("AMPQ"))
+func NewInvitationProducer(messageType eventinvitation.MessageType) exchange.Producer {
+	return newProducer(eventinvitation.RouteOf(messageType))
 }
 
-func NewEventUpdateProducer() *eventupdate.Producer {
-	return eventupdate.NewProducer(NewAmpqExchange(eventupdate.ExchangeKey))
+// OutboxRelay publishes messages written to the outbox on the casper-events exchange
+func OutboxRelay() (*outbox.Relay, error) {
+	DeclareAmpqExchange(exchange.CasperEventName)
+
+	publisher := exchange.NewConfirmPublisher(func(ctx context.Context) (exchange.ConfirmChannel, error) {
+		return AmpqConnection().Channel(ctx)
+	})
+
+	return outbox.NewRelay(
+		outbox.WithStore(OutboxStore()),
+		outbox.WithPublisher(outbox.NewAMQPPublisher(publisher)),
+		outbox.WithTransactor(Transactor()),
+		outbox.WithRetention(config.GetDuration("OUTBOX.RETENTION")),
+		outbox.WithMaxAttempts(config.GetInt("OUTBOX.MAX_ATTEMPTS")),
+	)
 }
 
 func NewEventUpdateConsumer() *exchange.DefaultConsumer {
-	return exchange.NewConsumer(eventupdate.QueueName, NewAmpqQueue(
+	consumer := exchange.NewConsumer(
 		eventupdate.QueueName,
-		eventupdate.ExchangeKey,
-		exchange.CasperEventName,
-	))
+		func(ctx context.Context) (exchange.Channel, error) {
+			return AmpqConnection().Channel(ctx)
+		},
+		ConsumerConfiguration()...,
+	)
+
+	DeclareAmpqQueue(eventupdate.QueueName, eventupdate.ExchangeKey, exchange.CasperEventName, consumer.RetryDelays()...)
+
+	return consumer
+}
+
+// NewInvitationConsumer consumes all invitation transitions
+func NewInvitationConsumer() *exchange.DefaultConsumer {
+	consumer := exchange.NewConsumer(
+		eventinvitation.QueueName,
+		func(ctx context.Context) (exchange.Channel, error) {
+			return AmpqConnection().Channel(ctx)
+		},
+		ConsumerConfiguration()...,
+	)
+
+	DeclareAmpqQueue(eventinvitation.QueueName, eventinvitation.RoutingPattern, exchange.CasperEventName, consumer.RetryDelays()...)
+
+	return consumer
+}
+
+// ConsumerConfiguration reads prefetch, concurrency and retry settings of the consumers
+func ConsumerConfiguration() []exchange.ConsumerConfiguration {
+	return []exchange.ConsumerConfiguration{
+		exchange.WithPrefetch(config.GetInt("CONSUMER.PREFETCH")),
+		exchange.WithConcurrency(config.GetInt("CONSUMER.CONCURRENCY")),
+		exchange.WithRetries(
+			config.GetInt("CONSUMER.MAX_ATTEMPTS"),
+			config.GetDuration("CONSUMER.RETRY_DELAY"),
+			config.GetDuration("CONSUMER.MAX_RETRY_DELAY"),
+		),
+	}
 }
@@ -1,9 +1,12 @@
 package di
 
 import (
+	"event-service/internal/exchange/eventinvitation"
+	"event-service/internal/metrics"
 	"event-service/internal/observers"
 	"event-service/internal/services/eventcreator"
 	"event-service/internal/services/eventfinder"
+	"event-service/internal/services/eventremover"
 	"event-service/internal/services/eventupdater"
 	"event-service/internal/services/invitation"
 )
@@ -11,12 +14,16 @@ import (
 func DefaultEventsAddHandler() (*eventcreator.EventCreator, error) {
 	return eventcreator.NewEventCreator(
 		eventcreator.WithAdderRepository(EventsRepository()),
+		eventcreator.WithTransactor(Transactor()),
+		eventcreator.WithObservers(observers.NewCounterObserver(metrics.EventsCreated)),
 	)
 }
 
 func DefaultEventsListHandler() (*eventfinder.EventFinder, error) {
 	return eventfinder.NewEventFinder(
 		eventfinder.WithFinderRepository(EventsRepository()),
+		eventfinder.WithBatchFinderRepository(EventsRepository()),
+		eventfinder.WithInvitationListerRepository(InvitationRepository()),
 	)
 }
 
@@ -25,21 +32,67 @@ func DefaultInvitationHandler() (*invitation.Invitation, error) {
 		invitation.WithEventFinderRepository(EventsRepository()),
 		invitation.WithInvitationRepository(InvitationRepository()),
 		invitation.WithInviteFinderRepository(InvitationRepository()),
+		invitation.WithTransactor(Transactor()),
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	i.AddObserver(invitation.UserInvitedEvent, observers.NewInvitationNotificationObserver())
+	i.AddObserver(invitation.UserAcceptedEvent, observers.NewInvitationCounterObserver(metrics.InvitationsAccepted))
+	i.AddObserver(invitation.UserWaitlistedEvent, observers.NewInvitationCounterObserver(metrics.InvitationsWaitlisted))
+
+	// transitions are published on the exchange or, without the message broker, to the subscriptions only,
+	// the email service sends the notifications consuming them from the exchange
+	for eventType, messageType := range map[invitation.EventType]eventinvitation.MessageType{
+		invitation.UserInvitedEvent:    eventinvitation.UserInvited,
+		invitation.UserAcceptedEvent:   eventinvitation.UserAccepted,
+		invitation.UserJoinedEvent:     eventinvitation.UserJoined,
+		invitation.UserRemovedEvent:    eventinvitation.UserRemoved,
+		invitation.UserWaitlistedEvent: eventinvitation.UserWaitlisted,
+		invitation.UserPromotedEvent:   eventinvitation.UserPromoted,
+		invitation.UserDeclinedEvent:   eventinvitation.UserDeclined,
+		invitation.UserRevokedEvent:    eventinvitation.UserRevoked,
+		invitation.UserLeftEvent:       eventinvitation.UserLeft,
+	} {
+		i.AddObserver(eventType, observers.NewInvitationLifecycleObserver(NewInvitationProducer(messageType), messageType))
+	}
 
 	return i, nil
 }
 
 func DefaultEventsUpdateHandler() (*eventupdater.EventUpdater, error) {
-	return eventupdater.NewEventUpdater(
+	invitationHandler, err := DefaultInvitationHandler()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := []eventupdater.Configuration{
 		eventupdater.WithUpdaterRepository(EventsRepository()),
 		eventupdater.WithFinderRepository(EventsRepository()),
+		eventupdater.WithTransactor(Transactor()),
+		eventupdater.WithObservers(observers.NewWaitlistPromotionObserver(invitationHandler)),
 		eventupdater.WithObservers(observers.NewEventUpdateObserver(NewEventUpdateProducer())),
-	)
+	}
+
+	return eventupdater.NewEventUpdater(cfg...)
+}
+
+func DefaultEventsRemoveHandler() (*eventremover.EventRemover, error) {
+	cfg := []eventremover.Configuration{
+		eventremover.WithUpdaterRepository(EventsRepository()),
+		eventremover.WithRemoverRepository(EventsRepository()),
+		eventremover.WithFinderRepository(EventsRepository()),
+		eventremover.WithTransactor(Transactor()),
+		eventremover.WithObservers(
+			eventremover.EventCancelled,
+			observers.NewEventCancelObserver(NewEventCancelProducer()),
+		),
+		eventremover.WithObservers(
+			eventremover.EventDeleted,
+			observers.NewEventDeleteObserver(NewEventDeleteProducer()),
+		),
+	}
+
+	return eventremover.NewEventRemover(cfg...)
 }
@@ -0,0 +1,41 @@
+package di
+
+import (
+	"context"
+
+	"event-service/internal/config"
+	"event-service/internal/database"
+	"event-service/internal/health"
+	"event-service/migrations"
+)
+
+// HealthChecker checks the database with its migrations and the message broker, unused dependencies are not checked
+func HealthChecker() (*health.Checker, error) {
+	configuration := []health.CheckerConfiguration{
+		health.WithTimeout(config.GetDuration("API.HEALTH.TIMEOUT")),
+	}
+
+	if !UsesInMemoryStorage() {
+		db, err := GORM().DB()
+		if err != nil {
+			return nil, err
+		}
+
+		configuration = append(configuration, health.WithCheck("mysql", db.PingContext))
+
+		latest, latestErr := database.LatestMigration(migrations.FS)
+		if latestErr != nil {
+			configuration = append(configuration, health.WithCheck("migrations", func(context.Context) error {
+				return latestErr
+			}))
+		} else {
+			configuration = append(configuration, health.WithCheck("migrations", database.MigrationsApplied(db, latest)))
+		}
+	}
+
+	if AmpqEnabled() {
+		configuration = append(configuration, health.WithCheck("amqp", AmpqConnection().Ping))
+	}
+
+	return health.NewChecker(configuration...), nil
+}
@@ -1,13 +1,76 @@
 package di
 
 import (
+	"event-service/internal/config"
+	"event-service/internal/database"
+	gorminternal "event-service/internal/database/gorm"
 	"event-service/internal/database/gorm/repository"
+	inmemmoryrepository "event-service/internal/database/inmemmory/repository"
+	"event-service/internal/domain/event"
+	"event-service/internal/outbox"
+	"event-service/internal/services"
 )
 
-func EventsRepository() *repository.EventRepository {
+// InMemoryStorageDriver keeps all the data in the process memory, it is meant for tests and local demos
+const InMemoryStorageDriver = "inmemory"
+
+func StorageDriver() string {
+	return config.GetStringOrFallback("STORAGE.DRIVER", database.DriverName)
+}
+
+func UsesInMemoryStorage() bool {
+	return StorageDriver() == InMemoryStorageDriver
+}
+
+func EventsRepository() event.Repository {
+	if UsesInMemoryStorage() {
+		return inMemoryEventsStorage()
+	}
+
 	return repository.NewEventRepository()
 }
 
-func InvitationRepository() *repository.InvitationRepository {
+func InvitationRepository() event.InvitationRepository {
+	if UsesInMemoryStorage() {
+		return inMemoryInvitationsStorage()
+	}
+
 	return repository.NewInvitationRepository()
 }
+
+func inMemoryEventsStorage() *inmemmoryrepository.EventsStorage {
+	if container.eventsStorage == nil {
+		container.eventsStorage = inmemmoryrepository.NewEventsStorage()
+	}
+
+	return container.eventsStorage
+}
+
+func inMemoryInvitationsStorage() *inmemmoryrepository.InvitationsStorage {
+	if container.invitationsStorage == nil {
+		container.invitationsStorage = inmemmoryrepository.NewLinkedInvitationsStorage(inMemoryEventsStorage())
+	}
+
+	return container.invitationsStorage
+}
+
+func OutboxStore() outbox.Store {
+	if !UsesInMemoryStorage() {
+		return repository.NewOutboxRepository()
+	}
+
+	if container.outboxStorage == nil {
+		container.outboxStorage = inmemmoryrepository.NewOutboxStorage()
+	}
+
+	return container.outboxStorage
+}
+
+// Transactor wraps service changes in the database transaction, in memory storage has no transactions
+func Transactor() services.Transactor {
+	if UsesInMemoryStorage() {
+		return services.WithoutTransaction{}
+	}
+
+	return gorminternal.NewTransactor()
+}
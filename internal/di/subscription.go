@@ -0,0 +1,60 @@
+package di
+
+import (
+	"context"
+
+	"event-service/internal/exchange"
+	"event-service/internal/exchange/event"
+	"event-service/internal/exchange/eventcancel"
+	"event-service/internal/exchange/eventdelete"
+	"event-service/internal/exchange/eventinvitation"
+	"event-service/internal/outbox"
+	"event-service/internal/pubsub"
+	"event-service/internal/services/eventsubscription"
+)
+
+// SubscriptionBroker delivers changes of the events to the GraphQL subscriptions of the api
+func SubscriptionBroker() *pubsub.Broker {
+	if container.subscriptionBroker == nil {
+		container.subscriptionBroker = pubsub.NewBroker()
+	}
+
+	return container.subscriptionBroker
+}
+
+// SubscriptionsBridge feeds the subscription broker from the casper-events exchange, so subscribers
+// of every api replica see changes made through any of them
+func SubscriptionsBridge() *pubsub.Bridge {
+	DeclareAmpqExchange(exchange.CasperEventName)
+
+	topics := []string{eventupdate.ExchangeKey, eventcancel.ExchangeKey, eventdelete.ExchangeKey}
+	for _, messageType := range eventinvitation.MessageTypes {
+		topics = append(topics, string(messageType))
+	}
+
+	return pubsub.NewBridge(
+		func(ctx context.Context) (pubsub.Channel, error) {
+			return AmpqConnection().Channel(ctx)
+		},
+		exchange.CasperEventName,
+		SubscriptionBroker(),
+		topics...,
+	)
+}
+
+func DefaultEventSubscriptionHandler() (*eventsubscription.EventSubscription, error) {
+	return eventsubscription.NewEventSubscription(
+		eventsubscription.WithFinderRepository(EventsRepository()),
+		eventsubscription.WithSubscriber(SubscriptionBroker()),
+	)
+}
+
+// newProducer writes messages of the route into the outbox when the message broker is configured, they reach
+// the subscriptions through the exchange then. Otherwise messages are passed to the subscriptions directly
+func newProducer(route exchange.Route) exchange.Producer {
+	if AmpqEnabled() {
+		return outbox.NewProducer(OutboxStore(), route)
+	}
+
+	return pubsub.NewProducer(SubscriptionBroker(), route.RoutingKey)
+}
@@ -0,0 +1,21 @@
+package di
+
+import (
+	"context"
+
+	"event-service/internal/config"
+	"event-service/internal/tracing"
+)
+
+// SetupTracing registers the tracer provider of the service, the returned function flushes pending spans
+func SetupTracing(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	return tracing.Setup(ctx, tracing.Parameters{
+		Exporter:       config.GetString("TRACING.EXPORTER"),
+		ServiceName:    serviceName,
+		ServiceVersion: config.GetString("VERSION"),
+		Endpoint:       config.GetString("TRACING.OTLP.ENDPOINT"),
+		Insecure:       config.GetBool("TRACING.OTLP.INSECURE"),
+		File:           config.GetString("TRACING.FILE"),
+		SampleRatio:    config.GetFloat("TRACING.SAMPLE_RATIO"),
+	})
+}
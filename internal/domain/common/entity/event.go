@@ -1,13 +1,26 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// EventStatus describes the lifecycle stage of the event
+type EventStatus string
+
+const (
+	EventStatusScheduled EventStatus = "scheduled"
+	EventStatusCancelled EventStatus = "cancelled"
+)
+
 type Event struct {
-	ExternalID  uuid.UUID
-	Name        string
-	Description string
-	Capacity    int
-	Public      bool
+	ExternalID         uuid.UUID
+	Name               string
+	Description        string
+	Capacity           int
+	Public             bool
+	Status             EventStatus
+	CancellationReason string
+	CancelledAt        *time.Time
 }
@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalid matches every validation error, the invalid fields are listed by Errors
+var ErrInvalid = errors.New("validation failed")
+
+// FieldError tells why the value of the input field is invalid
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func NewFieldError(field string, err error) FieldError {
+	return FieldError{Field: field, Err: err}
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+func (e FieldError) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// Errors aggregates all invalid fields of the input
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fe := range e {
+		messages = append(messages, fe.Error())
+	}
+
+	return ErrInvalid.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e Errors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, fe := range e {
+		errs = append(errs, fe)
+	}
+
+	return errs
+}
+
+func (e Errors) Is(target error) bool {
+	return target == ErrInvalid
+}
@@ -0,0 +1,31 @@
+package validation
+
+// Validator collects errors of all invalid fields, so the client can fix them at once
+type Validator struct {
+	errs Errors
+}
+
+// Check reports the field with the error unless the condition holds
+func (v *Validator) Check(valid bool, field string, err error) {
+	if !valid {
+		v.errs = append(v.errs, NewFieldError(field, err))
+	}
+}
+
+// Add reports the field when the error is not nil
+func (v *Validator) Add(field string, err error) {
+	v.Check(err == nil, field, err)
+}
+
+func (v *Validator) Valid() bool {
+	return len(v.errs) == 0
+}
+
+// Err returns the aggregated field errors or nil when all fields are valid
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+
+	return v.errs
+}
@@ -0,0 +1,36 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidator_Err(t *testing.T) {
+	errNegative := errors.New("must not be negative")
+	errTooLong := errors.New("is too long")
+
+	v := Validator{}
+	v.Check(true, "name", errTooLong)
+	v.Add("description", nil)
+
+	if err := v.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+
+	v.Check(false, "capacity", errNegative)
+	v.Add("name", errTooLong)
+
+	err := v.Err()
+	if !errors.Is(err, ErrInvalid) || !errors.Is(err, errNegative) || !errors.Is(err, errTooLong) {
+		t.Errorf("Err() = %v, want validation error matching field errors", err)
+	}
+
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Field != "capacity" || errs[1].Field != "name" {
+		t.Errorf("Err() field errors = %v", errs)
+	}
+
+	if want := "validation failed: capacity: must not be negative; name: is too long"; err.Error() != want {
+		t.Errorf("Err() message = %q, want %q", err.Error(), want)
+	}
+}
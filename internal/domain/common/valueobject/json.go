@@ -0,0 +1,62 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// value objects keep their fields unexported, the json representation lets aggregates travel in the exchange messages
+
+type periodJSON struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (r Period) MarshalJSON() ([]byte, error) {
+	return json.Marshal(periodJSON{Start: r.startDate, End: r.endDate})
+}
+
+func (r *Period) UnmarshalJSON(data []byte) error {
+	p := periodJSON{}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+
+	r.startDate, r.endDate = p.Start, p.End
+
+	return nil
+}
+
+func (ep EventPeriod) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ep.period)
+}
+
+func (ep *EventPeriod) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &ep.period); err != nil {
+		return err
+	}
+
+	ep.duration = ep.period.End().Sub(ep.period.Start())
+
+	return nil
+}
+
+type locationJSON struct {
+	Lat  float64 `json:"lat"`
+	Long float64 `json:"long"`
+}
+
+func (l Location) MarshalJSON() ([]byte, error) {
+	return json.Marshal(locationJSON{Lat: l.lat, Long: l.long})
+}
+
+func (l *Location) UnmarshalJSON(data []byte) error {
+	loc := locationJSON{}
+	if err := json.Unmarshal(data, &loc); err != nil {
+		return err
+	}
+
+	l.lat, l.long = loc.Lat, loc.Long
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValueObjects_JSON(t *testing.T) {
+	eventPeriod, _ := EventPeriod{}.WithStartAndDuration(time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC), 2*time.Hour)
+
+	type aggregate struct {
+		EventPeriod EventPeriod
+		Period      Period
+		Location    Location
+	}
+
+	want := aggregate{
+		EventPeriod: eventPeriod,
+		Period:      Period{startDate: time.Date(2029, 12, 1, 0, 0, 0, 0, time.UTC), endDate: time.Date(2029, 12, 31, 0, 0, 0, 0, time.UTC)},
+		Location:    NewLocation(48.8584, 2.2945),
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	got := aggregate{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json round trip = %+v, want %+v", got, want)
+	}
+}
@@ -1,5 +1,7 @@
 package valueobject
 
+import "math"
+
 type Location struct {
 	lat  float64
 	long float64
@@ -16,3 +18,16 @@ func (l Location) Long() float64 {
 func NewLocation(lat float64, long float64) Location {
 	return Location{lat: lat, long: long}
 }
+
+const earthRadiusKm = 6371
+
+// DistanceTo returns great-circle distance in kilometers calculated with the haversine formula
+func (l Location) DistanceTo(o Location) float64 {
+	lat1, lat2 := l.lat*math.Pi/180, o.lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLong := (o.long - l.long) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLong/2)*math.Sin(dLong/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
@@ -0,0 +1,153 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// MaxRecurrenceCount limits the number of occurrences, so that expanding the series stays cheap
+const MaxRecurrenceCount = 1000
+
+var (
+	ErrInvalidRecurrenceRule = errors.New("invalid recurrence rule")
+	ErrRecurrenceTooFrequent = errors.New("recurrence cannot repeat more often than daily")
+	ErrRecurrenceUnbounded   = errors.New("recurrence must be limited with COUNT or UNTIL")
+	ErrRecurrenceTooLong     = fmt.Errorf("recurrence cannot have more than %d occurrences", MaxRecurrenceCount)
+)
+
+// Recurrence describes series of occurrences with the iCalendar RRULE (e.g. FREQ=WEEKLY;COUNT=10;BYDAY=TU)
+// and the dates excluded from the series, the series starts with the first event period
+type Recurrence struct {
+	rule       string
+	exceptions []time.Time
+}
+
+func NewRecurrence(rule string, exceptions ...time.Time) (Recurrence, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return Recurrence{}, nil
+	}
+
+	option, err := rrule.StrToROption(rule)
+	if err != nil {
+		return Recurrence{}, errors.Join(ErrInvalidRecurrenceRule, err)
+	}
+
+	if err := validateOption(*option); err != nil {
+		return Recurrence{}, errors.Join(ErrInvalidRecurrenceRule, err)
+	}
+
+	r := Recurrence{rule: rule}
+	for _, exception := range exceptions {
+		r.exceptions = append(r.exceptions, exception.Truncate(time.Second))
+	}
+
+	return r, nil
+}
+
+func validateOption(option rrule.ROption) error {
+	switch {
+	case option.Freq > rrule.DAILY:
+		return ErrRecurrenceTooFrequent
+	case option.Count == 0 && option.Until.IsZero():
+		return ErrRecurrenceUnbounded
+	case option.Count > MaxRecurrenceCount:
+		return ErrRecurrenceTooLong
+	}
+
+	return nil
+}
+
+// Validate checks that the series beginning at start, which length depends on it for UNTIL rules,
+// does not have more than MaxRecurrenceCount occurrences
+func (r Recurrence) Validate(start time.Time) error {
+	set, ok := r.set(start, MaxRecurrenceCount+1)
+	if !ok {
+		return nil
+	}
+
+	next := set.Iterator()
+	for i := 0; i <= MaxRecurrenceCount; i++ {
+		if _, ok := next(); !ok {
+			return nil
+		}
+	}
+
+	return errors.Join(ErrInvalidRecurrenceRule, ErrRecurrenceTooLong)
+}
+
+func (r Recurrence) IsSet() bool {
+	return r.rule != ""
+}
+
+func (r Recurrence) Rule() string {
+	return r.rule
+}
+
+func (r Recurrence) Exceptions() []time.Time {
+	return r.exceptions
+}
+
+// Between returns starts of the occurrences of the series beginning at start, within the given dates inclusively
+func (r Recurrence) Between(start, from, to time.Time) []time.Time {
+	set, ok := r.set(start, MaxRecurrenceCount)
+	if !ok {
+		return nil
+	}
+
+	return set.Between(from, to, true)
+}
+
+// Includes tells if the series beginning at start has an occurrence starting at the given date
+func (r Recurrence) Includes(start, date time.Time) bool {
+	return len(r.Between(start, date, date)) > 0
+}
+
+// Last returns start of the last occurrence of the series
+func (r Recurrence) Last(start time.Time) (time.Time, bool) {
+	set, ok := r.set(start, MaxRecurrenceCount)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	occurrences := set.All()
+	if len(occurrences) == 0 {
+		return time.Time{}, false
+	}
+
+	return occurrences[len(occurrences)-1], true
+}
+
+// set expands the series beginning at start up to the limit of occurrences, so that it is never expanded
+// beyond it, even for the rules stored before the limit was introduced
+func (r Recurrence) set(start time.Time, limit int) (*rrule.Set, bool) {
+	if !r.IsSet() {
+		return nil, false
+	}
+
+	option, err := rrule.StrToROption(r.rule)
+	if err != nil {
+		return nil, false
+	}
+
+	option.Dtstart = start
+
+	if option.Count == 0 || option.Count > limit {
+		option.Count = limit
+	}
+
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, false
+	}
+
+	set := &rrule.Set{}
+	set.RRule(rule)
+	set.SetExDates(r.exceptions)
+
+	return set, true
+}
@@ -0,0 +1,168 @@
+package valueobject
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecurrence_Between(t *testing.T) {
+	start := time.Date(2023, 1, 3, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		rule       string
+		exceptions []time.Time
+		from, to   time.Time
+		want       []time.Time
+		wantErr    error
+	}{
+		{
+			name: "weekly series limited with count",
+			rule: "FREQ=WEEKLY;COUNT=3",
+			from: start,
+			to:   start.AddDate(1, 0, 0),
+			want: []time.Time{start, start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)},
+		},
+		{
+			name: "rule prefix is accepted",
+			rule: "RRULE:FREQ=WEEKLY;COUNT=2",
+			from: start,
+			to:   start.AddDate(1, 0, 0),
+			want: []time.Time{start, start.AddDate(0, 0, 7)},
+		},
+		{
+			name: "weekly series on given days until the date",
+			rule: "FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20230112T180000Z",
+			from: start,
+			to:   start.AddDate(1, 0, 0),
+			want: []time.Time{start, start.AddDate(0, 0, 2), start.AddDate(0, 0, 7), start.AddDate(0, 0, 9)},
+		},
+		{
+			name:       "exceptions are excluded from the series",
+			rule:       "FREQ=WEEKLY;COUNT=3",
+			exceptions: []time.Time{start.AddDate(0, 0, 7)},
+			from:       start,
+			to:         start.AddDate(1, 0, 0),
+			want:       []time.Time{start, start.AddDate(0, 0, 14)},
+		},
+		{
+			name: "only occurrences within the dates are returned",
+			rule: "FREQ=WEEKLY;COUNT=52",
+			from: start.AddDate(0, 0, 1),
+			to:   start.AddDate(0, 0, 8),
+			want: []time.Time{start.AddDate(0, 0, 7)},
+		},
+		{
+			name:    "invalid rule",
+			rule:    "FREQ=SOMETIMES",
+			wantErr: ErrInvalidRecurrenceRule,
+		},
+		{
+			name:    "rule repeating more often than daily",
+			rule:    "FREQ=SECONDLY;COUNT=100000000",
+			wantErr: ErrRecurrenceTooFrequent,
+		},
+		{
+			name:    "infinite rule",
+			rule:    "FREQ=DAILY",
+			wantErr: ErrRecurrenceUnbounded,
+		},
+		{
+			name:    "rule with too many occurrences",
+			rule:    "FREQ=DAILY;COUNT=1001",
+			wantErr: ErrRecurrenceTooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRecurrence(tt.rule, tt.exceptions...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewRecurrence() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := r.Between(start, tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Between() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrence_Last(t *testing.T) {
+	start := time.Date(2023, 1, 3, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		rule   string
+		want   time.Time
+		wantOk bool
+	}{
+		{
+			name:   "series limited with count",
+			rule:   "FREQ=DAILY;COUNT=5",
+			want:   start.AddDate(0, 0, 4),
+			wantOk: true,
+		},
+		{
+			name:   "series limited with date",
+			rule:   "FREQ=DAILY;UNTIL=20230110T180000Z",
+			want:   start.AddDate(0, 0, 7),
+			wantOk: true,
+		},
+		{
+			name:   "no recurrence",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := NewRecurrence(tt.rule)
+
+			got, ok := r.Last(start)
+			if ok != tt.wantOk || !got.Equal(tt.want) {
+				t.Errorf("Last() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestRecurrence_Validate(t *testing.T) {
+	start := time.Date(2023, 1, 3, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr error
+	}{
+		{
+			name: "series within the limit",
+			rule: "FREQ=DAILY;UNTIL=20250101T000000Z",
+		},
+		{
+			name:    "series until the date with too many occurrences",
+			rule:    "FREQ=DAILY;UNTIL=20300101T000000Z",
+			wantErr: ErrRecurrenceTooLong,
+		},
+		{
+			name: "no recurrence",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRecurrence(tt.rule)
+			if err != nil {
+				t.Fatalf("NewRecurrence() error = %v", err)
+			}
+
+			if err := r.Validate(start); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
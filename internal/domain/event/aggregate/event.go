@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/common/validation"
 	"event-service/internal/domain/common/valueobject"
 
 	"github.com/google/uuid"
@@ -13,6 +14,8 @@ import (
 var (
 	ErrUserIDRequired    = errors.New("user id cannot be empty")
 	ErrEventNameRequired = errors.New("event must be named")
+	ErrEventCancelled    = errors.New("event has been cancelled")
+	ErrVersionConflict   = errors.New("event has been modified in the meantime, fetch it again and retry")
 )
 
 type Event struct {
@@ -22,20 +25,29 @@ type Event struct {
 	Location           *Location
 	EventPeriod        valueobject.EventPeriod
 	RegistrationPeriod valueobject.Period
+	Recurrence         valueobject.Recurrence
+	Overrides          []OccurrenceOverride
 	Participants       []uuid.UUID
+	Waitlist           []uuid.UUID // users waiting for a free spot, in order of joining
+	Invitees           []uuid.UUID // users with a pending or declined invitation, they can see the private event
+	Sequence           uint        // revision number of the event, used by calendar clients to refresh their entries
+	Version            uint        // version of the stored event, moved by every write, guards against lost updates
+	CreatedAt          time.Time
 }
 
 type EventPayload struct {
-	UserID              uuid.UUID
-	Name                string
-	Description         string
-	Lat                 float64
-	Long                float64
-	Capacity            int
-	Duration            time.Duration
-	StartDate           time.Time
-	RegistrationEndDate time.Time
-	Public              bool
+	UserID               uuid.UUID
+	Name                 string
+	Description          string
+	Lat                  float64
+	Long                 float64
+	Capacity             int
+	Duration             time.Duration
+	StartDate            time.Time
+	RegistrationEndDate  time.Time
+	Public               bool
+	RecurrenceRule       string
+	RecurrenceExceptions []time.Time
 }
 
 func NewEvent(cfg EventPayload) (event *Event, err error) {
@@ -43,8 +55,10 @@ func NewEvent(cfg EventPayload) (event *Event, err error) {
 		return nil, ErrUserIDRequired
 	}
 
-	if cfg.Name == "" {
-		return nil, ErrEventNameRequired
+	now := time.Now()
+
+	if err = validatePayload(cfg, now); err != nil {
+		return nil, err
 	}
 
 	event = &Event{
@@ -55,20 +69,36 @@ func NewEvent(cfg EventPayload) (event *Event, err error) {
 			Description: cfg.Description,
 			Capacity:    cfg.Capacity,
 			Public:      cfg.Public,
+			Status:      entity.EventStatusScheduled,
 		},
 		Location: &Location{
 			Spot: valueobject.NewLocation(cfg.Lat, cfg.Long),
 		},
+		CreatedAt: now,
 	}
 
 	if event.EventPeriod, err = event.EventPeriod.WithStartAndDuration(cfg.StartDate, cfg.Duration); err != nil {
 		return nil, err
 	}
 
+	if event.Recurrence, err = valueobject.NewRecurrence(cfg.RecurrenceRule, cfg.RecurrenceExceptions...); err != nil {
+		return nil, validation.Errors{validation.NewFieldError("recurrence.rule", err)}
+	}
+
+	if err = event.Recurrence.Validate(cfg.StartDate); err != nil {
+		return nil, validation.Errors{validation.NewFieldError("recurrence.rule", err)}
+	}
+
 	if err = event.SetUpRegistrationPeriod(); err != nil {
 		return nil, err
 	}
 
+	if !cfg.RegistrationEndDate.IsZero() {
+		if event.RegistrationPeriod, err = event.RegistrationPeriod.WithStartAndEndDate(now, cfg.RegistrationEndDate); err != nil {
+			return nil, err
+		}
+	}
+
 	return event, nil
 }
 
@@ -84,6 +114,65 @@ func (e *Event) ParticipantsNumber() int {
 	return len(e.Participants)
 }
 
+func (e *Event) HasFreeSpot() bool {
+	return e.Event.Capacity > e.ParticipantsNumber()
+}
+
+// SpotsLeft returns number of users who can still take part in the event
+func (e *Event) SpotsLeft() int {
+	return max(e.Event.Capacity-e.ParticipantsNumber(), 0)
+}
+
+// WaitlistPosition returns position of the user on the waitlist starting from 1, or 0 when user is not waitlisted
+func (e *Event) WaitlistPosition(user uuid.UUID) int {
+	for i, u := range e.Waitlist {
+		if u == user {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// Revise marks the change of the event, it must be called for every change visible to the participants
+func (e *Event) Revise() {
+	e.Sequence++
+}
+
+// CheckVersion fails when the event has been changed since the client fetched the given version
+func (e *Event) CheckVersion(version uint) error {
+	if e.Version != version {
+		return ErrVersionConflict
+	}
+
+	return nil
+}
+
+func (e *Event) IsCancelled() bool {
+	return e.Event.Status == entity.EventStatusCancelled
+}
+
+// Cancel moves the event into the cancelled status, cancelled events cannot be joined anymore
+func (e *Event) Cancel(reason string) error {
+	if e.IsCancelled() {
+		return ErrEventCancelled
+	}
+
+	now := time.Now()
+	e.Event.Status = entity.EventStatusCancelled
+	e.Event.CancellationReason = reason
+	e.Event.CancelledAt = &now
+	e.Revise()
+
+	return nil
+}
+
+// RegistrationOpen reports whether users can join the event regardless of its capacity, users joining
+// the full event are waitlisted
+func (e *Event) RegistrationOpen() bool {
+	return !e.IsCancelled() && e.RegistrationPeriod.Contains(time.Now())
+}
+
 func (e *Event) OpenToJoin() bool {
-	return e.Event.Capacity > e.ParticipantsNumber() && e.RegistrationPeriod.Contains(time.Now())
+	return e.RegistrationOpen() && e.HasFreeSpot()
 }
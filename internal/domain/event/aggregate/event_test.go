@@ -1,15 +1,20 @@
 package aggregate
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"event-service/internal/domain/common/validation"
+
 	"github.com/google/uuid"
 )
 
 func TestNewEvent(t *testing.T) {
 	startDate := time.Now().Add(24 * 10 * time.Hour)
-	registrationDate := startDate.Add(24 * 10 * time.Hour)
+	registrationDate := startDate.Add(-24 * time.Hour)
 
 	tests := []struct {
 		name    string
@@ -136,6 +141,31 @@ func TestNewEvent(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:    "registration ending after the event starts",
+			args:    validPayload(func(p *EventPayload) { p.RegistrationEndDate = startDate.Add(time.Hour) }),
+			wantErr: true,
+		},
+		{
+			name:    "negative capacity",
+			args:    validPayload(func(p *EventPayload) { p.Capacity = -1 }),
+			wantErr: true,
+		},
+		{
+			name:    "latitude out of range",
+			args:    validPayload(func(p *EventPayload) { p.Lat = 500 }),
+			wantErr: true,
+		},
+		{
+			name:    "start date in the past",
+			args:    validPayload(func(p *EventPayload) { p.StartDate = time.Now().Add(-time.Hour) }),
+			wantErr: true,
+		},
+		{
+			name:    "too long name",
+			args:    validPayload(func(p *EventPayload) { p.Name = strings.Repeat("a", MaxNameLength+1) }),
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -147,3 +177,134 @@ func TestNewEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestEvent_Cancel(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   *Event
+		wantErr error
+	}{
+		{
+			name:  "scheduled event is cancelled",
+			event: newEventMock(2, time.Now().Add(24*time.Hour)),
+		},
+		{
+			name: "event cannot be cancelled twice",
+			event: func() *Event {
+				e := newEventMock(2, time.Now().Add(24*time.Hour))
+				_ = e.Cancel("first")
+
+				return e
+			}(),
+			wantErr: ErrEventCancelled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.event.Cancel("bad weather")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Cancel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.event.IsCancelled() || tt.event.Event.CancelledAt == nil {
+				t.Errorf("Cancel() event is not cancelled")
+			}
+
+			if tt.event.OpenToJoin() {
+				t.Errorf("OpenToJoin() cancelled event is open to join")
+			}
+		})
+	}
+}
+
+func validPayload(modify func(p *EventPayload)) EventPayload {
+	p := EventPayload{
+		UserID:    uuid.New(),
+		Name:      "Test Event",
+		Lat:       48.85853948884809,
+		Long:      2.2944598405858394,
+		Capacity:  10,
+		Duration:  2 * time.Hour,
+		StartDate: time.Now().Add(24 * time.Hour),
+	}
+	modify(&p)
+
+	return p
+}
+
+func TestNewEventReportsAllInvalidFields(t *testing.T) {
+	_, err := NewEvent(validPayload(func(p *EventPayload) {
+		p.Name = ""
+		p.Capacity = -5
+		p.Lat = 500
+		p.Long = -200
+		p.StartDate = time.Now().Add(-time.Hour)
+	}))
+
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("NewEvent() error = %v, want validation errors", err)
+	}
+
+	var fields []string
+	for _, fe := range errs {
+		fields = append(fields, fe.Field)
+	}
+
+	if want := []string{"name", "capacity", "latitude", "longitude", "startDate"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("NewEvent() invalid fields = %v, want %v", fields, want)
+	}
+
+	if !errors.Is(err, ErrEventNameRequired) || !errors.Is(err, ErrCapacityOutOfRange) {
+		t.Errorf("NewEvent() error = %v, want it to match the field errors", err)
+	}
+}
+
+func TestEvent_SpotsLeftAndRegistration(t *testing.T) {
+	tests := []struct {
+		name                 string
+		event                *Event
+		wantSpotsLeft        int
+		wantRegistrationOpen bool
+		wantOpenToJoin       bool
+	}{
+		{
+			name:                 "event with free spots",
+			event:                newEventMock(3, time.Now().Add(24*time.Hour), uuid.New()),
+			wantSpotsLeft:        2,
+			wantRegistrationOpen: true,
+			wantOpenToJoin:       true,
+		},
+		{
+			name:                 "full event is open to the waitlist",
+			event:                newEventMock(1, time.Now().Add(24*time.Hour), uuid.New()),
+			wantRegistrationOpen: true,
+		},
+		{
+			name:                 "event with reduced capacity",
+			event:                newEventMock(1, time.Now().Add(24*time.Hour), uuid.New(), uuid.New()),
+			wantRegistrationOpen: true,
+		},
+		{
+			name:          "registration ended",
+			event:         newEventMock(3, time.Now().Add(-time.Hour)),
+			wantSpotsLeft: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.SpotsLeft(); got != tt.wantSpotsLeft {
+				t.Errorf("SpotsLeft() = %d, want %d", got, tt.wantSpotsLeft)
+			}
+
+			if got := tt.event.RegistrationOpen(); got != tt.wantRegistrationOpen {
+				t.Errorf("RegistrationOpen() = %v, want %v", got, tt.wantRegistrationOpen)
+			}
+
+			if got := tt.event.OpenToJoin(); got != tt.wantOpenToJoin {
+				t.Errorf("OpenToJoin() = %v, want %v", got, tt.wantOpenToJoin)
+			}
+		})
+	}
+}
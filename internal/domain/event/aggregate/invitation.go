@@ -13,12 +13,35 @@ var (
 	ErrCapacityFull              = errors.New("event capacity has been reached")
 	ErrRegistrationClosed        = errors.New("event registration is closed")
 	ErrInvitationAlreadyAccepted = errors.New("invitation already accepted")
+	ErrAlreadyWaitlisted         = errors.New("user is already on the waitlist")
+	ErrNotWaitlisted             = errors.New("user is not on the waitlist")
+	ErrInvitationRemoved         = errors.New("user has been removed from the event")
+	ErrInvitationRevoked         = errors.New("invitation has been revoked")
+	ErrInvitationNotPending      = errors.New("invitation has already been answered")
+)
+
+// InvitationStatus describes relation of the invited user to the event
+type InvitationStatus string
+
+const (
+	InvitationInvited    InvitationStatus = "invited"
+	InvitationAccepted   InvitationStatus = "accepted"
+	InvitationDeclined   InvitationStatus = "declined"
+	InvitationWaitlisted InvitationStatus = "waitlisted"
+	InvitationRemoved    InvitationStatus = "removed"
+	InvitationRevoked    InvitationStatus = "revoked"
 )
 
 type Invitation struct {
-	Event       *Event
-	InvitedUser uuid.UUID
-	AcceptedAt  *time.Time
+	Event        *Event
+	InvitedUser  uuid.UUID
+	InvitedBy    uuid.UUID // the organizer who sent the invitation, empty for users who joined on their own
+	InvitedAt    time.Time
+	AcceptedAt   *time.Time
+	WaitlistedAt *time.Time
+	DeclinedAt   *time.Time
+	RevokedAt    *time.Time
+	RemovedAt    *time.Time
 }
 
 func NewInvitation(event *Event, participantID uuid.UUID) (i *Invitation, err error) {
@@ -33,28 +56,173 @@ func NewInvitation(event *Event, participantID uuid.UUID) (i *Invitation, err er
 	return &Invitation{
 		Event:       event,
 		InvitedUser: participantID,
+		InvitedAt:   time.Now(),
 	}, nil
 }
 
+// Status returns the current state of the invitation, the user removed from the event or the revoked invitation
+// has no other state and the user who declined the invitation can still accept it
+func (i *Invitation) Status() InvitationStatus {
+	switch {
+	case i.IsAccepted():
+		return InvitationAccepted
+	case i.IsWaitlisted():
+		return InvitationWaitlisted
+	case i.IsRemoved():
+		return InvitationRemoved
+	case i.IsRevoked():
+		return InvitationRevoked
+	case i.IsDeclined():
+		return InvitationDeclined
+	default:
+		return InvitationInvited
+	}
+}
+
+func (i *Invitation) IsRemoved() bool {
+	return i.RemovedAt != nil && !i.RemovedAt.IsZero()
+}
+
+func (i *Invitation) IsRevoked() bool {
+	return i.RevokedAt != nil && !i.RevokedAt.IsZero()
+}
+
+func (i *Invitation) IsDeclined() bool {
+	return i.DeclinedAt != nil && !i.DeclinedAt.IsZero()
+}
+
+// IsActive reports whether the invitation can still be answered, the removed user and the user whose invitation
+// has been revoked need a new invitation
+func (i *Invitation) IsActive() bool {
+	return !i.IsRemoved() && !i.IsRevoked()
+}
+
 func (i *Invitation) IsAccepted() bool {
 	return i.AcceptedAt != nil && !i.AcceptedAt.IsZero()
 }
 
+func (i *Invitation) IsWaitlisted() bool {
+	return !i.IsAccepted() && i.WaitlistedAt != nil && !i.WaitlistedAt.IsZero()
+}
+
 func (i *Invitation) Accept() error {
 	if i.IsAccepted() {
 		return ErrInvitationAlreadyAccepted
 	}
 
-	if i.Event.Event.Capacity <= i.Event.ParticipantsNumber() {
+	if err := i.checkActive(); err != nil {
+		return err
+	}
+
+	if i.Event.IsCancelled() {
+		return ErrEventCancelled
+	}
+
+	if !i.Event.HasFreeSpot() {
 		return ErrCapacityFull
 	}
 
+	if !i.Event.RegistrationOpen() {
+		return ErrRegistrationClosed
+	}
+
+	now := time.Now()
+	i.AcceptedAt = &now
+
+	return nil
+}
+
+// Waitlist queues the user for the spot freed in the full event, users are promoted in order they were waitlisted
+func (i *Invitation) Waitlist() error {
+	if i.IsAccepted() {
+		return ErrInvitationAlreadyAccepted
+	}
+
+	if i.IsWaitlisted() {
+		return ErrAlreadyWaitlisted
+	}
+
+	if err := i.checkActive(); err != nil {
+		return err
+	}
+
+	if i.Event.IsCancelled() {
+		return ErrEventCancelled
+	}
+
 	if !i.Event.RegistrationPeriod.Contains(time.Now()) {
 		return ErrRegistrationClosed
 	}
 
+	now := time.Now()
+	i.WaitlistedAt = &now
+
+	return nil
+}
+
+// Promote accepts waitlisted user once there is a free spot in the event, registration period is not checked
+// as spots can be freed after the registration ends
+func (i *Invitation) Promote() error {
+	if !i.IsWaitlisted() {
+		return ErrNotWaitlisted
+	}
+
+	if i.Event.IsCancelled() {
+		return ErrEventCancelled
+	}
+
+	if !i.Event.HasFreeSpot() {
+		return ErrCapacityFull
+	}
+
 	now := time.Now()
 	i.AcceptedAt = &now
+	i.WaitlistedAt = nil
+
+	return nil
+}
+
+// Remove takes the spot or the place on the waitlist away from the user, the invitation is kept to show the user
+// has been removed, the removed user can be invited again
+func (i *Invitation) Remove() {
+	now := time.Now()
+	i.RemovedAt = &now
+	i.AcceptedAt = nil
+	i.WaitlistedAt = nil
+}
+
+// Decline answers the pending invitation, the user can change their mind and accept it later
+func (i *Invitation) Decline() error {
+	if i.Status() != InvitationInvited {
+		return ErrInvitationNotPending
+	}
+
+	now := time.Now()
+	i.DeclinedAt = &now
+
+	return nil
+}
+
+// Revoke withdraws the invitation the user has not answered yet
+func (i *Invitation) Revoke() error {
+	if i.Status() != InvitationInvited {
+		return ErrInvitationNotPending
+	}
+
+	now := time.Now()
+	i.RevokedAt = &now
+
+	return nil
+}
+
+func (i *Invitation) checkActive() error {
+	if i.IsRemoved() {
+		return ErrInvitationRemoved
+	}
+
+	if i.IsRevoked() {
+		return ErrInvitationRevoked
+	}
 
 	return nil
 }
@@ -1,6 +1,7 @@
 package aggregate
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -18,6 +19,7 @@ func TestInvitation_Accept(t *testing.T) {
 		Event       *Event
 		InvitedUser uuid.UUID
 		Accepted    time.Time
+		Removed     *time.Time
 		wantErr     bool
 	}{
 		{
@@ -45,6 +47,24 @@ func TestInvitation_Accept(t *testing.T) {
 			InvitedUser: uuid.New(),
 			wantErr:     true,
 		},
+		{
+			name: "event has been cancelled",
+			Event: func() *Event {
+				e := newEventMock(2, time.Now().Add(24*time.Hour))
+				_ = e.Cancel("bad weather")
+
+				return e
+			}(),
+			InvitedUser: uuid.New(),
+			wantErr:     true,
+		},
+		{
+			name:        "user has been removed from the event",
+			Event:       newEventMock(2, time.Now().Add(24*time.Hour)),
+			InvitedUser: uuid.New(),
+			Removed:     func() *time.Time { t := time.Now().Add(-time.Hour); return &t }(),
+			wantErr:     true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -52,6 +72,7 @@ func TestInvitation_Accept(t *testing.T) {
 				Event:       tt.Event,
 				InvitedUser: tt.InvitedUser,
 				AcceptedAt:  &tt.Accepted,
+				RemovedAt:   tt.Removed,
 			}
 			if err := i.Accept(); (err != nil) != tt.wantErr {
 				t.Errorf("Accept() error = %v, wantErr %v", err, tt.wantErr)
@@ -60,6 +81,174 @@ func TestInvitation_Accept(t *testing.T) {
 	}
 }
 
+func TestInvitation_WaitlistAndPromote(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		event       *Event
+		waitlisted  *time.Time
+		accepted    *time.Time
+		waitlistErr error
+		promoteErr  error
+	}{
+		{
+			name:        "user is waitlisted and promoted once spot is freed",
+			event:       newEventMock(1, time.Now().Add(24*time.Hour), uuid.New()),
+			waitlistErr: nil,
+			promoteErr:  nil,
+		},
+		{
+			name:        "user already on the waitlist",
+			event:       newEventMock(1, time.Now().Add(24*time.Hour), uuid.New()),
+			waitlisted:  &hourAgo,
+			waitlistErr: ErrAlreadyWaitlisted,
+		},
+		{
+			name:        "accepted user cannot be waitlisted",
+			event:       newEventMock(1, time.Now().Add(24*time.Hour), uuid.New()),
+			accepted:    &hourAgo,
+			waitlistErr: ErrInvitationAlreadyAccepted,
+		},
+		{
+			name:        "waitlist is closed with the registration",
+			event:       newEventMock(1, time.Now().Add(-24*time.Hour), uuid.New()),
+			waitlistErr: ErrRegistrationClosed,
+		},
+		{
+			name:       "user cannot be promoted while event is full",
+			event:      newEventMock(1, time.Now().Add(24*time.Hour), uuid.New()),
+			promoteErr: ErrCapacityFull,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &Invitation{
+				Event:        tt.event,
+				InvitedUser:  uuid.New(),
+				AcceptedAt:   tt.accepted,
+				WaitlistedAt: tt.waitlisted,
+			}
+
+			if err := i.Waitlist(); !errors.Is(err, tt.waitlistErr) {
+				t.Errorf("Waitlist() error = %v, wantErr %v", err, tt.waitlistErr)
+			}
+
+			if tt.waitlistErr != nil {
+				return
+			}
+
+			if !errors.Is(tt.promoteErr, ErrCapacityFull) {
+				i.Event.Participants = nil
+			}
+
+			if err := i.Promote(); !errors.Is(err, tt.promoteErr) {
+				t.Errorf("Promote() error = %v, wantErr %v", err, tt.promoteErr)
+			}
+
+			if tt.promoteErr == nil && (!i.IsAccepted() || i.IsWaitlisted()) {
+				t.Errorf("Promote() user was not accepted")
+			}
+		})
+	}
+}
+
+func TestInvitation_Status(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		invitation Invitation
+		want       InvitationStatus
+	}{
+		{name: "pending invitation", invitation: Invitation{}, want: InvitationInvited},
+		{name: "accepted invitation", invitation: Invitation{AcceptedAt: &hourAgo}, want: InvitationAccepted},
+		{name: "waitlisted user", invitation: Invitation{WaitlistedAt: &hourAgo}, want: InvitationWaitlisted},
+		{name: "declined invitation", invitation: Invitation{DeclinedAt: &hourAgo}, want: InvitationDeclined},
+		{name: "removed user", invitation: Invitation{DeclinedAt: &hourAgo, RemovedAt: &hourAgo}, want: InvitationRemoved},
+		{name: "revoked invitation", invitation: Invitation{RevokedAt: &hourAgo}, want: InvitationRevoked},
+		{name: "declined invitation accepted later", invitation: Invitation{DeclinedAt: &hourAgo, AcceptedAt: &hourAgo}, want: InvitationAccepted},
+		{name: "promoted user", invitation: Invitation{AcceptedAt: &hourAgo, WaitlistedAt: &hourAgo}, want: InvitationAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.invitation.Status(); got != tt.want {
+				t.Errorf("Status() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvitation_Remove(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour)
+	i := &Invitation{Event: newEventMock(2, time.Now().Add(24*time.Hour)), InvitedUser: uuid.New(), AcceptedAt: &hourAgo}
+
+	i.Remove()
+
+	if i.Status() != InvitationRemoved || i.AcceptedAt != nil || i.WaitlistedAt != nil {
+		t.Errorf("Remove() status = %v, accepted at %v, waitlisted at %v", i.Status(), i.AcceptedAt, i.WaitlistedAt)
+	}
+
+	if err := i.Accept(); !errors.Is(err, ErrInvitationRemoved) {
+		t.Errorf("Accept() of the removed user error = %v, want %v", err, ErrInvitationRemoved)
+	}
+}
+
+func TestInvitation_DeclineAndRevoke(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		invitation  Invitation
+		wantDecline error
+		wantRevoke  error
+	}{
+		{name: "pending invitation", invitation: Invitation{}},
+		{
+			name:        "accepted invitation",
+			invitation:  Invitation{AcceptedAt: &hourAgo},
+			wantDecline: ErrInvitationNotPending,
+			wantRevoke:  ErrInvitationNotPending,
+		},
+		{
+			name:        "waitlisted user",
+			invitation:  Invitation{WaitlistedAt: &hourAgo},
+			wantDecline: ErrInvitationNotPending,
+			wantRevoke:  ErrInvitationNotPending,
+		},
+		{
+			name:        "declined invitation",
+			invitation:  Invitation{DeclinedAt: &hourAgo},
+			wantDecline: ErrInvitationNotPending,
+			wantRevoke:  ErrInvitationNotPending,
+		},
+		{
+			name:        "revoked invitation",
+			invitation:  Invitation{RevokedAt: &hourAgo},
+			wantDecline: ErrInvitationNotPending,
+			wantRevoke:  ErrInvitationNotPending,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			declined, revoked := tt.invitation, tt.invitation
+
+			if err := declined.Decline(); !errors.Is(err, tt.wantDecline) {
+				t.Errorf("Decline() error = %v, want %v", err, tt.wantDecline)
+			} else if err == nil && declined.Status() != InvitationDeclined {
+				t.Errorf("Decline() status = %v, want %v", declined.Status(), InvitationDeclined)
+			}
+
+			if err := revoked.Revoke(); !errors.Is(err, tt.wantRevoke) {
+				t.Errorf("Revoke() error = %v, want %v", err, tt.wantRevoke)
+			} else if err == nil && revoked.Status() != InvitationRevoked {
+				t.Errorf("Revoke() status = %v, want %v", revoked.Status(), InvitationRevoked)
+			}
+		})
+	}
+}
+
 func TestNewInvitation(t *testing.T) {
 	validParticipant := uuid.New()
 	validEvent := Event{
@@ -122,6 +311,13 @@ func TestNewInvitation(t *testing.T) {
 				t.Errorf("NewInvitation() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if gotI != nil {
+				if gotI.InvitedAt.IsZero() {
+					t.Errorf("NewInvitation() time of inviting is not set")
+				}
+
+				gotI.InvitedAt = time.Time{}
+			}
 			if !reflect.DeepEqual(gotI, tt.wantI) {
 				t.Errorf("NewInvitation() gotI = %v, want %v", gotI, tt.wantI)
 			}
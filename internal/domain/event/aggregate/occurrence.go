@@ -0,0 +1,153 @@
+package aggregate
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"event-service/internal/domain/common/valueobject"
+)
+
+// MaxOccurrencesRange limits the dates within which the occurrences are listed at once
+const MaxOccurrencesRange = 366 * 24 * time.Hour
+
+var (
+	ErrEventNotRecurring       = errors.New("event is not recurring")
+	ErrOccurrenceNotFound      = errors.New("event has no occurrence starting at the given date")
+	ErrOccurrencesRangeTooLong = errors.New("occurrences can be listed within at most 366 days")
+)
+
+// OccurrenceOverride changes a single occurrence of the recurring event, the occurrence is either cancelled or moved
+type OccurrenceOverride struct {
+	OriginalStart time.Time
+	Cancelled     bool
+	Period        valueobject.EventPeriod
+}
+
+// Occurrence is a single instance of the event, the event which is not recurring has exactly one occurrence
+type Occurrence struct {
+	OriginalStart time.Time
+	Period        valueobject.EventPeriod
+	Moved         bool
+}
+
+func (e *Event) IsRecurring() bool {
+	return e.Recurrence.IsSet()
+}
+
+// Occurrences returns occurrences starting within the given dates with the overrides applied, cancelled ones are skipped
+func (e *Event) Occurrences(from, to time.Time) []Occurrence {
+	if !e.IsRecurring() {
+		if start := e.EventPeriod.Start(); !start.Before(from) && !start.After(to) {
+			return []Occurrence{{OriginalStart: start, Period: e.EventPeriod}}
+		}
+
+		return nil
+	}
+
+	occurrences := make([]Occurrence, 0)
+
+	for _, start := range e.Recurrence.Between(e.EventPeriod.Start(), from, to) {
+		if _, overridden := e.override(start); overridden {
+			continue
+		}
+
+		period, err := e.EventPeriod.WithStartAndDuration(start, e.EventPeriod.Duration())
+		if err != nil {
+			continue
+		}
+
+		occurrences = append(occurrences, Occurrence{OriginalStart: start, Period: period})
+	}
+
+	for _, o := range e.Overrides {
+		if start := o.Period.Start(); o.Cancelled || start.Before(from) || start.After(to) {
+			continue
+		}
+
+		occurrences = append(occurrences, Occurrence{OriginalStart: o.OriginalStart, Period: o.Period, Moved: true})
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].Period.Start().Before(occurrences[j].Period.Start())
+	})
+
+	return occurrences
+}
+
+// RecurrenceEnd returns the end of the last occurrence, single events have no recurrence end
+func (e *Event) RecurrenceEnd() (time.Time, bool) {
+	if !e.IsRecurring() {
+		return time.Time{}, false
+	}
+
+	last, ok := e.Recurrence.Last(e.EventPeriod.Start())
+	if !ok {
+		return time.Time{}, false
+	}
+
+	end := last.Add(e.EventPeriod.Duration())
+	for _, o := range e.Overrides {
+		if !o.Cancelled && o.Period.End().After(end) {
+			end = o.Period.End()
+		}
+	}
+
+	return end, true
+}
+
+// CancelOccurrence cancels a single occurrence of the series starting at the given date
+func (e *Event) CancelOccurrence(originalStart time.Time) error {
+	if err := e.validateOccurrence(originalStart); err != nil {
+		return err
+	}
+
+	e.setOverride(OccurrenceOverride{OriginalStart: originalStart, Cancelled: true})
+
+	return nil
+}
+
+// MoveOccurrence moves a single occurrence of the series starting at the given date to the new period
+func (e *Event) MoveOccurrence(originalStart time.Time, period valueobject.EventPeriod) error {
+	if err := e.validateOccurrence(originalStart); err != nil {
+		return err
+	}
+
+	e.setOverride(OccurrenceOverride{OriginalStart: originalStart, Period: period})
+
+	return nil
+}
+
+func (e *Event) validateOccurrence(originalStart time.Time) error {
+	if !e.IsRecurring() {
+		return ErrEventNotRecurring
+	}
+
+	if !e.Recurrence.Includes(e.EventPeriod.Start(), originalStart) {
+		return ErrOccurrenceNotFound
+	}
+
+	return nil
+}
+
+func (e *Event) override(originalStart time.Time) (OccurrenceOverride, bool) {
+	for _, o := range e.Overrides {
+		if o.OriginalStart.Equal(originalStart) {
+			return o, true
+		}
+	}
+
+	return OccurrenceOverride{}, false
+}
+
+func (e *Event) setOverride(override OccurrenceOverride) {
+	for i, o := range e.Overrides {
+		if o.OriginalStart.Equal(override.OriginalStart) {
+			e.Overrides[i] = override
+
+			return
+		}
+	}
+
+	e.Overrides = append(e.Overrides, override)
+}
@@ -0,0 +1,148 @@
+package aggregate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/common/valueobject"
+
+	"github.com/google/uuid"
+)
+
+func newRecurringEventMock(rule string) *Event {
+	start := time.Date(2023, 1, 3, 18, 0, 0, 0, time.UTC)
+	period, _ := valueobject.EventPeriod{}.WithStartAndDuration(start, 2*time.Hour)
+	recurrence, _ := valueobject.NewRecurrence(rule)
+
+	return &Event{
+		UserID:      uuid.New(),
+		Event:       &entity.Event{ExternalID: uuid.New(), Name: "Weekly meetup"},
+		EventPeriod: period,
+		Recurrence:  recurrence,
+	}
+}
+
+func TestEvent_Occurrences(t *testing.T) {
+	start := time.Date(2023, 1, 3, 18, 0, 0, 0, time.UTC)
+	moved := start.AddDate(0, 0, 8)
+
+	tests := []struct {
+		name     string
+		event    func() *Event
+		from, to time.Time
+		want     []time.Time
+	}{
+		{
+			name:  "single event has one occurrence",
+			event: func() *Event { return newRecurringEventMock("") },
+			from:  start.AddDate(0, 0, -1),
+			to:    start.AddDate(0, 0, 1),
+			want:  []time.Time{start},
+		},
+		{
+			name:  "occurrences of the series",
+			event: func() *Event { return newRecurringEventMock("FREQ=WEEKLY;COUNT=3") },
+			from:  start,
+			to:    start.AddDate(1, 0, 0),
+			want:  []time.Time{start, start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)},
+		},
+		{
+			name: "cancelled occurrence is skipped",
+			event: func() *Event {
+				e := newRecurringEventMock("FREQ=WEEKLY;COUNT=3")
+				_ = e.CancelOccurrence(start.AddDate(0, 0, 7))
+
+				return e
+			},
+			from: start,
+			to:   start.AddDate(1, 0, 0),
+			want: []time.Time{start, start.AddDate(0, 0, 14)},
+		},
+		{
+			name: "moved occurrence is placed at the new date",
+			event: func() *Event {
+				e := newRecurringEventMock("FREQ=WEEKLY;COUNT=3")
+				period, _ := valueobject.EventPeriod{}.WithStartAndDuration(moved, time.Hour)
+				_ = e.MoveOccurrence(start.AddDate(0, 0, 7), period)
+
+				return e
+			},
+			from: start,
+			to:   start.AddDate(1, 0, 0),
+			want: []time.Time{start, moved, start.AddDate(0, 0, 14)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occurrences := tt.event().Occurrences(tt.from, tt.to)
+
+			got := make([]time.Time, len(occurrences))
+			for i, o := range occurrences {
+				got[i] = o.Period.Start()
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Occurrences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvent_OverrideOccurrence(t *testing.T) {
+	start := time.Date(2023, 1, 3, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		rule          string
+		originalStart time.Time
+		wantErr       error
+	}{
+		{
+			name:          "occurrence of the series",
+			rule:          "FREQ=WEEKLY;COUNT=3",
+			originalStart: start.AddDate(0, 0, 7),
+		},
+		{
+			name:          "date which is not an occurrence of the series",
+			rule:          "FREQ=WEEKLY;COUNT=3",
+			originalStart: start.AddDate(0, 0, 1),
+			wantErr:       ErrOccurrenceNotFound,
+		},
+		{
+			name:          "event is not recurring",
+			originalStart: start,
+			wantErr:       ErrEventNotRecurring,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newRecurringEventMock(tt.rule)
+
+			if err := e.CancelOccurrence(tt.originalStart); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CancelOccurrence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := e.MoveOccurrence(tt.originalStart, e.EventPeriod); !errors.Is(err, tt.wantErr) {
+				t.Errorf("MoveOccurrence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && len(e.Overrides) != 1 {
+				t.Errorf("Overrides = %v, want single override replaced by the latest change", e.Overrides)
+			}
+		})
+	}
+}
+
+func TestEvent_RecurrenceEnd(t *testing.T) {
+	finite := newRecurringEventMock("FREQ=DAILY;COUNT=3")
+	if end, ok := finite.RecurrenceEnd(); !ok || !end.Equal(time.Date(2023, 1, 5, 20, 0, 0, 0, time.UTC)) {
+		t.Errorf("RecurrenceEnd() = %v, %v", end, ok)
+	}
+
+	if _, ok := newRecurringEventMock("").RecurrenceEnd(); ok {
+		t.Errorf("RecurrenceEnd() single event has an end of the series")
+	}
+}
@@ -0,0 +1,85 @@
+package aggregate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"event-service/internal/domain/common/validation"
+	"event-service/internal/domain/common/valueobject"
+)
+
+const (
+	MaxCapacity          = 100000
+	MaxNameLength        = 200
+	MaxDescriptionLength = 5000
+)
+
+var (
+	ErrCapacityOutOfRange        = fmt.Errorf("capacity must be between 0 and %d", MaxCapacity)
+	ErrLatitudeOutOfRange        = errors.New("latitude must be between -90 and 90")
+	ErrLongitudeOutOfRange       = errors.New("longitude must be between -180 and 180")
+	ErrNameTooLong               = fmt.Errorf("name cannot be longer than %d characters", MaxNameLength)
+	ErrDescriptionTooLong        = fmt.Errorf("description cannot be longer than %d characters", MaxDescriptionLength)
+	ErrStartDateInPast           = errors.New("event cannot start in the past")
+	ErrRegistrationEndAfterStart = errors.New("registration must end before the event starts")
+	ErrRegistrationEndInPast     = errors.New("registration cannot end in the past")
+)
+
+// Validate checks invariants of the event, all invalid fields are reported together
+func (e *Event) Validate() error {
+	v := validation.Validator{}
+
+	validateDetails(&v, e.Event.Name, e.Event.Description, e.Event.Capacity)
+
+	if e.Location != nil {
+		validateLocation(&v, e.Location.Spot.Lat(), e.Location.Spot.Long())
+	}
+
+	v.Check(!e.RegistrationPeriod.End().After(e.EventPeriod.Start()), "registrationEndDate", ErrRegistrationEndAfterStart)
+	v.Add("recurrence.rule", e.Recurrence.Validate(e.EventPeriod.Start()))
+
+	return v.Err()
+}
+
+// ValidateStartDate checks that the new date of the event is in the future
+func ValidateStartDate(v *validation.Validator, start, now time.Time) {
+	v.Check(start.IsZero() || start.After(now), "startDate", ErrStartDateInPast)
+}
+
+func validatePayload(cfg EventPayload, now time.Time) error {
+	v := validation.Validator{}
+
+	validateDetails(&v, cfg.Name, cfg.Description, cfg.Capacity)
+	validateLocation(&v, cfg.Lat, cfg.Long)
+	ValidateStartDate(&v, cfg.StartDate, now)
+
+	if !cfg.RegistrationEndDate.IsZero() {
+		v.Check(cfg.RegistrationEndDate.After(now), "registrationEndDate", ErrRegistrationEndInPast)
+		v.Check(!cfg.RegistrationEndDate.After(cfg.StartDate), "registrationEndDate", ErrRegistrationEndAfterStart)
+	}
+
+	if cfg.Duration == 0 {
+		v.Add("duration", valueobject.ErrDurationRequired)
+	}
+
+	if cfg.StartDate.IsZero() {
+		v.Add("startDate", valueobject.ErrPeriodEmptyDates)
+	}
+
+	return v.Err()
+}
+
+func validateDetails(v *validation.Validator, name, description string, capacity int) {
+	v.Check(strings.TrimSpace(name) != "", "name", ErrEventNameRequired)
+	v.Check(utf8.RuneCountInString(name) <= MaxNameLength, "name", ErrNameTooLong)
+	v.Check(utf8.RuneCountInString(description) <= MaxDescriptionLength, "description", ErrDescriptionTooLong)
+	v.Check(capacity >= 0 && capacity <= MaxCapacity, "capacity", ErrCapacityOutOfRange)
+}
+
+func validateLocation(v *validation.Validator, lat, long float64) {
+	v.Check(lat >= -90 && lat <= 90, "latitude", ErrLatitudeOutOfRange)
+	v.Check(long >= -180 && long <= 180, "longitude", ErrLongitudeOutOfRange)
+}
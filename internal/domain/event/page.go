@@ -0,0 +1,69 @@
+package event
+
+import (
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/valueobject"
+)
+
+type Edge struct {
+	Cursor valueobject.Cursor
+	Event  *aggregate.Event
+}
+
+type Page struct {
+	Edges           []Edge
+	TotalCount      int64
+	HasNextPage     bool
+	HasPreviousPage bool
+}
+
+// NewPage builds a page from edges fetched in query order, repositories fetch one edge more than
+// the limit so that it can be determined if there are more elements to fetch
+func NewPage(edges []Edge, totalCount int64, pagination valueobject.Pagination) Page {
+	page := Page{TotalCount: totalCount}
+
+	hasMore := len(edges) > pagination.Limit()
+	if hasMore {
+		edges = edges[:pagination.Limit()]
+	}
+
+	if pagination.Backward() {
+		for i, j := 0, len(edges)-1; i < j; i, j = i+1, j-1 {
+			edges[i], edges[j] = edges[j], edges[i]
+		}
+
+		page.HasPreviousPage = hasMore
+		_, page.HasNextPage = pagination.Before()
+	} else {
+		page.HasNextPage = hasMore
+		_, page.HasPreviousPage = pagination.After()
+	}
+
+	page.Edges = edges
+
+	return page
+}
+
+func (p Page) Events() []*aggregate.Event {
+	events := make([]*aggregate.Event, len(p.Edges))
+	for i, edge := range p.Edges {
+		events[i] = edge.Event
+	}
+
+	return events
+}
+
+// CursorFor returns cursor pointing to the event in the list ordered by the given field,
+// distance is used only for lists ordered by the distance
+func CursorFor(field valueobject.OrderField, e *aggregate.Event, distance float64) valueobject.Cursor {
+	switch field {
+	case valueobject.OrderByCreatedAt:
+		return valueobject.NewTimeCursor(field, e.ID, e.CreatedAt)
+	case valueobject.OrderByName:
+		return valueobject.NewStringCursor(field, e.ID, e.Event.Name)
+	case valueobject.OrderByDistance:
+		return valueobject.NewFloatCursor(field, e.ID, distance)
+	default:
+		return valueobject.NewTimeCursor(valueobject.OrderByStartDate, e.ID, e.EventPeriod.Start())
+	}
+}
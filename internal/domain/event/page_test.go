@@ -0,0 +1,82 @@
+package event
+
+import (
+	"testing"
+
+	"event-service/internal/domain/event/valueobject"
+)
+
+func TestNewPage(t *testing.T) {
+	two := 2
+	cursor := valueobject.NewStringCursor(valueobject.OrderByName, 10, "name")
+
+	type args struct {
+		edges      int
+		pagination func() valueobject.Pagination
+	}
+
+	tests := []struct {
+		name         string
+		args         args
+		wantEdges    []uint
+		wantNext     bool
+		wantPrevious bool
+	}{
+		{
+			name: "first page with more elements",
+			args: args{edges: 3, pagination: func() valueobject.Pagination {
+				p, _ := valueobject.NewPagination(&two, nil, nil, nil)
+				return p
+			}},
+			wantEdges: []uint{1, 2},
+			wantNext:  true,
+		},
+		{
+			name: "last forward page after cursor",
+			args: args{edges: 2, pagination: func() valueobject.Pagination {
+				p, _ := valueobject.NewPagination(&two, nil, &cursor, nil)
+				return p
+			}},
+			wantEdges:    []uint{1, 2},
+			wantPrevious: true,
+		},
+		{
+			name: "backward page is returned in list order",
+			args: args{edges: 3, pagination: func() valueobject.Pagination {
+				p, _ := valueobject.NewPagination(nil, &two, nil, &cursor)
+				return p
+			}},
+			wantEdges:    []uint{2, 1},
+			wantNext:     true,
+			wantPrevious: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edges := make([]Edge, tt.args.edges)
+			for i := range edges {
+				edges[i] = Edge{Cursor: valueobject.NewStringCursor(valueobject.OrderByName, uint(i+1), "name")}
+			}
+
+			got := NewPage(edges, int64(tt.args.edges), tt.args.pagination())
+
+			if len(got.Edges) != len(tt.wantEdges) {
+				t.Fatalf("NewPage() got %d edges, want %d", len(got.Edges), len(tt.wantEdges))
+			}
+
+			for i, id := range tt.wantEdges {
+				if got.Edges[i].Cursor.ID != id {
+					t.Errorf("NewPage() edge %d id = %v, want %v", i, got.Edges[i].Cursor.ID, id)
+				}
+			}
+
+			if got.HasNextPage != tt.wantNext {
+				t.Errorf("NewPage() HasNextPage = %v, want %v", got.HasNextPage, tt.wantNext)
+			}
+
+			if got.HasPreviousPage != tt.wantPrevious {
+				t.Errorf("NewPage() HasPreviousPage = %v, want %v", got.HasPreviousPage, tt.wantPrevious)
+			}
+		})
+	}
+}
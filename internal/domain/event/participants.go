@@ -0,0 +1,77 @@
+package event
+
+import (
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/valueobject"
+
+	"github.com/google/uuid"
+)
+
+// ParticipantsRequest selects a page of the invitations of the event ordered by time of inviting,
+// invitations of all statuses are listed when the status is not given
+type ParticipantsRequest struct {
+	Status *aggregate.InvitationStatus
+	Limit  int
+	After  *valueobject.ParticipantCursor
+}
+
+// Matches checks the invitation against the status of the request
+func (r ParticipantsRequest) Matches(i *aggregate.Invitation) bool {
+	return r.Status == nil || i.Status() == *r.Status
+}
+
+type ParticipantEdge struct {
+	Cursor     valueobject.ParticipantCursor
+	Invitation *aggregate.Invitation
+	// WaitlistPosition is the position of the waitlisted user starting from 1, it is 0 for other invitations
+	WaitlistPosition int
+}
+
+type ParticipantPage struct {
+	Edges           []ParticipantEdge
+	TotalCount      int64
+	HasNextPage     bool
+	HasPreviousPage bool
+}
+
+// NewParticipantPage builds a page from edges fetched in order, repositories fetch one edge more than
+// the limit so that it can be determined if there are more invitations to fetch
+func NewParticipantPage(edges []ParticipantEdge, totalCount int64, request ParticipantsRequest) ParticipantPage {
+	page := ParticipantPage{
+		TotalCount:      totalCount,
+		HasNextPage:     len(edges) > request.Limit,
+		HasPreviousPage: request.After != nil,
+	}
+
+	if page.HasNextPage {
+		edges = edges[:request.Limit]
+	}
+
+	page.Edges = edges
+
+	return page
+}
+
+// ParticipantCursorFor returns cursor pointing to the invitation in the list of participants
+func ParticipantCursorFor(i *aggregate.Invitation) valueobject.ParticipantCursor {
+	return valueobject.ParticipantCursor{InvitedAt: i.InvitedAt.UTC(), User: i.InvitedUser}
+}
+
+// WaitlistPosition returns position of the user among waitlisted participants starting from 1, or 0 when user is not waitlisted
+func WaitlistPosition(participants []*aggregate.Invitation, user uuid.UUID) int {
+	position := 0
+
+	for _, ia := range participants {
+		if !ia.IsWaitlisted() {
+			continue
+		}
+
+		position++
+
+		if ia.InvitedUser == user {
+			return position
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,103 @@
+package policy
+
+import (
+	"errors"
+
+	"event-service/internal/domain/event/aggregate"
+
+	"github.com/google/uuid"
+)
+
+var ErrForbidden = errors.New("action is forbidden for the user")
+
+// Role describes relation of the user to the event
+type Role string
+
+const (
+	RoleOrganizer   Role = "organizer"
+	RoleParticipant Role = "participant"
+	RoleInvitee     Role = "invitee" // waitlisted user or user with an open invitation
+	RoleAnonymous   Role = "anonymous"
+)
+
+func RoleOf(e *aggregate.Event, user uuid.UUID) Role {
+	if user == uuid.Nil || e == nil {
+		return RoleAnonymous
+	}
+
+	if e.UserID == user {
+		return RoleOrganizer
+	}
+
+	for _, p := range e.Participants {
+		if p == user {
+			return RoleParticipant
+		}
+	}
+
+	for _, invitees := range [][]uuid.UUID{e.Waitlist, e.Invitees} {
+		for _, i := range invitees {
+			if i == user {
+				return RoleInvitee
+			}
+		}
+	}
+
+	return RoleAnonymous
+}
+
+// CanView allows everyone to see public events, private ones are visible to the organizer and the users invited
+// to the event, the repositories list events with the same rule
+func CanView(e *aggregate.Event, actor uuid.UUID) error {
+	if e.Event.Public || RoleOf(e, actor) != RoleAnonymous {
+		return nil
+	}
+
+	return ErrForbidden
+}
+
+// CanManageEvent allows only the organizer to edit the event
+func CanManageEvent(e *aggregate.Event, actor uuid.UUID) error {
+	if RoleOf(e, actor) != RoleOrganizer {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// CanInvite allows only the organizer to invite users to the event
+func CanInvite(e *aggregate.Event, actor uuid.UUID) error {
+	return CanManageEvent(e, actor)
+}
+
+// CanActAs allows users to accept invitations or join events only on their own behalf
+func CanActAs(user, actor uuid.UUID) error {
+	if user == uuid.Nil || user != actor {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// CanRemove allows the organizer to remove any participant and the participant to remove themselves
+func CanRemove(i *aggregate.Invitation, actor uuid.UUID) error {
+	if RoleOf(i.Event, actor) == RoleOrganizer {
+		return nil
+	}
+
+	return CanActAs(i.InvitedUser, actor)
+}
+
+// CanListInvitations allows everyone who can view the event to see its participants and the waitlist,
+// pending, declined and removed invitations are visible to the organizer only
+func CanListInvitations(e *aggregate.Event, actor uuid.UUID, status *aggregate.InvitationStatus) error {
+	if err := CanView(e, actor); err != nil {
+		return err
+	}
+
+	if status != nil && (*status == aggregate.InvitationAccepted || *status == aggregate.InvitationWaitlisted) {
+		return nil
+	}
+
+	return CanManageEvent(e, actor)
+}
@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/event/aggregate"
+
+	"github.com/google/uuid"
+)
+
+func TestRoleOf(t *testing.T) {
+	organizer, participant, waitlisted, invited := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	e := &aggregate.Event{
+		UserID:       organizer,
+		Event:        &entity.Event{},
+		Participants: []uuid.UUID{participant},
+		Waitlist:     []uuid.UUID{waitlisted},
+		Invitees:     []uuid.UUID{invited},
+	}
+
+	tests := []struct {
+		name string
+		user uuid.UUID
+		want Role
+	}{
+		{name: "organizer of the event", user: organizer, want: RoleOrganizer},
+		{name: "participant of the event", user: participant, want: RoleParticipant},
+		{name: "waitlisted user", user: waitlisted, want: RoleInvitee},
+		{name: "invited user", user: invited, want: RoleInvitee},
+		{name: "user not related to the event", user: uuid.New(), want: RoleAnonymous},
+		{name: "not authenticated user", user: uuid.Nil, want: RoleAnonymous},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoleOf(e, tt.user); got != tt.want {
+				t.Errorf("RoleOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanRemove(t *testing.T) {
+	organizer, participant := uuid.New(), uuid.New()
+	i := &aggregate.Invitation{
+		Event:       &aggregate.Event{UserID: organizer, Event: &entity.Event{}, Participants: []uuid.UUID{participant}},
+		InvitedUser: participant,
+	}
+
+	tests := []struct {
+		name    string
+		actor   uuid.UUID
+		wantErr error
+	}{
+		{name: "organizer removes participant", actor: organizer},
+		{name: "participant removes themselves", actor: participant},
+		{name: "other user removes participant", actor: uuid.New(), wantErr: ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CanRemove(i, tt.actor); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CanRemove() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanView(t *testing.T) {
+	organizer, participant := uuid.New(), uuid.New()
+	private := &aggregate.Event{UserID: organizer, Event: &entity.Event{}, Participants: []uuid.UUID{participant}}
+	public := &aggregate.Event{UserID: organizer, Event: &entity.Event{Public: true}}
+
+	tests := []struct {
+		name    string
+		event   *aggregate.Event
+		actor   uuid.UUID
+		wantErr error
+	}{
+		{name: "anyone views public event", event: public, actor: uuid.Nil},
+		{name: "organizer views private event", event: private, actor: organizer},
+		{name: "participant views private event", event: private, actor: participant},
+		{name: "other user views private event", event: private, actor: uuid.New(), wantErr: ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CanView(tt.event, tt.actor); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CanView() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanListInvitations(t *testing.T) {
+	organizer := uuid.New()
+	public := &aggregate.Event{UserID: organizer, Event: &entity.Event{Public: true}}
+	private := &aggregate.Event{UserID: organizer, Event: &entity.Event{}}
+	accepted, invited := aggregate.InvitationAccepted, aggregate.InvitationInvited
+
+	tests := []struct {
+		name    string
+		event   *aggregate.Event
+		actor   uuid.UUID
+		status  *aggregate.InvitationStatus
+		wantErr error
+	}{
+		{name: "anyone lists participants of public event", event: public, status: &accepted},
+		{name: "organizer lists all invitations", event: public, actor: organizer},
+		{name: "organizer lists pending invitations", event: private, actor: organizer, status: &invited},
+		{name: "other user lists all invitations", event: public, actor: uuid.New(), wantErr: ErrForbidden},
+		{name: "other user lists pending invitations", event: public, actor: uuid.New(), status: &invited, wantErr: ErrForbidden},
+		{name: "other user lists participants of private event", event: private, actor: uuid.New(), status: &accepted, wantErr: ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CanListInvitations(tt.event, tt.actor, tt.status); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CanListInvitations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
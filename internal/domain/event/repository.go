@@ -2,6 +2,7 @@ package event
 
 import (
 	"context"
+	"errors"
 
 	"event-service/internal/domain/event/aggregate"
 	"event-service/internal/domain/event/valueobject"
@@ -9,6 +10,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrNotFound is returned by the repositories for the event that does not exist
+var ErrNotFound = errors.New("event not found")
+
 type Adder interface {
 	Add(context.Context, *aggregate.Event) error
 }
@@ -17,17 +21,53 @@ type Updater interface {
 	Update(context.Context, *aggregate.Event) error
 }
 
+// Remover deletes the event permanently together with its invitations
+type Remover interface {
+	Delete(context.Context, *aggregate.Event) error
+}
+
 type Finder interface {
-	FindBy(context.Context, valueobject.ListRequest) ([]*aggregate.Event, error)
+	FindBy(context.Context, valueobject.ListRequest) (Page, error)
 	FindByExternalID(ctx context.Context, id uuid.UUID) (*aggregate.Event, error)
 }
 
+// BatchFinder loads many events at once, e.g. data of all events of the page is loaded with a single query
+type BatchFinder interface {
+	// FindByExternalIDs returns the events found, missing events are left out
+	FindByExternalIDs(ctx context.Context, ids []uuid.UUID) ([]*aggregate.Event, error)
+}
+
 type InviteFinder interface {
 	FindBy(ctx context.Context, eventID, userID uuid.UUID) (*aggregate.Invitation, error)
 }
 
+// InvitationLister lists invitations of the events, the listed invitations come without the event
+type InvitationLister interface {
+	// FindParticipants returns accepted invitations followed by the waitlisted ones in order of joining the waitlist,
+	// events nobody takes part in are left out
+	FindParticipants(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]*aggregate.Invitation, error)
+	// FindByEvent returns the page of the invitations of the event ordered by time of inviting
+	FindByEvent(ctx context.Context, eventID uuid.UUID, request ParticipantsRequest) (ParticipantPage, error)
+}
+
 type Inviter interface {
 	Invite(context.Context, *aggregate.Invitation) error
 	Accept(context.Context, *aggregate.Invitation) error
 	Remove(context.Context, *aggregate.Invitation) error
+	Decline(context.Context, *aggregate.Invitation) error
+	Revoke(context.Context, *aggregate.Invitation) error
+}
+
+type Repository interface {
+	Adder
+	Updater
+	Remover
+	Finder
+	BatchFinder
+}
+
+type InvitationRepository interface {
+	Inviter
+	InviteFinder
+	InvitationLister
 }
@@ -0,0 +1,106 @@
+package valueobject
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// Cursor points to a single element of an ordered list, it stores value of the ordered field
+// and the id of the element used as a tie-breaker
+type Cursor struct {
+	Field OrderField `json:"f"`
+	ID    uint       `json:"i"`
+	Value string     `json:"v"`
+}
+
+func NewTimeCursor(field OrderField, id uint, value time.Time) Cursor {
+	return Cursor{Field: field, ID: id, Value: value.UTC().Format(time.RFC3339Nano)}
+}
+
+func NewStringCursor(field OrderField, id uint, value string) Cursor {
+	return Cursor{Field: field, ID: id, Value: value}
+}
+
+func NewFloatCursor(field OrderField, id uint, value float64) Cursor {
+	return Cursor{Field: field, ID: id, Value: strconv.FormatFloat(value, 'f', -1, 64)}
+}
+
+func (c Cursor) TimeValue() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return time.Time{}, errors.Join(ErrInvalidCursor, err)
+	}
+
+	return t, nil
+}
+
+func (c Cursor) FloatValue() (float64, error) {
+	f, err := strconv.ParseFloat(c.Value, 64)
+	if err != nil {
+		return 0, errors.Join(ErrInvalidCursor, err)
+	}
+
+	return f, nil
+}
+
+// Encode returns opaque string representation of the cursor
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(s string) (c Cursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, errors.Join(ErrInvalidCursor, err)
+	}
+
+	if err = json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, errors.Join(ErrInvalidCursor, err)
+	}
+
+	if c.Field == "" || c.ID == 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// ParticipantCursor points to the invitation in the list ordered by time of inviting, the invited user
+// is used as a tie-breaker
+type ParticipantCursor struct {
+	InvitedAt time.Time `json:"t"`
+	User      uuid.UUID `json:"u"`
+}
+
+// Encode returns opaque string representation of the cursor
+func (c ParticipantCursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeParticipantCursor(s string) (c ParticipantCursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ParticipantCursor{}, errors.Join(ErrInvalidCursor, err)
+	}
+
+	if err = json.Unmarshal(data, &c); err != nil {
+		return ParticipantCursor{}, errors.Join(ErrInvalidCursor, err)
+	}
+
+	if c.User == uuid.Nil || c.InvitedAt.IsZero() {
+		return ParticipantCursor{}, ErrInvalidCursor
+	}
+
+	return c, nil
+}
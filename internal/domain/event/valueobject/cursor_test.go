@@ -0,0 +1,95 @@
+package valueobject
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Cursor
+		wantErr bool
+	}{
+		{
+			name:  "encoded time cursor",
+			value: NewTimeCursor(OrderByStartDate, 10, time.Date(2022, 10, 1, 5, 0, 0, 0, time.UTC)).Encode(),
+			want:  Cursor{Field: OrderByStartDate, ID: 10, Value: "2022-10-01T05:00:00Z"},
+		},
+		{
+			name:  "encoded float cursor",
+			value: NewFloatCursor(OrderByDistance, 3, 12.5).Encode(),
+			want:  Cursor{Field: OrderByDistance, ID: 3, Value: "12.5"},
+		},
+		{
+			name:    "not encoded value",
+			value:   "some cursor",
+			wantErr: true,
+		},
+		{
+			name:    "cursor without id",
+			value:   Cursor{Field: OrderByName, Value: "name"}.Encode(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeCursor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeCursor() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeParticipantCursor(t *testing.T) {
+	cursor := ParticipantCursor{InvitedAt: time.Date(2022, 10, 1, 5, 0, 0, 0, time.UTC), User: uuid.New()}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    ParticipantCursor
+		wantErr bool
+	}{
+		{
+			name:  "encoded cursor",
+			value: cursor.Encode(),
+			want:  cursor,
+		},
+		{
+			name:    "not encoded value",
+			value:   "some cursor",
+			wantErr: true,
+		},
+		{
+			name:    "cursor without user",
+			value:   ParticipantCursor{InvitedAt: cursor.InvitedAt}.Encode(),
+			wantErr: true,
+		},
+		{
+			name:    "cursor of the events list",
+			value:   NewTimeCursor(OrderByStartDate, 10, cursor.InvitedAt).Encode(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeParticipantCursor(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeParticipantCursor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeParticipantCursor() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
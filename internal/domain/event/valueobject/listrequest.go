@@ -3,23 +3,36 @@ package valueobject
 import (
 	"time"
 
+	commonvalueobject "event-service/internal/domain/common/valueobject"
+
+	"github.com/google/uuid"
 	"github.com/markphelps/optional"
 )
 
 //type optionalBool
 
 type ListRequest struct {
-	user     string
-	name     string
-	public   optional.Bool
-	interval TimeInterval
-	distance Distance
+	user        string
+	participant string
+	name        string
+	public      optional.Bool
+	interval    TimeInterval
+	distance    Distance
+	origin      *commonvalueobject.Location
+	order       Order
+	pagination  Pagination
+	viewer      *uuid.UUID
 }
 
 func (l ListRequest) User() (string, bool) {
 	return l.user, l.user != ""
 }
 
+// Participant returns the user that accepted invitations to the listed events
+func (l ListRequest) Participant() (string, bool) {
+	return l.participant, l.participant != ""
+}
+
 func (l ListRequest) Name() (string, bool) {
 	return l.name, l.name != ""
 }
@@ -30,6 +43,16 @@ func (l ListRequest) Public() (bool, bool) {
 	return p, err == nil
 }
 
+// Viewer returns the user the events are listed for, only public events, events organized by the user and events
+// the user is invited to are listed. Anonymous viewer is the nil uuid
+func (l ListRequest) Viewer() (uuid.UUID, bool) {
+	if l.viewer == nil {
+		return uuid.Nil, false
+	}
+
+	return *l.viewer, true
+}
+
 func (l ListRequest) Interval() (TimeInterval, bool) {
 	return l.interval, l.interval.IsSet()
 }
@@ -38,6 +61,23 @@ func (l ListRequest) Distance() (Distance, bool) {
 	return l.distance, l.distance.IsSet()
 }
 
+// Origin returns the point that distance is measured from, it is set together with the distance
+func (l ListRequest) Origin() (commonvalueobject.Location, bool) {
+	if l.origin == nil {
+		return commonvalueobject.Location{}, false
+	}
+
+	return *l.origin, true
+}
+
+func (l ListRequest) Order() Order {
+	return l.order
+}
+
+func (l ListRequest) Pagination() Pagination {
+	return l.pagination
+}
+
 type ListRequestConfiguration func(*ListRequest)
 
 func NewListRequest(configs ...ListRequestConfiguration) ListRequest {
@@ -66,6 +106,9 @@ func WithDistance(distance int64, lat, long float64) ListRequestConfiguration {
 			initLatitude:  lat,
 			initLongitude: long,
 		}
+
+		origin := commonvalueobject.NewLocation(lat, long)
+		r.origin = &origin
 	}
 }
 
@@ -86,3 +129,27 @@ func WithUser(user string) ListRequestConfiguration {
 		r.user = user
 	}
 }
+
+func WithParticipant(user string) ListRequestConfiguration {
+	return func(r *ListRequest) {
+		r.participant = user
+	}
+}
+
+func WithViewer(viewer uuid.UUID) ListRequestConfiguration {
+	return func(r *ListRequest) {
+		r.viewer = &viewer
+	}
+}
+
+func WithOrder(order Order) ListRequestConfiguration {
+	return func(r *ListRequest) {
+		r.order = order
+	}
+}
+
+func WithPagination(pagination Pagination) ListRequestConfiguration {
+	return func(r *ListRequest) {
+		r.pagination = pagination
+	}
+}
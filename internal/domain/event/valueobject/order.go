@@ -0,0 +1,68 @@
+package valueobject
+
+import "errors"
+
+var ErrDistanceOrderWithoutOrigin = errors.New("ordering by distance requires location to measure distance from")
+
+type OrderField string
+
+const (
+	OrderByStartDate OrderField = "start_date"
+	OrderByCreatedAt OrderField = "created_at"
+	OrderByDistance  OrderField = "distance"
+	OrderByName      OrderField = "name"
+)
+
+type OrderDirection string
+
+const (
+	OrderAscending  OrderDirection = "ASC"
+	OrderDescending OrderDirection = "DESC"
+)
+
+type Order struct {
+	field     OrderField
+	direction OrderDirection
+}
+
+// NewOrder creates ordering for the list, by default events are ordered ascending by start date
+func NewOrder(field OrderField, direction OrderDirection) Order {
+	if field == "" {
+		field = OrderByStartDate
+	}
+
+	if direction != OrderDescending {
+		direction = OrderAscending
+	}
+
+	return Order{field: field, direction: direction}
+}
+
+func (o Order) Field() OrderField {
+	if o.field == "" {
+		return OrderByStartDate
+	}
+
+	return o.field
+}
+
+func (o Order) Direction() OrderDirection {
+	if o.direction == "" {
+		return OrderAscending
+	}
+
+	return o.direction
+}
+
+func (o Order) IsDescending() bool {
+	return o.Direction() == OrderDescending
+}
+
+// Reversed returns order with the opposite direction, used when paginating backwards
+func (o Order) Reversed() Order {
+	if o.IsDescending() {
+		return NewOrder(o.Field(), OrderAscending)
+	}
+
+	return NewOrder(o.Field(), OrderDescending)
+}
@@ -0,0 +1,71 @@
+package valueobject
+
+import "errors"
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	ErrFirstAndLastCombined = errors.New("first and last cannot be used together")
+	ErrInvalidPageSize      = errors.New("page size must be between 0 and 100")
+)
+
+// Pagination describes a cursor based page, "first" pages forward and "last" pages backward,
+// zero value of the Pagination is the first page of the default size
+type Pagination struct {
+	limit    *int
+	backward bool
+	after    *Cursor
+	before   *Cursor
+}
+
+func NewPagination(first, last *int, after, before *Cursor) (Pagination, error) {
+	p := Pagination{after: after, before: before}
+
+	switch {
+	case first != nil && last != nil:
+		return Pagination{}, ErrFirstAndLastCombined
+	case first != nil:
+		p.limit = first
+	case last != nil:
+		p.limit = last
+		p.backward = true
+	}
+
+	if p.limit != nil && (*p.limit < 0 || *p.limit > MaxPageSize) {
+		return Pagination{}, ErrInvalidPageSize
+	}
+
+	return p, nil
+}
+
+// Limit returns number of elements requested for the page
+func (p Pagination) Limit() int {
+	if p.limit == nil {
+		return DefaultPageSize
+	}
+
+	return *p.limit
+}
+
+func (p Pagination) Backward() bool {
+	return p.backward
+}
+
+func (p Pagination) After() (Cursor, bool) {
+	if p.after == nil {
+		return Cursor{}, false
+	}
+
+	return *p.after, true
+}
+
+func (p Pagination) Before() (Cursor, bool) {
+	if p.before == nil {
+		return Cursor{}, false
+	}
+
+	return *p.before, true
+}
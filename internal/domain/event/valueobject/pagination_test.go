@@ -0,0 +1,77 @@
+package valueobject
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewPagination(t *testing.T) {
+	five, negative, tooMany := 5, -1, MaxPageSize+1
+	cursor := NewStringCursor(OrderByName, 1, "name")
+
+	type args struct {
+		first, last   *int
+		after, before *Cursor
+	}
+
+	tests := []struct {
+		name         string
+		args         args
+		wantLimit    int
+		wantBackward bool
+		wantErr      error
+	}{
+		{
+			name:      "default page size without limits",
+			args:      args{},
+			wantLimit: DefaultPageSize,
+		},
+		{
+			name:      "forward pagination",
+			args:      args{first: &five, after: &cursor},
+			wantLimit: 5,
+		},
+		{
+			name:         "backward pagination",
+			args:         args{last: &five, before: &cursor},
+			wantLimit:    5,
+			wantBackward: true,
+		},
+		{
+			name:    "first and last combined",
+			args:    args{first: &five, last: &five},
+			wantErr: ErrFirstAndLastCombined,
+		},
+		{
+			name:    "negative page size",
+			args:    args{first: &negative},
+			wantErr: ErrInvalidPageSize,
+		},
+		{
+			name:    "page size above maximum",
+			args:    args{last: &tooMany},
+			wantErr: ErrInvalidPageSize,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewPagination(tt.args.first, tt.args.last, tt.args.after, tt.args.before)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewPagination() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got.Limit() != tt.wantLimit {
+				t.Errorf("Limit() = %v, want %v", got.Limit(), tt.wantLimit)
+			}
+
+			if got.Backward() != tt.wantBackward {
+				t.Errorf("Backward() = %v, want %v", got.Backward(), tt.wantBackward)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+)
+
+var (
+	ErrConnectionClosed = errors.New("amqp connection manager is closed")
+	ErrNotConnected     = errors.New("not connected to the message broker")
+)
+
+const (
+	DefaultReconnectDelay    = time.Second
+	DefaultMaxReconnectDelay = 30 * time.Second
+)
+
+// Topology declares exchanges, queues and bindings, it is applied again after every reconnection
+type Topology func(ch *amqp.Channel) error
+
+type ConnectionConfiguration func(*Connection)
+
+// WithReconnectDelay sets delay of the first reconnection attempt, it doubles with every failed attempt up to the max delay
+func WithReconnectDelay(delay, maxDelay time.Duration) ConnectionConfiguration {
+	return func(c *Connection) {
+		if delay > 0 {
+			c.delay = delay
+		}
+
+		if maxDelay > 0 {
+			c.maxDelay = maxDelay
+		}
+	}
+}
+
+// Connection keeps the broker connection alive, it reconnects with backoff when the broker drops it
+// and re-declares the registered topology. Channels are opened on the current connection, their users
+// open new ones when the old ones are closed
+type Connection struct {
+	url      string
+	delay    time.Duration
+	maxDelay time.Duration
+
+	mu        sync.RWMutex
+	conn      *amqp.Connection
+	closed    bool
+	topology  []Topology
+	connected chan struct{}
+	done      chan struct{}
+	startOnce sync.Once
+}
+
+func NewConnection(url string, configuration ...ConnectionConfiguration) *Connection {
+	c := &Connection{
+		url:       url,
+		delay:     DefaultReconnectDelay,
+		maxDelay:  DefaultMaxReconnectDelay,
+		connected: make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	for _, cfg := range configuration {
+		cfg(c)
+	}
+
+	return c
+}
+
+// Start connects in the background and keeps reconnecting until the connection is closed
+func (c *Connection) Start() {
+	c.startOnce.Do(func() {
+		go c.run()
+	})
+}
+
+func (c *Connection) run() {
+	for attempt := 0; ; attempt++ {
+		conn, err := c.connect()
+		if errors.Is(err, ErrConnectionClosed) {
+			return
+		}
+
+		if err != nil {
+			delay := c.backoff(attempt)
+			log.WithError(err).WithField("retry_in", delay).Warn("cannot connect to the message broker")
+
+			select {
+			case <-c.done:
+				return
+			case <-time.After(delay):
+				continue
+			}
+		}
+
+		attempt = -1
+		closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+
+		select {
+		case <-c.done:
+			_ = conn.Close()
+
+			return
+		case amqpErr := <-closed:
+			log.WithField("reason", amqpErr).Warn("message broker connection lost, reconnecting")
+			c.disconnectedFrom(conn)
+		}
+	}
+}
+
+// connect dials the broker and applies the topology before the connection is handed to the channel users,
+// the connection dialled while the manager was being closed is dropped
+func (c *Connection) connect() (*amqp.Connection, error) {
+	conn, err := amqp.Dial(c.url)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		_ = conn.Close()
+
+		return nil, ErrConnectionClosed
+	}
+
+	for _, t := range c.topology {
+		if err := apply(conn, t); err != nil {
+			_ = conn.Close()
+
+			return nil, err
+		}
+	}
+
+	c.conn = conn
+	close(c.connected)
+
+	log.Info("connected to the message broker")
+
+	return conn, nil
+}
+
+// Declare registers the topology and applies it at once when the connection is established
+func (c *Connection) Declare(t Topology) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.topology = append(c.topology, t)
+
+	if c.conn == nil {
+		return nil
+	}
+
+	return apply(c.conn, t)
+}
+
+// Channel opens a channel on the current connection, it waits for the connection when the broker is unavailable
+func (c *Connection) Channel(ctx context.Context) (*amqp.Channel, error) {
+	for {
+		c.mu.RLock()
+		conn, connected := c.conn, c.connected
+		c.mu.RUnlock()
+
+		if conn != nil {
+			ch, err := conn.Channel()
+			if !errors.Is(err, amqp.ErrClosed) {
+				return ch, err
+			}
+
+			// the connection is being replaced, the next one is awaited
+			c.disconnectedFrom(conn)
+
+			continue
+		}
+
+		select {
+		case <-connected:
+		case <-c.done:
+			return nil, ErrConnectionClosed
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Ping reports an error while the connection to the broker is lost
+func (c *Connection) Ping(context.Context) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.conn == nil || c.conn.IsClosed() {
+		return ErrNotConnected
+	}
+
+	return nil
+}
+
+// disconnectedFrom forgets the closed connection unless it has been replaced already
+func (c *Connection) disconnectedFrom(conn *amqp.Connection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == conn {
+		c.conn = nil
+		c.connected = make(chan struct{})
+	}
+}
+
+// Close closes the current connection and stops reconnecting, the flag and the connection are swapped under
+// the same lock as in connect, so the connection dialled in the meantime is never handed to the channel users
+func (c *Connection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+
+	c.closed = true
+	close(c.done)
+
+	conn := c.conn
+	c.conn = nil
+
+	if conn == nil {
+		return nil
+	}
+
+	return conn.Close()
+}
+
+func (c *Connection) backoff(attempt int) time.Duration {
+	delay := c.delay
+	for i := 0; i < attempt && delay < c.maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, c.maxDelay)
+}
+
+func apply(conn *amqp.Connection, t Topology) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+
+	defer ch.Close()
+
+	return t(ch)
+}
+
+// DeclareExchange declares durable topic exchange
+func DeclareExchange(name string) Topology {
+	return func(ch *amqp.Channel) error {
+		return ch.ExchangeDeclare(name, "topic", true, false, false, false, nil)
+	}
+}
+
+// DeclareQueue declares the queue bound to the exchange together with its dead letter queue, to which rejected
+// messages are routed, and the retry queue of every delay, from which messages go back to the queue after the delay
+func DeclareQueue(queue, routingKey, exchangeName string, retryDelays ...time.Duration) Topology {
+	return func(ch *amqp.Channel) error {
+		if err := DeclareExchange(exchangeName)(ch); err != nil {
+			return err
+		}
+
+		if _, err := ch.QueueDeclare(DeadLetterQueue(queue), true, false, false, false, nil); err != nil {
+			return err
+		}
+
+		for _, delay := range retryDelays {
+			if _, err := ch.QueueDeclare(RetryQueue(queue, delay), true, false, false, false, amqp.Table{
+				"x-message-ttl":             delay.Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queue,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if _, err := ch.QueueDeclare(queue, true, false, false, false, amqp.Table{
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": DeadLetterQueue(queue),
+		}); err != nil {
+			return err
+		}
+
+		return ch.QueueBind(queue, routingKey, exchangeName, false, nil)
+	}
+}
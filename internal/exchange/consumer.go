@@ -1,45 +1,354 @@
 package exchange
 
-import "github.com/streadway/amqp"
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"event-service/internal/metrics"
+	"event-service/internal/tracing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
 
 const CasperEventName = "casper-events"
 
+const (
+	DefaultPrefetch    = 10
+	DefaultConcurrency = 1
+	DefaultMaxAttempts = 5
+	DefaultRetryDelay  = time.Second
+	DefaultMaxDelay    = 5 * time.Minute
+
+	// RetryCountHeader counts failed attempts of handling the message
+	RetryCountHeader = "x-retry-count"
+	// LastErrorHeader keeps the error of the last failed attempt
+	LastErrorHeader = "x-last-error"
+)
+
+// ErrPermanent marks failures that will not pass on retry, e.g. malformed messages, they go to the dead letter queue at once
+var ErrPermanent = errors.New("permanent failure")
+
+// Permanent wraps the handler error so that the message is not retried
+func Permanent(err error) error {
+	return errors.Join(ErrPermanent, err)
+}
+
+// RetryQueue holds failed messages for the delay set as its message TTL, then the broker moves them back to the queue.
+// Every delay has its own queue, because the broker expires only messages at the head of the queue
+func RetryQueue(queue string, delay time.Duration) string {
+	return queue + ".retry." + delay.String()
+}
+
+// DeadLetterQueue keeps messages that could not be handled
+func DeadLetterQueue(queue string) string {
+	return queue + ".dead"
+}
+
+// Handler handles the message body, the message is acknowledged only when it returns nil
+type Handler func(ctx context.Context, body []byte) error
+
 type Consumer interface {
-	Consume(func([]byte)) error
+	Consume(ctx context.Context, handler Handler) error
+}
+
+// Channel is the part of the amqp channel used by the consumer, retries are published on it in confirm mode
+type Channel interface {
+	Qos(prefetchCount, prefetchSize int, global bool) error
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Cancel(consumer string, noWait bool) error
+	Confirm(noWait bool) error
+	NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Close() error
+}
+
+// ChannelSource opens a new channel, e.g. on the current connection of the connection manager
+type ChannelSource func(ctx context.Context) (Channel, error)
+
+type ConsumerConfiguration func(*DefaultConsumer)
+
+// WithPrefetch limits number of unacknowledged messages delivered to the consumer
+func WithPrefetch(prefetch int) ConsumerConfiguration {
+	return func(c *DefaultConsumer) {
+		if prefetch > 0 {
+			c.prefetch = prefetch
+		}
+	}
+}
+
+// WithConcurrency sets number of messages handled at the same time
+func WithConcurrency(concurrency int) ConsumerConfiguration {
+	return func(c *DefaultConsumer) {
+		if concurrency > 0 {
+			c.concurrency = concurrency
+		}
+	}
+}
+
+// WithRetries sets number of attempts before the message is dead lettered and delay of the first retry,
+// the delay doubles with every next attempt up to the max delay
+func WithRetries(maxAttempts int, delay, maxDelay time.Duration) ConsumerConfiguration {
+	return func(c *DefaultConsumer) {
+		if maxAttempts > 0 {
+			c.maxAttempts = maxAttempts
+		}
+
+		if delay > 0 {
+			c.retryDelay = delay
+		}
+
+		if maxDelay > 0 {
+			c.maxDelay = maxDelay
+		}
+	}
 }
 
+// DefaultConsumer acknowledges messages after they are handled. Failed messages are republished to the retry queue
+// of the growing delay and messages failing permanently or too many times are rejected to the dead letter queue.
+// When the channel is closed, e.g. the broker restarts, the consumer subscribes again on a new channel
 type DefaultConsumer struct {
-	ch        *amqp.Channel
-	queueName string
+	channels    ChannelSource
+	queueName   string
+	tag         string
+	prefetch    int
+	concurrency int
+	maxAttempts int
+	retryDelay  time.Duration
+	maxDelay    time.Duration
+}
+
+func NewConsumer(queueName string, channels ChannelSource, configuration ...ConsumerConfiguration) *DefaultConsumer {
+	c := &DefaultConsumer{
+		channels:    channels,
+		queueName:   queueName,
+		tag:         queueName + "-consumer",
+		prefetch:    DefaultPrefetch,
+		concurrency: DefaultConcurrency,
+		maxAttempts: DefaultMaxAttempts,
+		retryDelay:  DefaultRetryDelay,
+		maxDelay:    DefaultMaxDelay,
+	}
+
+	for _, cfg := range configuration {
+		cfg(c)
+	}
+
+	return c
 }
 
-func NewConsumer(queueName string, ch *amqp.Channel) *DefaultConsumer {
-	return &DefaultConsumer{ch, queueName}
+// Consume handles messages until the context is done, messages being handled at that moment are finished
+func (c *DefaultConsumer) Consume(ctx context.Context, handler Handler) error {
+	for {
+		err := c.subscribe(ctx, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			log.WithError(err).WithField("queue", c.queueName).Warn("consumer subscription failed, subscribing again")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.retryDelay):
+		}
+	}
 }
 
-func (c *DefaultConsumer) Consume(handlerFunc func([]byte)) error {
-	message, err := c.ch.Consume(
-		c.queueName,
-		"",
-		true,
-		false,
-		false,
-		false,
-		nil,
-	)
+// subscribe handles messages delivered on a single channel until the channel is closed or the context is done
+func (c *DefaultConsumer) subscribe(ctx context.Context, handler Handler) error {
+	ch, err := c.channels(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer ch.Close()
+
+	if err := ch.Qos(c.prefetch, 0, false); err != nil {
+		return err
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		return err
+	}
+
+	retries := &retryPublisher{ch: ch, confirms: ch.NotifyPublish(make(chan amqp.Confirmation, 1))}
+
+	deliveries, err := ch.Consume(c.queueName, c.tag, false, false, false, false, nil)
 	if err != nil {
 		return err
 	}
 
-	forever := make(chan bool)
+	// in flight messages are finished even when the consumer is stopped
+	handlerCtx := context.WithoutCancel(ctx)
 
+	wg := sync.WaitGroup{}
+	for i := 0; i < c.concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for d := range deliveries {
+				c.handle(handlerCtx, retries, handler, d)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
 	go func() {
-		for d := range message {
-			handlerFunc(d.Body)
-		}
+		wg.Wait()
+		close(done)
 	}()
 
-	<-forever
+	select {
+	case <-done:
+		return amqp.ErrClosed
+	case <-ctx.Done():
+	}
+
+	cancelErr := ch.Cancel(c.tag, false)
+	<-done
+
+	return cancelErr
+}
+
+func (c *DefaultConsumer) handle(ctx context.Context, retries *retryPublisher, handler Handler, d amqp.Delivery) {
+	ctx, span := tracing.Start(tracing.Extract(ctx, d.Headers), "consume "+c.queueName, trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(
+		attribute.String("messaging.system", "rabbitmq"),
+		attribute.String("messaging.source.name", c.queueName),
+		attribute.String("messaging.message.id", d.MessageId),
+		attribute.Int("messaging.rabbitmq.retry_count", retryCount(d)),
+	))
+
+	handleErr := handler(ctx, d.Body)
+	tracing.End(span, &handleErr)
+	if handleErr == nil {
+		c.settle(d.Ack(false), metrics.ResultAcked)
+
+		return
+	}
+
+	attempts := retryCount(d) + 1
+	logger := log.WithError(handleErr).WithField("queue", c.queueName).WithField("attempt", attempts)
+
+	if errors.Is(handleErr, ErrPermanent) || attempts >= c.maxAttempts {
+		logger.Error("message is moved to the dead letter queue")
+		c.settle(d.Nack(false, false), metrics.ResultDeadLetter)
+
+		return
+	}
+
+	// the message is acknowledged only after the broker confirms its retry, otherwise the message could be lost
+	if err := retries.publish(RetryQueue(c.queueName, c.backoff(attempts)), retryPublishing(d, attempts, handleErr)); err != nil {
+		logger.WithField("retry_error", err).Error("cannot schedule retry, message is requeued")
+		c.settle(d.Nack(false, true), metrics.ResultRequeued)
+
+		return
+	}
+
+	logger.Warn("message handling failed, retry is scheduled")
+	c.settle(d.Ack(false), metrics.ResultRetried)
+}
+
+// retryPublisher publishes retries on the channel of the consumer and waits for their confirmation,
+// retries are published one at a time so that the confirmation belongs to the published message
+type retryPublisher struct {
+	mu       sync.Mutex
+	ch       Channel
+	confirms chan amqp.Confirmation
+}
+
+func (p *retryPublisher) publish(queue string, msg amqp.Publishing) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ch.Publish("", queue, false, false, msg); err != nil {
+		return err
+	}
+
+	// the confirmations are closed together with the channel
+	confirm, ok := <-p.confirms
+	if !ok {
+		return ErrPublishNotConfirmed
+	}
+
+	if !confirm.Ack {
+		return ErrPublishRejected
+	}
 
 	return nil
 }
+
+// settle counts the consumed message with its result and logs failed acknowledgement
+func (c *DefaultConsumer) settle(err error, result string) {
+	metrics.MessagesConsumed.WithLabelValues(c.queueName, result).Inc()
+
+	if err != nil {
+		log.WithError(err).WithField("queue", c.queueName).Error("cannot acknowledge message")
+	}
+}
+
+// RetryDelays returns the delays of all retries, the retry queue of each of them has to be declared with DeclareQueue
+func (c *DefaultConsumer) RetryDelays() []time.Duration {
+	delays := make([]time.Duration, 0)
+
+	for attempts := 1; attempts < c.maxAttempts; attempts++ {
+		if delay := c.backoff(attempts); !slices.Contains(delays, delay) {
+			delays = append(delays, delay)
+		}
+	}
+
+	return delays
+}
+
+// backoff returns delay of the retry after given number of failed attempts
+func (c *DefaultConsumer) backoff(attempts int) time.Duration {
+	delay := c.retryDelay
+	for i := 1; i < attempts && delay < c.maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, c.maxDelay)
+}
+
+func retryCount(d amqp.Delivery) int {
+	switch count := d.Headers[RetryCountHeader].(type) {
+	case int32:
+		return int(count)
+	case int64:
+		return int(count)
+	case int:
+		return count
+	default:
+		return 0
+	}
+}
+
+func retryPublishing(d amqp.Delivery, attempts int, err error) amqp.Publishing {
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+
+	headers[RetryCountHeader] = int32(attempts)
+	headers[LastErrorHeader] = err.Error()
+
+	return amqp.Publishing{
+		Headers:       headers,
+		ContentType:   d.ContentType,
+		DeliveryMode:  amqp.Persistent,
+		CorrelationId: d.CorrelationId,
+		MessageId:     d.MessageId,
+		Timestamp:     d.Timestamp,
+		Type:          d.Type,
+		Body:          d.Body,
+	}
+}
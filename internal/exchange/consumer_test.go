@@ -0,0 +1,272 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"event-service/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/streadway/amqp"
+)
+
+type acknowledgerMock struct {
+	mu      sync.Mutex
+	settled map[uint64]string
+}
+
+func (a *acknowledgerMock) settle(tag uint64, outcome string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.settled[tag] = outcome
+
+	return nil
+}
+
+func (a *acknowledgerMock) Ack(tag uint64, _ bool) error {
+	return a.settle(tag, "ack")
+}
+
+func (a *acknowledgerMock) Nack(tag uint64, _ bool, requeue bool) error {
+	if requeue {
+		return a.settle(tag, "requeue")
+	}
+
+	return a.settle(tag, "dead")
+}
+
+func (a *acknowledgerMock) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+type channelMock struct {
+	deliveries chan amqp.Delivery
+	published  []amqp.Publishing
+	publishErr error
+	nack       bool
+	confirms   chan amqp.Confirmation
+	mu         sync.Mutex
+}
+
+func (c *channelMock) Qos(int, int, bool) error {
+	return nil
+}
+
+func (c *channelMock) Consume(string, string, bool, bool, bool, bool, amqp.Table) (<-chan amqp.Delivery, error) {
+	return c.deliveries, nil
+}
+
+func (c *channelMock) Cancel(string, bool) error {
+	close(c.deliveries)
+
+	return nil
+}
+
+func (c *channelMock) Confirm(bool) error {
+	return nil
+}
+
+func (c *channelMock) NotifyPublish(confirms chan amqp.Confirmation) chan amqp.Confirmation {
+	c.confirms = confirms
+
+	return confirms
+}
+
+func (c *channelMock) Close() error {
+	return nil
+}
+
+func (c *channelMock) Publish(_, key string, _, _ bool, msg amqp.Publishing) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.publishErr != nil {
+		return c.publishErr
+	}
+
+	// rejected messages are not kept by the broker
+	if c.nack {
+		c.confirms <- amqp.Confirmation{Ack: false}
+
+		return nil
+	}
+
+	msg.Headers["routing-key"] = key
+	c.published = append(c.published, msg)
+	c.confirms <- amqp.Confirmation{DeliveryTag: uint64(len(c.published)), Ack: true}
+
+	return nil
+}
+
+func TestDefaultConsumer_Consume(t *testing.T) {
+	errTemporary := errors.New("smtp unavailable")
+
+	tests := []struct {
+		name          string
+		handlerErr    error
+		retryCount    int32
+		publishErr    error
+		nack          bool
+		wantOutcome   string
+		wantResult    string
+		wantRetry     bool
+		wantRetryWith amqp.Table
+	}{
+		{
+			name:        "handled message is acknowledged",
+			wantOutcome: "ack",
+			wantResult:  metrics.ResultAcked,
+		},
+		{
+			name:        "failed message is retried with delay",
+			handlerErr:  errTemporary,
+			retryCount:  2,
+			wantOutcome: "ack",
+			wantResult:  metrics.ResultRetried,
+			wantRetry:   true,
+			wantRetryWith: amqp.Table{
+				RetryCountHeader: int32(3),
+				LastErrorHeader:  errTemporary.Error(),
+				"routing-key":    "event-update.retry.4s",
+			},
+		},
+		{
+			name:        "message failing too many times is dead lettered",
+			handlerErr:  errTemporary,
+			retryCount:  4,
+			wantOutcome: "dead",
+			wantResult:  metrics.ResultDeadLetter,
+		},
+		{
+			name:        "permanent failure is dead lettered at once",
+			handlerErr:  Permanent(errors.New("malformed message")),
+			wantOutcome: "dead",
+			wantResult:  metrics.ResultDeadLetter,
+		},
+		{
+			name:        "message is requeued when retry cannot be scheduled",
+			handlerErr:  errTemporary,
+			publishErr:  errors.New("channel closed"),
+			wantOutcome: "requeue",
+			wantResult:  metrics.ResultRequeued,
+		},
+		{
+			name:        "message is requeued when broker rejects the retry",
+			handlerErr:  errTemporary,
+			nack:        true,
+			wantOutcome: "requeue",
+			wantResult:  metrics.ResultRequeued,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := &channelMock{deliveries: make(chan amqp.Delivery, 1), publishErr: tt.publishErr, nack: tt.nack}
+			ack := &acknowledgerMock{settled: map[uint64]string{}}
+			c := NewConsumer("event-update", sourceOf(ch), WithConcurrency(2), WithRetries(5, time.Second, time.Minute))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			handled := make(chan struct{})
+			consumed := testutil.ToFloat64(metrics.MessagesConsumed.WithLabelValues("event-update", tt.wantResult))
+
+			ch.deliveries <- amqp.Delivery{
+				Acknowledger: ack,
+				DeliveryTag:  1,
+				Headers:      amqp.Table{RetryCountHeader: tt.retryCount},
+				Body:         []byte("body"),
+			}
+
+			go func() {
+				<-handled
+				cancel()
+			}()
+
+			if err := c.Consume(ctx, func(ctx context.Context, body []byte) error {
+				defer close(handled)
+
+				return tt.handlerErr
+			}); err != nil {
+				t.Fatalf("Consume() error = %v", err)
+			}
+
+			if got := ack.settled[1]; got != tt.wantOutcome {
+				t.Errorf("Consume() outcome = %v, want %v", got, tt.wantOutcome)
+			}
+
+			if got := testutil.ToFloat64(metrics.MessagesConsumed.WithLabelValues("event-update", tt.wantResult)); got != consumed+1 {
+				t.Errorf("Consume() did not count %s message", tt.wantResult)
+			}
+
+			if (len(ch.published) == 1) != tt.wantRetry {
+				t.Fatalf("Consume() published retries = %v, want retry %v", ch.published, tt.wantRetry)
+			}
+
+			if tt.wantRetry {
+				// the delay is the message TTL of the retry queue, the broker expires messages only at the head of the queue
+				if !reflect.DeepEqual(ch.published[0].Headers, tt.wantRetryWith) || ch.published[0].Expiration != "" {
+					t.Errorf("Consume() retry headers = %v, expiration = %v", ch.published[0].Headers, ch.published[0].Expiration)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultConsumer_RetryDelays(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxDelay time.Duration
+		want     []time.Duration
+	}{
+		{name: "every retry doubles the delay", maxDelay: time.Minute, want: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}},
+		{name: "retries over the max delay share its queue", maxDelay: 3 * time.Second, want: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConsumer("event-update", nil, WithRetries(5, time.Second, tt.maxDelay))
+
+			if got := c.RetryDelays(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RetryDelays() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultConsumer_ConsumeSubscribesAgainWhenChannelIsClosed(t *testing.T) {
+	closed := &channelMock{deliveries: make(chan amqp.Delivery)}
+	close(closed.deliveries)
+
+	reopened := &channelMock{deliveries: make(chan amqp.Delivery, 1)}
+	ack := &acknowledgerMock{settled: map[uint64]string{}}
+	reopened.deliveries <- amqp.Delivery{Acknowledger: ack, DeliveryTag: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := NewConsumer("event-update", sourceOf(closed, reopened), WithRetries(5, time.Millisecond, time.Millisecond))
+
+	if err := c.Consume(ctx, func(context.Context, []byte) error {
+		cancel()
+
+		return nil
+	}); err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+
+	if ack.settled[1] != "ack" {
+		t.Errorf("Consume() message on the reopened channel was not handled")
+	}
+}
+
+// sourceOf returns the channels in order, the last one is returned when the others are used
+func sourceOf(channels ...*channelMock) ChannelSource {
+	return func(context.Context) (Channel, error) {
+		ch := channels[0]
+		if len(channels) > 1 {
+			channels = channels[1:]
+		}
+
+		return ch, nil
+	}
+}
@@ -3,32 +3,15 @@ package eventupdate
 import (
 	"event-service/internal/domain/event/aggregate"
 	"event-service/internal/exchange"
-
-	"github.com/streadway/amqp"
 )
 
 const ExchangeKey = "event-update"
 const QueueName = "event-update"
 
-type Producer struct {
-	ch *amqp.Channel
-}
-
-func NewProducer(ch *amqp.Channel) *Producer {
-	return &Producer{ch}
-}
-
-func (p *Producer) Publish(body []byte) error {
-	return p.ch.Publish(
-		exchange.CasperEventName,
-		ExchangeKey,
-		false,
-		false,
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		},
-	)
+// Route publishes event updates on the casper-events exchange
+var Route = exchange.Route{
+	Exchange:   exchange.CasperEventName,
+	RoutingKey: ExchangeKey,
 }
 
 type Message struct {
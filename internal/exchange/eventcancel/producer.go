@@ -0,0 +1,23 @@
+package eventcancel
+
+import (
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/exchange"
+)
+
+const ExchangeKey = "event-cancelled"
+const QueueName = "event-cancelled"
+const MessageType = "EventCancelled"
+
+// Route publishes event cancellations on the casper-events exchange
+var Route = exchange.Route{
+	Exchange:   exchange.CasperEventName,
+	RoutingKey: ExchangeKey,
+	Type:       MessageType,
+}
+
+type Message struct {
+	Type   string
+	Event  *aggregate.Event
+	Reason string
+}
@@ -0,0 +1,21 @@
+package eventdelete
+
+import (
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/exchange"
+)
+
+const ExchangeKey = "event-deleted"
+const MessageType = "EventDeleted"
+
+// Route publishes deletions of the events on the casper-events exchange
+var Route = exchange.Route{
+	Exchange:   exchange.CasperEventName,
+	RoutingKey: ExchangeKey,
+	Type:       MessageType,
+}
+
+type Message struct {
+	Type  string
+	Event *aggregate.Event
+}
@@ -0,0 +1,108 @@
+package eventinvitation
+
+import (
+	"fmt"
+	"time"
+
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/exchange"
+
+	"github.com/google/uuid"
+)
+
+// SchemaVersion is bumped on every incompatible change of the Message, consumers should reject versions they do not know
+const SchemaVersion = 1
+
+// MessageType is a lifecycle transition of the invitation, it is used as the routing key on the casper-events exchange
+type MessageType string
+
+const (
+	UserInvited  MessageType = "invitation.invited"
+	UserAccepted MessageType = "invitation.accepted"
+	UserJoined   MessageType = "invitation.joined"
+	UserRemoved  MessageType = "invitation.removed"
+	// UserWaitlisted is published when the user joins or accepts invitation of the full event
+	UserWaitlisted MessageType = "invitation.waitlisted"
+	// UserPromoted is published when the waitlisted user takes the freed spot
+	UserPromoted MessageType = "invitation.promoted"
+	UserDeclined MessageType = "invitation.declined"
+	// UserRevoked is published when the organizer withdraws the pending invitation
+	UserRevoked MessageType = "invitation.revoked"
+	// UserLeft is published when the participant or waitlisted user leaves the event
+	UserLeft MessageType = "invitation.left"
+)
+
+// MessageTypes lists all invitation transitions
+var MessageTypes = []MessageType{
+	UserInvited, UserAccepted, UserJoined, UserRemoved, UserWaitlisted, UserPromoted, UserDeclined, UserRevoked, UserLeft,
+}
+
+// RoutingPattern binds a queue to all invitation messages
+const RoutingPattern = "invitation.*"
+
+const QueueName = "event-invitation"
+
+// RouteOf publishes messages of the type with its routing key, schema version is passed in the message headers
+func RouteOf(messageType MessageType) exchange.Route {
+	return exchange.Route{
+		Exchange:   exchange.CasperEventName,
+		RoutingKey: string(messageType),
+		Type:       string(messageType),
+		Headers:    map[string]any{"schema-version": int32(SchemaVersion)},
+	}
+}
+
+// Message describes the invitation after the transition, Schema identifies type and version of the message, e.g. invitation.invited.v1
+type Message struct {
+	Schema      string      `json:"schema"`
+	Type        MessageType `json:"type"`
+	Version     int         `json:"version"`
+	OccurredAt  time.Time   `json:"occurredAt"`
+	EventID     uuid.UUID   `json:"eventId"`
+	EventName   string      `json:"eventName"`
+	OrganizerID uuid.UUID   `json:"organizerId"`
+	UserID      uuid.UUID   `json:"userId"`
+	PerformedBy *uuid.UUID  `json:"performedBy,omitempty"`
+	AcceptedAt  *time.Time  `json:"acceptedAt,omitempty"`
+	Waitlisted  bool        `json:"waitlisted"`
+	// the details of the event let consumers, e.g. the email service, describe it without querying the api
+	EventDescription string           `json:"eventDescription,omitempty"`
+	EventStart       time.Time        `json:"eventStart"`
+	EventEnd         time.Time        `json:"eventEnd"`
+	EventLocation    *MessageLocation `json:"eventLocation,omitempty"`
+}
+
+type MessageLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// NewMessage describes the invitation in the current schema version
+func NewMessage(messageType MessageType, ia aggregate.Invitation, performedBy uuid.UUID, occurredAt time.Time) Message {
+	m := Message{
+		Schema:      fmt.Sprintf("%s.v%d", messageType, SchemaVersion),
+		Type:        messageType,
+		Version:     SchemaVersion,
+		OccurredAt:  occurredAt.UTC(),
+		EventID:     ia.Event.Event.ExternalID,
+		EventName:   ia.Event.Event.Name,
+		OrganizerID: ia.Event.UserID,
+		UserID:      ia.InvitedUser,
+		AcceptedAt:  ia.AcceptedAt,
+		Waitlisted:  ia.IsWaitlisted(),
+
+		EventDescription: ia.Event.Event.Description,
+		EventStart:       ia.Event.EventPeriod.Start().UTC(),
+		EventEnd:         ia.Event.EventPeriod.End().UTC(),
+	}
+
+	if ia.Event.Location != nil {
+		m.EventLocation = &MessageLocation{Latitude: ia.Event.Location.Spot.Lat(), Longitude: ia.Event.Location.Spot.Long()}
+	}
+
+	if performedBy != uuid.Nil {
+		m.PerformedBy = &performedBy
+	}
+
+	return m
+}
@@ -0,0 +1,69 @@
+package eventinvitation
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event/aggregate"
+
+	"github.com/google/uuid"
+)
+
+func TestNewMessage(t *testing.T) {
+	organizer := uuid.MustParse("6a1c7b8e-2f1d-4c1a-9d1e-0c3b2a1f0e9d")
+	user := uuid.MustParse("c5a1f0e9-7b6a-4d3c-8e2f-1a0b9c8d7e6f")
+	at := time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC)
+
+	period, _ := valueobject.EventPeriod{}.WithStartAndDuration(at.Add(24*time.Hour), 2*time.Hour)
+
+	ia := aggregate.Invitation{
+		Event: &aggregate.Event{
+			UserID:      organizer,
+			Event:       &entity.Event{ExternalID: uuid.MustParse("0b9e4a52-3c55-4d6f-8a7b-1c2d3e4f5a6b"), Name: "meetup"},
+			EventPeriod: period,
+			Location:    &aggregate.Location{Spot: valueobject.NewLocation(48.8584, 2.2945)},
+		},
+		InvitedUser: user,
+		AcceptedAt:  &at,
+	}
+
+	tests := []struct {
+		name        string
+		messageType MessageType
+		performedBy uuid.UUID
+		want        string
+	}{
+		{
+			name:        "accepted by the user",
+			messageType: UserAccepted,
+			performedBy: user,
+			want: `{"schema":"invitation.accepted.v1","type":"invitation.accepted","version":1,"occurredAt":"2030-01-01T18:00:00Z",` +
+				`"eventId":"0b9e4a52-3c55-4d6f-8a7b-1c2d3e4f5a6b","eventName":"meetup","organizerId":"6a1c7b8e-2f1d-4c1a-9d1e-0c3b2a1f0e9d",` +
+				`"userId":"c5a1f0e9-7b6a-4d3c-8e2f-1a0b9c8d7e6f","performedBy":"c5a1f0e9-7b6a-4d3c-8e2f-1a0b9c8d7e6f","acceptedAt":"2030-01-01T18:00:00Z","waitlisted":false,` +
+				`"eventStart":"2030-01-02T18:00:00Z","eventEnd":"2030-01-02T20:00:00Z","eventLocation":{"latitude":48.8584,"longitude":2.2945}}`,
+		},
+		{
+			name:        "removed without known actor",
+			messageType: UserRemoved,
+			want: `{"schema":"invitation.removed.v1","type":"invitation.removed","version":1,"occurredAt":"2030-01-01T18:00:00Z",` +
+				`"eventId":"0b9e4a52-3c55-4d6f-8a7b-1c2d3e4f5a6b","eventName":"meetup","organizerId":"6a1c7b8e-2f1d-4c1a-9d1e-0c3b2a1f0e9d",` +
+				`"userId":"c5a1f0e9-7b6a-4d3c-8e2f-1a0b9c8d7e6f","acceptedAt":"2030-01-01T18:00:00Z","waitlisted":false,` +
+				`"eventStart":"2030-01-02T18:00:00Z","eventEnd":"2030-01-02T20:00:00Z","eventLocation":{"latitude":48.8584,"longitude":2.2945}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(NewMessage(tt.messageType, ia, tt.performedBy, at))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("NewMessage() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
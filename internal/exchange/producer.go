@@ -1,5 +1,8 @@
 package exchange
 
+import "context"
+
+// Producer publishes the message body, the context carries the transaction the message is written in
 type Producer interface {
-	Publish(body []byte) error
+	Publish(ctx context.Context, body []byte) error
 }
@@ -0,0 +1,117 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/streadway/amqp"
+)
+
+var (
+	ErrPublishNotConfirmed = errors.New("broker did not confirm the message")
+	ErrPublishRejected     = errors.New("broker rejected the message")
+)
+
+// Publisher publishes messages on the exchange
+type Publisher interface {
+	Publish(ctx context.Context, exchangeName, key string, msg amqp.Publishing) error
+}
+
+// ConfirmChannel is the part of the amqp channel used by the confirming publisher
+type ConfirmChannel interface {
+	Confirm(noWait bool) error
+	NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Close() error
+}
+
+// ConfirmChannelSource opens a new channel, e.g. on the current connection of the connection manager
+type ConfirmChannelSource func(ctx context.Context) (ConfirmChannel, error)
+
+// ConfirmPublisher publishes on a channel in confirm mode and returns only after the broker confirms the message,
+// the channel is opened again after it fails
+type ConfirmPublisher struct {
+	channels ConfirmChannelSource
+	mu       sync.Mutex
+	ch       ConfirmChannel
+	confirms chan amqp.Confirmation
+}
+
+func NewConfirmPublisher(channels ConfirmChannelSource) *ConfirmPublisher {
+	return &ConfirmPublisher{channels: channels}
+}
+
+func (p *ConfirmPublisher) Publish(ctx context.Context, exchangeName, key string, msg amqp.Publishing) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.open(ctx); err != nil {
+		return err
+	}
+
+	if err := p.ch.Publish(exchangeName, key, false, false, msg); err != nil {
+		p.reset()
+
+		return err
+	}
+
+	select {
+	case confirm, ok := <-p.confirms:
+		if !ok {
+			p.reset()
+
+			return ErrPublishNotConfirmed
+		}
+
+		if !confirm.Ack {
+			return ErrPublishRejected
+		}
+
+		return nil
+	case <-ctx.Done():
+		// confirmation of this message would be taken for the next one
+		p.reset()
+
+		return errors.Join(ErrPublishNotConfirmed, ctx.Err())
+	}
+}
+
+func (p *ConfirmPublisher) open(ctx context.Context) error {
+	if p.ch != nil {
+		return nil
+	}
+
+	ch, err := p.channels(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		_ = ch.Close()
+
+		return err
+	}
+
+	p.ch, p.confirms = ch, ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+
+	return nil
+}
+
+func (p *ConfirmPublisher) reset() {
+	if p.ch != nil {
+		_ = p.ch.Close()
+	}
+
+	p.ch, p.confirms = nil, nil
+}
+
+// Close closes the channel of the publisher
+func (p *ConfirmPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reset()
+
+	return nil
+}
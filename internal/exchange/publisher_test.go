@@ -0,0 +1,104 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/streadway/amqp"
+)
+
+type confirmChannelMock struct {
+	confirm    *amqp.Confirmation // nil closes the confirmations channel
+	publishErr error
+	confirms   chan amqp.Confirmation
+	closed     bool
+}
+
+func (c *confirmChannelMock) Confirm(bool) error {
+	return nil
+}
+
+func (c *confirmChannelMock) NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation {
+	c.confirms = confirm
+
+	return confirm
+}
+
+func (c *confirmChannelMock) Publish(string, string, bool, bool, amqp.Publishing) error {
+	if c.publishErr != nil {
+		return c.publishErr
+	}
+
+	if c.confirm == nil {
+		close(c.confirms)
+	} else {
+		c.confirms <- *c.confirm
+	}
+
+	return nil
+}
+
+func (c *confirmChannelMock) Close() error {
+	c.closed = true
+
+	return nil
+}
+
+func TestConfirmPublisher_Publish(t *testing.T) {
+	errChannelClosed := errors.New("channel closed")
+
+	tests := []struct {
+		name       string
+		channel    *confirmChannelMock
+		wantErr    error
+		wantReopen bool
+	}{
+		{
+			name:    "confirmed message",
+			channel: &confirmChannelMock{confirm: &amqp.Confirmation{DeliveryTag: 1, Ack: true}},
+		},
+		{
+			name:    "rejected message",
+			channel: &confirmChannelMock{confirm: &amqp.Confirmation{DeliveryTag: 1, Ack: false}},
+			wantErr: ErrPublishRejected,
+		},
+		{
+			name:       "channel closed before confirmation",
+			channel:    &confirmChannelMock{},
+			wantErr:    ErrPublishNotConfirmed,
+			wantReopen: true,
+		},
+		{
+			name:       "publishing on closed channel",
+			channel:    &confirmChannelMock{publishErr: errChannelClosed},
+			wantErr:    errChannelClosed,
+			wantReopen: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opened := 0
+			p := NewConfirmPublisher(func(context.Context) (ConfirmChannel, error) {
+				opened++
+
+				return tt.channel, nil
+			})
+
+			if err := p.Publish(context.Background(), CasperEventName, "event-update", amqp.Publishing{}); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.channel.closed != tt.wantReopen {
+				t.Errorf("Publish() channel closed = %v, want %v", tt.channel.closed, tt.wantReopen)
+			}
+
+			tt.channel.publishErr = errChannelClosed
+			_ = p.Publish(context.Background(), CasperEventName, "event-update", amqp.Publishing{})
+
+			if wantOpened := map[bool]int{true: 2, false: 1}[tt.wantReopen]; opened != wantOpened {
+				t.Errorf("Publish() opened %d channels, want %d", opened, wantOpened)
+			}
+		})
+	}
+}
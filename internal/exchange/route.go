@@ -0,0 +1,9 @@
+package exchange
+
+// Route tells where and as what type the message is published
+type Route struct {
+	Exchange   string
+	RoutingKey string
+	Type       string
+	Headers    map[string]any
+}
@@ -0,0 +1,126 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	DefaultTimeout = 2 * time.Second
+)
+
+// Check verifies a single dependency, it returns an error when the dependency cannot be used
+type Check func(ctx context.Context) error
+
+type CheckerConfiguration func(c *Checker)
+
+// WithCheck registers the named dependency check
+func WithCheck(name string, check Check) CheckerConfiguration {
+	return func(c *Checker) {
+		c.checks[name] = check
+	}
+}
+
+// WithTimeout limits how long every check may take
+func WithTimeout(timeout time.Duration) CheckerConfiguration {
+	return func(c *Checker) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+// Checker runs dependency checks concurrently and reports their status with latency
+type Checker struct {
+	checks  map[string]Check
+	timeout time.Duration
+}
+
+func NewChecker(configuration ...CheckerConfiguration) *Checker {
+	c := &Checker{checks: map[string]Check{}, timeout: DefaultTimeout}
+
+	for _, cfg := range configuration {
+		cfg(c)
+	}
+
+	return c
+}
+
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+func (r Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+// Run checks all dependencies, the report is down when any of them is down
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(c.checks))}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for name, check := range c.checks {
+		wg.Add(1)
+
+		go func(name string, check Check) {
+			defer wg.Done()
+
+			result := c.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[name] = result
+			if result.Status == StatusDown {
+				report.Status = StatusDown
+			}
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	started := time.Now()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- check(ctx)
+	}()
+
+	var err error
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := Result{Status: StatusUp, LatencyMs: float64(time.Since(started).Microseconds()) / 1000}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChecker_Run(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		checks     map[string]Check
+		wantStatus string
+		wantChecks map[string]string
+	}{
+		{
+			name:       "without checks",
+			wantStatus: StatusUp,
+			wantChecks: map[string]string{},
+		},
+		{
+			name:       "all dependencies up",
+			checks:     map[string]Check{"mysql": up, "amqp": up},
+			wantStatus: StatusUp,
+			wantChecks: map[string]string{"mysql": StatusUp, "amqp": StatusUp},
+		},
+		{
+			name:       "single dependency down",
+			checks:     map[string]Check{"mysql": up, "amqp": down},
+			wantStatus: StatusDown,
+			wantChecks: map[string]string{"mysql": StatusUp, "amqp": StatusDown},
+		},
+		{
+			name:       "check exceeding timeout",
+			checks:     map[string]Check{"mysql": hanging},
+			wantStatus: StatusDown,
+			wantChecks: map[string]string{"mysql": StatusDown},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configuration := []CheckerConfiguration{WithTimeout(10 * time.Millisecond)}
+			for name, check := range tt.checks {
+				configuration = append(configuration, WithCheck(name, check))
+			}
+
+			report := NewChecker(configuration...).Run(context.Background())
+
+			if report.Status != tt.wantStatus {
+				t.Errorf("Run() status = %s, want %s", report.Status, tt.wantStatus)
+			}
+
+			if len(report.Checks) != len(tt.wantChecks) {
+				t.Fatalf("Run() checks = %v, want %v", report.Checks, tt.wantChecks)
+			}
+
+			for name, status := range tt.wantChecks {
+				if report.Checks[name].Status != status {
+					t.Errorf("Run() check %s = %+v, want %s", name, report.Checks[name], status)
+				}
+
+				if status == StatusDown && report.Checks[name].Error == "" {
+					t.Errorf("Run() check %s is down without error", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"event-service/internal/auth"
+	"event-service/internal/calendar"
+	"event-service/internal/domain/event"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
+	"event-service/internal/domain/event/valueobject"
+	"event-service/internal/services/eventfinder"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// CalendarHandler renders events in the iCalendar format so that they can be imported or subscribed to
+type CalendarHandler struct {
+	finder eventfinder.ListHandler
+}
+
+func NewCalendarHandler(finder eventfinder.ListHandler) *CalendarHandler {
+	return &CalendarHandler{finder: finder}
+}
+
+// Event renders a single event, private events are available only to the organizer and participants
+func (h CalendarHandler) Event(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		http.Error(w, "event not found", http.StatusNotFound)
+
+		return
+	}
+
+	e, err := h.finder.GetByID(r.Context(), id)
+	if isNotFound(err) {
+		http.Error(w, "event not found", http.StatusNotFound)
+
+		return
+	} else if err != nil {
+		internalError(w, err)
+
+		return
+	}
+
+	actor, _ := auth.ActorFromContext(r.Context())
+	if err := policy.CanView(e, actor); err != nil {
+		forbidden(w, actor)
+
+		return
+	}
+
+	write(w, calendar.Calendar{Name: e.Event.Name, Events: []*aggregate.Event{e}, Stamp: time.Now()})
+}
+
+// UserCalendar renders feed of the events accepted by the user, the feed is available only to the user
+func (h CalendarHandler) UserCalendar(w http.ResponseWriter, r *http.Request) {
+	user, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "user not found", http.StatusNotFound)
+
+		return
+	}
+
+	actor, _ := auth.ActorFromContext(r.Context())
+	if err := policy.CanActAs(user, actor); err != nil {
+		forbidden(w, actor)
+
+		return
+	}
+
+	events, err := h.participatedEvents(r, user)
+	if err != nil {
+		internalError(w, err)
+
+		return
+	}
+
+	write(w, calendar.Calendar{Name: "Events", Events: events, Stamp: time.Now()})
+}
+
+// participatedEvents fetches all pages of the events the user takes part in
+func (h CalendarHandler) participatedEvents(r *http.Request, user uuid.UUID) ([]*aggregate.Event, error) {
+	var events []*aggregate.Event
+
+	size := valueobject.MaxPageSize
+	request := eventfinder.Request{
+		Participant: user.String(),
+		First:       &size,
+		OrderBy:     &eventfinder.OrderRequest{Field: valueobject.OrderByStartDate, Direction: valueobject.OrderAscending},
+	}
+
+	for {
+		page, err := h.finder.List(r.Context(), request)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, page.Events()...)
+		if !page.HasNextPage || len(page.Edges) == 0 {
+			return events, nil
+		}
+
+		request.After = page.Edges[len(page.Edges)-1].Cursor.Encode()
+	}
+}
+
+func write(w http.ResponseWriter, c calendar.Calendar) {
+	w.Header().Set("Content-Type", calendar.ContentType)
+
+	if _, err := c.WriteTo(w); err != nil {
+		log.WithError(err).Error("cannot write calendar")
+	}
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, event.ErrNotFound)
+}
+
+func forbidden(w http.ResponseWriter, actor uuid.UUID) {
+	if actor == uuid.Nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, auth.ErrUnauthenticated.Error(), http.StatusUnauthorized)
+
+		return
+	}
+
+	http.Error(w, policy.ErrForbidden.Error(), http.StatusForbidden)
+}
+
+func internalError(w http.ResponseWriter, err error) {
+	log.WithError(err).Error("cannot render calendar")
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"event-service/internal/health"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const StatusShuttingDown = "shutting_down"
+
+// LivenessHandler responds while the process serves requests, it does not check dependencies
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeReport(w, health.Report{Status: health.StatusUp})
+	}
+}
+
+// HealthHandler responds with status of every dependency, it responds with 503 when any of them is down
+func HealthHandler(checker *health.Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, checker.Run(r.Context()))
+	}
+}
+
+// ReadinessHandler responds with 503 when the api is shutting down or any of the dependencies is down
+func ReadinessHandler(readiness *Readiness, checker *health.Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !readiness.Ready() {
+			writeReport(w, health.Report{Status: StatusShuttingDown})
+
+			return
+		}
+
+		writeReport(w, checker.Run(r.Context()))
+	}
+}
+
+func writeReport(w http.ResponseWriter, report health.Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if !report.Up() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.WithError(err).Error("cannot write health report")
+	}
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"event-service/internal/health"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	up := health.WithCheck("mysql", func(context.Context) error { return nil })
+	down := health.WithCheck("amqp", func(context.Context) error { return errors.New("not connected") })
+
+	tests := []struct {
+		name       string
+		ready      bool
+		checker    *health.Checker
+		wantCode   int
+		wantStatus string
+	}{
+		{
+			name:       "dependencies up",
+			ready:      true,
+			checker:    health.NewChecker(up),
+			wantCode:   http.StatusOK,
+			wantStatus: health.StatusUp,
+		},
+		{
+			name:       "dependency down",
+			ready:      true,
+			checker:    health.NewChecker(up, down),
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: health.StatusDown,
+		},
+		{
+			name:       "shutting down",
+			ready:      false,
+			checker:    health.NewChecker(up),
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: StatusShuttingDown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readiness := &Readiness{}
+			readiness.SetReady(tt.ready)
+
+			w := httptest.NewRecorder()
+			ReadinessHandler(readiness, tt.checker)(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("ReadinessHandler() code = %d, want %d", w.Code, tt.wantCode)
+			}
+
+			var report health.Report
+			if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+				t.Fatalf("ReadinessHandler() body is not a report: %v", err)
+			}
+
+			if report.Status != tt.wantStatus {
+				t.Errorf("ReadinessHandler() status = %s, want %s", report.Status, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -1,10 +1,19 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
 
+	"event-service/internal/auth"
+	"event-service/internal/calendar"
 	internalgorm "event-service/internal/database/gorm"
 
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 	"gorm.io/gorm"
 )
 
@@ -16,3 +25,120 @@ func GORMConnectionMiddleware(db *gorm.DB) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// TracingMiddleware continues the trace of the caller passed in the W3C traceparent header
+func TracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// AuthenticationMiddleware puts identity from the bearer token into the request context,
+// requests without token are passed as anonymous and services decide if the identity is required
+func AuthenticationMiddleware(verifier auth.TokenVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			token, found := strings.CutPrefix(header, "Bearer ")
+			if !found {
+				unauthorized(w, "unsupported authorization scheme")
+
+				return
+			}
+
+			identity, err := verifier.Verify(token)
+			if err != nil {
+				unauthorized(w, "invalid token")
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.ContextWithIdentity(r.Context(), identity)))
+		})
+	}
+}
+
+// CalendarVerifier verifies tokens issued for the calendar at the path
+type CalendarVerifier interface {
+	Verify(token, path string) (auth.Identity, error)
+}
+
+// CalendarTokenMiddleware puts identity from the calendar token of the access_token query parameter into the request
+// context, it is used only on the calendar routes and the bearer token of the header takes precedence over it
+func CalendarTokenMiddleware(verifier CalendarVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.URL.Query().Get(calendar.AccessTokenParameter)
+			if _, authenticated := auth.IdentityFromContext(r.Context()); authenticated || token == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			identity, err := verifier.Verify(token, r.URL.Path)
+			if err != nil {
+				unauthorized(w, "invalid token")
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.ContextWithIdentity(r.Context(), identity)))
+		})
+	}
+}
+
+// RedactingLogFormatter logs requests with the tokens of the query replaced, so that they do not end up in the access logs
+type RedactingLogFormatter struct {
+	Formatter middleware.LogFormatter
+}
+
+func (f RedactingLogFormatter) NewLogEntry(r *http.Request) middleware.LogEntry {
+	query := r.URL.Query()
+	if !query.Has(calendar.AccessTokenParameter) {
+		return f.Formatter.NewLogEntry(r)
+	}
+
+	query.Set(calendar.AccessTokenParameter, "REDACTED")
+
+	redacted := r.Clone(r.Context())
+	redacted.URL.RawQuery = query.Encode()
+	redacted.RequestURI = redacted.URL.RequestURI()
+
+	return f.Formatter.NewLogEntry(redacted)
+}
+
+// WebsocketInitFunc puts identity from the bearer token of the connection init payload into the context of
+// the subscriptions, browsers cannot set headers on the websocket upgrade. Connections without the token keep
+// identity of the upgrade request
+func WebsocketInitFunc(verifier auth.TokenVerifier) transport.WebsocketInitFunc {
+	return func(ctx context.Context, initPayload transport.InitPayload) (context.Context, error) {
+		authorization := initPayload.Authorization()
+		if authorization == "" {
+			return ctx, nil
+		}
+
+		token, found := strings.CutPrefix(authorization, "Bearer ")
+		if !found {
+			return nil, errors.New("unsupported authorization scheme")
+		}
+
+		identity, err := verifier.Verify(token)
+		if err != nil {
+			return nil, auth.ErrInvalidToken
+		}
+
+		return auth.ContextWithIdentity(ctx, identity), nil
+	}
+}
+
+func unauthorized(w http.ResponseWriter, reason string) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	http.Error(w, reason, http.StatusUnauthorized)
+}
@@ -0,0 +1,195 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"event-service/internal/auth"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+type verifierMock map[string]auth.Identity
+
+func (v verifierMock) Verify(token string) (auth.Identity, error) {
+	identity, ok := v[token]
+	if !ok {
+		return auth.Identity{}, auth.ErrInvalidToken
+	}
+
+	return identity, nil
+}
+
+func TestWebsocketInitFunc(t *testing.T) {
+	user, upgradeUser := uuid.New(), uuid.New()
+	init := WebsocketInitFunc(verifierMock{"token": {UserID: user}})
+
+	tests := []struct {
+		name    string
+		payload transport.InitPayload
+		want    uuid.UUID
+		wantErr bool
+	}{
+		{
+			name:    "bearer token",
+			payload: transport.InitPayload{"authorization": "Bearer token"},
+			want:    user,
+		},
+		{
+			name:    "no token keeps identity of the upgrade request",
+			payload: transport.InitPayload{},
+			want:    upgradeUser,
+		},
+		{
+			name:    "invalid token",
+			payload: transport.InitPayload{"Authorization": "Bearer forged"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported scheme",
+			payload: transport.InitPayload{"Authorization": "Basic token"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := auth.ContextWithIdentity(context.Background(), auth.Identity{UserID: upgradeUser})
+
+			got, err := init(ctx, tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WebsocketInitFunc() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if actor, _ := auth.ActorFromContext(got); actor != tt.want {
+				t.Errorf("WebsocketInitFunc() actor = %v, want %v", actor, tt.want)
+			}
+		})
+	}
+}
+
+type calendarVerifierMock map[string]string
+
+func (v calendarVerifierMock) Verify(token, path string) (auth.Identity, error) {
+	if v[token] != path {
+		return auth.Identity{}, auth.ErrInvalidToken
+	}
+
+	return auth.Identity{UserID: uuid.MustParse(strings.Split(path, "/")[2])}, nil
+}
+
+func TestAuthenticationMiddleware_IgnoresQueryToken(t *testing.T) {
+	user := uuid.New()
+
+	var actor uuid.UUID
+	handler := AuthenticationMiddleware(verifierMock{"token": {UserID: user}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor, _ = auth.ActorFromContext(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/query?access_token=token", nil))
+
+	if rec.Code != http.StatusOK || actor != uuid.Nil {
+		t.Errorf("AuthenticationMiddleware() status = %v, actor = %v, want anonymous request", rec.Code, actor)
+	}
+}
+
+func TestCalendarTokenMiddleware(t *testing.T) {
+	user := uuid.New()
+	path := "/users/" + user.String() + "/calendar.ics"
+	verifier := calendarVerifierMock{"calendar-token": path}
+
+	tests := []struct {
+		name       string
+		target     string
+		identity   *auth.Identity
+		wantStatus int
+		want       uuid.UUID
+	}{
+		{
+			name:       "token of the calendar",
+			target:     path + "?access_token=calendar-token",
+			wantStatus: http.StatusOK,
+			want:       user,
+		},
+		{
+			name:       "token of another calendar",
+			target:     "/users/" + uuid.NewString() + "/calendar.ics?access_token=calendar-token",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "bearer token of the header takes precedence",
+			target:     path + "?access_token=forged",
+			identity:   &auth.Identity{UserID: user},
+			wantStatus: http.StatusOK,
+			want:       user,
+		},
+		{
+			name:       "anonymous request",
+			target:     path,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actor uuid.UUID
+			handler := CalendarTokenMiddleware(verifier)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actor, _ = auth.ActorFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.identity != nil {
+				req = req.WithContext(auth.ContextWithIdentity(req.Context(), *tt.identity))
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus || actor != tt.want {
+				t.Errorf("CalendarTokenMiddleware() status = %v, actor = %v, want %v, %v", rec.Code, actor, tt.wantStatus, tt.want)
+			}
+		})
+	}
+}
+
+type logFormatterMock struct {
+	uris []string
+}
+
+func (f *logFormatterMock) NewLogEntry(r *http.Request) middleware.LogEntry {
+	f.uris = append(f.uris, r.RequestURI)
+
+	return f
+}
+
+func (f *logFormatterMock) Write(int, int, http.Header, time.Duration, interface{}) {}
+func (f *logFormatterMock) Panic(interface{}, []byte)                               {}
+
+func TestRedactingLogFormatter(t *testing.T) {
+	formatter := &logFormatterMock{}
+
+	var token string
+	handler := middleware.RequestLogger(RedactingLogFormatter{Formatter: formatter})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.URL.Query().Get("access_token")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/events/1.ics?access_token=secret&tz=UTC", nil))
+
+	if len(formatter.uris) != 1 || strings.Contains(formatter.uris[0], "secret") {
+		t.Errorf("RedactingLogFormatter() logged %v, want token redacted", formatter.uris)
+	}
+
+	if token != "secret" {
+		t.Errorf("RedactingLogFormatter() passed token %q to the handler, want the original one", token)
+	}
+}
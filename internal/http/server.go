@@ -0,0 +1,164 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultDrainTimeout   = 15 * time.Second
+	DefaultReadinessDelay = 0
+)
+
+// Readiness tells load balancers if the api accepts new requests, it flips to not ready before the server drains
+type Readiness struct {
+	ready atomic.Bool
+}
+
+func (r *Readiness) Ready() bool {
+	return r.ready.Load()
+}
+
+func (r *Readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// Closer releases a resource during shutdown, closers run in the order they were registered
+type Closer struct {
+	Name  string
+	Close func(ctx context.Context) error
+}
+
+type ServerConfiguration func(s *Server)
+
+// WithDrainTimeout limits how long in-flight requests are awaited before the server is closed
+func WithDrainTimeout(timeout time.Duration) ServerConfiguration {
+	return func(s *Server) {
+		if timeout > 0 {
+			s.drainTimeout = timeout
+		}
+	}
+}
+
+// WithReadinessDelay keeps serving requests for the delay after readiness flipped, so load balancers can notice it
+func WithReadinessDelay(delay time.Duration) ServerConfiguration {
+	return func(s *Server) {
+		s.readinessDelay = delay
+	}
+}
+
+func WithReadiness(readiness *Readiness) ServerConfiguration {
+	return func(s *Server) {
+		s.readiness = readiness
+	}
+}
+
+// WithCloser registers the resource closed after the server drained
+func WithCloser(name string, close func(ctx context.Context) error) ServerConfiguration {
+	return func(s *Server) {
+		s.closers = append(s.closers, Closer{Name: name, Close: close})
+	}
+}
+
+// Server runs the http server until the context is done and then shuts it down gracefully
+type Server struct {
+	srv            *http.Server
+	readiness      *Readiness
+	drainTimeout   time.Duration
+	readinessDelay time.Duration
+	closers        []Closer
+	listen         func() error
+}
+
+func NewServer(addr string, handler http.Handler, configuration ...ServerConfiguration) *Server {
+	s := &Server{
+		srv:            &http.Server{Addr: addr, Handler: handler},
+		readiness:      &Readiness{},
+		drainTimeout:   DefaultDrainTimeout,
+		readinessDelay: DefaultReadinessDelay,
+	}
+	s.listen = s.srv.ListenAndServe
+
+	for _, c := range configuration {
+		c(s)
+	}
+
+	return s
+}
+
+func (s *Server) Readiness() *Readiness {
+	return s.readiness
+}
+
+// Run serves requests until the context is done, then it flips readiness, drains in-flight requests
+// and closes registered resources in order
+func (s *Server) Run(ctx context.Context) error {
+	listenErr := make(chan error, 1)
+
+	go func() {
+		listenErr <- s.listen()
+	}()
+
+	s.readiness.SetReady(true)
+
+	var err error
+
+	select {
+	case err = <-listenErr:
+		s.readiness.SetReady(false)
+	case <-ctx.Done():
+		err = s.shutdown()
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+
+	return errors.Join(err, s.close())
+}
+
+func (s *Server) shutdown() error {
+	s.readiness.SetReady(false)
+
+	log.Info("shutting down, the api is not ready")
+
+	time.Sleep(s.readinessDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.drainTimeout)
+	defer cancel()
+
+	if err := s.srv.Shutdown(ctx); err != nil {
+		log.WithError(err).Error("in-flight requests were not drained")
+
+		return errors.Join(err, s.srv.Close())
+	}
+
+	log.Info("in-flight requests drained")
+
+	return nil
+}
+
+func (s *Server) close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.drainTimeout)
+	defer cancel()
+
+	var errs []error
+
+	for _, c := range s.closers {
+		if err := c.Close(ctx); err != nil {
+			log.WithError(err).Errorf("cannot close %s", c.Name)
+			errs = append(errs, err)
+
+			continue
+		}
+
+		log.Infof("%s closed", c.Name)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServer_RunDrainsRequestsBeforeClosingResources(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var closed []string
+	closer := func(name string) ServerConfiguration {
+		return WithCloser(name, func(context.Context) error {
+			closed = append(closed, name)
+
+			return nil
+		})
+	}
+
+	s := NewServer("", handler, WithDrainTimeout(time.Second), closer("amqp"), closer("database"))
+	s.listen = func() error { return s.srv.Serve(ln) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+
+	go func() {
+		stopped <- s.Run(ctx)
+	}()
+
+	response := make(chan int)
+
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			response <- 0
+
+			return
+		}
+
+		resp.Body.Close()
+		response <- resp.StatusCode
+	}()
+
+	<-started
+	cancel()
+
+	for s.Readiness().Ready() {
+		time.Sleep(time.Millisecond)
+	}
+
+	if len(closed) != 0 {
+		t.Errorf("Run() closed %v before in-flight request finished", closed)
+	}
+
+	close(release)
+
+	if status := <-response; status != http.StatusOK {
+		t.Errorf("Run() in-flight request status = %d, want %d", status, http.StatusOK)
+	}
+
+	if err := <-stopped; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(closed) != 2 || closed[0] != "amqp" || closed[1] != "database" {
+		t.Errorf("Run() closed = %v, want [amqp database]", closed)
+	}
+}
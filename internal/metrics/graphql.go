@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+const unnamedOperation = "unnamed"
+
+// GraphQLExtension records latency and status of every GraphQL operation
+type GraphQLExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = GraphQLExtension{}
+
+func (GraphQLExtension) ExtensionName() string {
+	return "Metrics"
+}
+
+func (GraphQLExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (GraphQLExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+	started := oc.Stats.OperationStart
+	if started.IsZero() {
+		started = time.Now()
+	}
+
+	resp := next(ctx)
+
+	name, operationType := oc.OperationName, "unknown"
+	if oc.Operation != nil {
+		operationType = string(oc.Operation.Operation)
+
+		if name == "" {
+			name = oc.Operation.Name
+		}
+	}
+
+	if name == "" {
+		name = unnamedOperation
+	}
+
+	status := StatusOK
+	if resp == nil || len(resp.Errors) > 0 || len(graphql.GetErrors(ctx)) > 0 {
+		status = StatusError
+	}
+
+	GraphQLOperationDuration.WithLabelValues(name, operationType, status).Observe(time.Since(started).Seconds())
+
+	return resp
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	Namespace = "casper"
+
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// results of the consumed messages
+const (
+	ResultAcked      = "acked"
+	ResultRetried    = "retried"
+	ResultDeadLetter = "dead_letter"
+	ResultRequeued   = "requeued"
+)
+
+var (
+	GraphQLOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "graphql",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of GraphQL operations by operation name, type and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "type", "status"})
+
+	RepositoryCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "repository",
+		Name:      "call_duration_seconds",
+		Help:      "Duration of repository calls by repository, method and status.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"repository", "method", "status"})
+
+	MessagesPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "messages",
+		Name:      "published_total",
+		Help:      "Messages confirmed by the message broker by exchange and type.",
+	}, []string{"exchange", "type"})
+
+	MessagesPublishFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "messages",
+		Name:      "publish_failed_total",
+		Help:      "Failed attempts to publish messages by exchange and type.",
+	}, []string{"exchange", "type"})
+
+	MessagesPublishDead = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "messages",
+		Name:      "publish_dead_total",
+		Help:      "Outbox messages given up after too many failed attempts by exchange and type.",
+	}, []string{"exchange", "type"})
+
+	MessagesConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "messages",
+		Name:      "consumed_total",
+		Help:      "Consumed messages by queue and result: acked, retried, dead_letter or requeued.",
+	}, []string{"queue", "result"})
+
+	EventsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "events_created_total",
+		Help:      "Created events.",
+	})
+
+	InvitationsAccepted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "invitations_accepted_total",
+		Help:      "Accepted invitations.",
+	})
+
+	InvitationsWaitlisted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "invitations_waitlisted_total",
+		Help:      "Users put on the waitlist of the full event.",
+	})
+
+	CapacityFullRejections = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "capacity_full_rejections_total",
+		Help:      "Accepted invitations and joins rejected because the event is full.",
+	})
+)
+
+// Handler exposes the registered metrics in the prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func Status(err error) string {
+	if err != nil {
+		return StatusError
+	}
+
+	return StatusOK
+}
+
+// ObserveRepositoryCall records duration of the repository call started at the given time, it is meant to be deferred
+// with the pointer to the returned error
+func ObserveRepositoryCall(repository, method string, started time.Time, err *error) {
+	RepositoryCallDuration.WithLabelValues(repository, method, Status(*err)).Observe(time.Since(started).Seconds())
+}
@@ -0,0 +1,31 @@
+package observers
+
+import (
+	"context"
+	"encoding/json"
+
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/exchange"
+	"event-service/internal/exchange/eventcancel"
+)
+
+type EventCancelObserver struct {
+	producer exchange.Producer
+}
+
+func NewEventCancelObserver(producer exchange.Producer) *EventCancelObserver {
+	return &EventCancelObserver{producer: producer}
+}
+
+func (e EventCancelObserver) Notify(ctx context.Context, event aggregate.Event) error {
+	body, marshErr := json.Marshal(eventcancel.Message{
+		Type:   eventcancel.MessageType,
+		Event:  &event,
+		Reason: event.Event.CancellationReason,
+	})
+	if marshErr != nil {
+		return marshErr
+	}
+
+	return e.producer.Publish(ctx, body)
+}
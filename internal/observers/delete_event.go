@@ -0,0 +1,30 @@
+package observers
+
+import (
+	"context"
+	"encoding/json"
+
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/exchange"
+	"event-service/internal/exchange/eventdelete"
+)
+
+type EventDeleteObserver struct {
+	producer exchange.Producer
+}
+
+func NewEventDeleteObserver(producer exchange.Producer) *EventDeleteObserver {
+	return &EventDeleteObserver{producer: producer}
+}
+
+func (e EventDeleteObserver) Notify(ctx context.Context, event aggregate.Event) error {
+	body, marshErr := json.Marshal(eventdelete.Message{
+		Type:  eventdelete.MessageType,
+		Event: &event,
+	})
+	if marshErr != nil {
+		return marshErr
+	}
+
+	return e.producer.Publish(ctx, body)
+}
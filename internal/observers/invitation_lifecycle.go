@@ -0,0 +1,33 @@
+package observers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"event-service/internal/auth"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/exchange"
+	"event-service/internal/exchange/eventinvitation"
+)
+
+// InvitationLifecycleObserver publishes invitation transition of the given type
+type InvitationLifecycleObserver struct {
+	producer    exchange.Producer
+	messageType eventinvitation.MessageType
+}
+
+func NewInvitationLifecycleObserver(producer exchange.Producer, messageType eventinvitation.MessageType) *InvitationLifecycleObserver {
+	return &InvitationLifecycleObserver{producer: producer, messageType: messageType}
+}
+
+func (o InvitationLifecycleObserver) Notify(ctx context.Context, invitation aggregate.Invitation) error {
+	actor, _ := auth.ActorFromContext(ctx)
+
+	body, marshErr := json.Marshal(eventinvitation.NewMessage(o.messageType, invitation, actor, time.Now()))
+	if marshErr != nil {
+		return marshErr
+	}
+
+	return o.producer.Publish(ctx, body)
+}
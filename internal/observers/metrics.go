@@ -0,0 +1,41 @@
+package observers
+
+import (
+	"context"
+
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/services"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CounterObserver counts the observed event changes, e.g. created events, once the transaction of the change is committed
+type CounterObserver struct {
+	counter prometheus.Counter
+}
+
+func NewCounterObserver(counter prometheus.Counter) *CounterObserver {
+	return &CounterObserver{counter: counter}
+}
+
+func (o CounterObserver) Notify(ctx context.Context, _ aggregate.Event) error {
+	services.AfterCommit(ctx, o.counter.Inc)
+
+	return nil
+}
+
+// InvitationCounterObserver counts the observed invitation transitions, e.g. accepted invitations,
+// once the transaction of the transition is committed
+type InvitationCounterObserver struct {
+	counter prometheus.Counter
+}
+
+func NewInvitationCounterObserver(counter prometheus.Counter) *InvitationCounterObserver {
+	return &InvitationCounterObserver{counter: counter}
+}
+
+func (o InvitationCounterObserver) Notify(ctx context.Context, _ aggregate.Invitation) error {
+	services.AfterCommit(ctx, o.counter.Inc)
+
+	return nil
+}
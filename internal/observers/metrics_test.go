@@ -0,0 +1,38 @@
+package observers
+
+import (
+	"context"
+	"testing"
+
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/services"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCounterObserver_NotifyCountsAfterCommit(t *testing.T) {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "events_created_total"})
+	o := NewCounterObserver(counter)
+
+	ctx, commit := services.WithCommitHooks(context.Background())
+	_ = o.Notify(ctx, aggregate.Event{})
+
+	if got := testutil.ToFloat64(counter); got != 0 {
+		t.Errorf("Notify() counted %v before the commit", got)
+	}
+
+	commit()
+
+	if got := testutil.ToFloat64(counter); got != 1 {
+		t.Errorf("Notify() counted %v after the commit, want 1", got)
+	}
+
+	// rolled back transaction never runs its hooks
+	rolledBack, _ := services.WithCommitHooks(context.Background())
+	_ = o.Notify(rolledBack, aggregate.Event{})
+
+	if got := testutil.ToFloat64(counter); got != 1 {
+		t.Errorf("Notify() counted %v after the rollback, want 1", got)
+	}
+}
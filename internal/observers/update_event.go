@@ -17,11 +17,11 @@ func NewEventUpdateObserver(producer exchange.Producer) *EventUpdateObserver {
 	return &EventUpdateObserver{producer: producer}
 }
 
-func (e EventUpdateObserver) Notify(_ context.Context, event aggregate.Event) error {
+func (e EventUpdateObserver) Notify(ctx context.Context, event aggregate.Event) error {
 	body, marshErr := json.Marshal(eventupdate.Message{Event: &event})
 	if marshErr != nil {
 		return marshErr
 	}
 
-	return e.producer.Publish(body)
+	return e.producer.Publish(ctx, body)
 }
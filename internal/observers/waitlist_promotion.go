@@ -0,0 +1,24 @@
+package observers
+
+import (
+	"context"
+
+	"event-service/internal/domain/event/aggregate"
+)
+
+type WaitlistPromoter interface {
+	PromoteWaitlisted(context.Context, *aggregate.Event) error
+}
+
+// WaitlistPromotionObserver promotes waitlisted users after the event update, e.g. when the capacity is raised
+type WaitlistPromotionObserver struct {
+	promoter WaitlistPromoter
+}
+
+func NewWaitlistPromotionObserver(promoter WaitlistPromoter) *WaitlistPromotionObserver {
+	return &WaitlistPromotionObserver{promoter: promoter}
+}
+
+func (w WaitlistPromotionObserver) Notify(ctx context.Context, event aggregate.Event) error {
+	return w.promoter.PromoteWaitlisted(ctx, &event)
+}
@@ -0,0 +1,36 @@
+package outbox
+
+import (
+	"context"
+	"strconv"
+
+	"event-service/internal/exchange"
+
+	"github.com/streadway/amqp"
+)
+
+// AMQPPublisher publishes outbox messages on the broker, a message is sent only when the broker confirmed it
+type AMQPPublisher struct {
+	publisher exchange.Publisher
+}
+
+func NewAMQPPublisher(publisher exchange.Publisher) *AMQPPublisher {
+	return &AMQPPublisher{publisher: publisher}
+}
+
+func (p AMQPPublisher) Publish(ctx context.Context, m Message) error {
+	return p.publisher.Publish(
+		ctx,
+		m.Exchange,
+		m.RoutingKey,
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			Type:         m.Type,
+			Headers:      amqp.Table(m.Headers),
+			Timestamp:    m.CreatedAt,
+			MessageId:    "outbox-" + strconv.FormatUint(uint64(m.ID), 10),
+			Body:         m.Body,
+		},
+	)
+}
@@ -0,0 +1,76 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"event-service/internal/exchange"
+	"event-service/internal/tracing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Message is a pending publication stored together with the change that caused it
+type Message struct {
+	ID            uint
+	Exchange      string
+	RoutingKey    string
+	Type          string
+	Headers       map[string]any
+	Body          []byte
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	SentAt        *time.Time
+	DeadAt        *time.Time
+}
+
+// Store keeps outbox messages, Add must use the transaction from the context
+type Store interface {
+	Add(ctx context.Context, m *Message) error
+	// Pending claims messages neither sent nor dead in order they were added until the transaction from the context ends,
+	// nothing is returned while older messages are claimed by another relay
+	Pending(ctx context.Context, limit int) ([]Message, error)
+	MarkSent(ctx context.Context, id uint, at time.Time) error
+	MarkFailed(ctx context.Context, id uint, reason string, retryAt time.Time) error
+	// MarkDead records the last failed attempt of the message which is given up, it is no longer pending
+	MarkDead(ctx context.Context, id uint, reason string, at time.Time) error
+	// Purge deletes messages sent or given up before the time and returns number of the deleted ones
+	Purge(ctx context.Context, sentBefore time.Time) (int64, error)
+}
+
+// Producer writes messages of the route into the outbox instead of publishing them directly
+type Producer struct {
+	store Store
+	route exchange.Route
+}
+
+func NewProducer(store Store, route exchange.Route) *Producer {
+	return &Producer{store: store, route: route}
+}
+
+// Publish adds the message to the outbox, its headers carry the trace context to the consumers
+func (p Producer) Publish(ctx context.Context, body []byte) (err error) {
+	ctx, span := tracing.Start(ctx, "outbox.publish "+p.route.RoutingKey, trace.WithSpanKind(trace.SpanKindProducer))
+	defer tracing.End(span, &err)
+
+	headers := make(map[string]any, len(p.route.Headers))
+	for key, value := range p.route.Headers {
+		headers[key] = value
+	}
+
+	tracing.Inject(ctx, headers)
+
+	now := time.Now()
+
+	return p.store.Add(ctx, &Message{
+		Exchange:      p.route.Exchange,
+		RoutingKey:    p.route.RoutingKey,
+		Type:          p.route.Type,
+		Headers:       headers,
+		Body:          body,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+}
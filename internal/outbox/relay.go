@@ -0,0 +1,262 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"event-service/internal/metrics"
+	"event-service/internal/services"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ServiceName = "outbox relay"
+
+const (
+	DefaultInterval   = time.Second
+	DefaultBatchSize  = 100
+	DefaultRetryDelay = time.Second
+	DefaultMaxDelay   = 5 * time.Minute
+	// DefaultMaxAttempts is number of failed attempts after which the message is given up
+	DefaultMaxAttempts = 10
+	// DefaultRetention is how long sent and dead messages are kept in the outbox
+	DefaultRetention = 7 * 24 * time.Hour
+	// PurgeInterval is how often sent and dead messages past the retention are deleted
+	PurgeInterval = time.Hour
+)
+
+// Publisher delivers outbox message to the broker
+type Publisher interface {
+	Publish(ctx context.Context, m Message) error
+}
+
+type RelayConfiguration func(*Relay) error
+
+func WithStore(store Store) RelayConfiguration {
+	return func(r *Relay) error {
+		r.store = store
+
+		return nil
+	}
+}
+
+func WithPublisher(publisher Publisher) RelayConfiguration {
+	return func(r *Relay) error {
+		r.publisher = publisher
+
+		return nil
+	}
+}
+
+// WithTransactor sets transactor the batch of messages is claimed in, so relays do not publish the same messages
+func WithTransactor(transactor services.Transactor) RelayConfiguration {
+	return func(r *Relay) error {
+		r.transactor = transactor
+
+		return nil
+	}
+}
+
+// WithRetention sets how long sent and dead messages are kept before they are purged
+func WithRetention(retention time.Duration) RelayConfiguration {
+	return func(r *Relay) error {
+		if retention > 0 {
+			r.retention = retention
+		}
+
+		return nil
+	}
+}
+
+// WithMaxAttempts sets number of failed attempts after which the message is marked dead and skipped
+func WithMaxAttempts(maxAttempts int) RelayConfiguration {
+	return func(r *Relay) error {
+		if maxAttempts > 0 {
+			r.maxAttempts = maxAttempts
+		}
+
+		return nil
+	}
+}
+
+// WithInterval sets how often the outbox is polled for pending messages
+func WithInterval(interval time.Duration) RelayConfiguration {
+	return func(r *Relay) error {
+		r.interval = interval
+
+		return nil
+	}
+}
+
+// WithRetryDelay sets delay after the first failed attempt, it doubles with every next attempt up to the max delay
+func WithRetryDelay(delay, maxDelay time.Duration) RelayConfiguration {
+	return func(r *Relay) error {
+		r.retryDelay, r.maxDelay = delay, maxDelay
+
+		return nil
+	}
+}
+
+// Relay publishes pending outbox messages in order they were written. Failed message is retried with
+// exponential backoff and holds back the messages written after it, so consumers never see them reordered.
+// After max attempts the message is marked dead and skipped, so it does not hold back the outbox forever
+type Relay struct {
+	store       Store
+	publisher   Publisher
+	transactor  services.Transactor
+	retention   time.Duration
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	retryDelay  time.Duration
+	maxDelay    time.Duration
+	now         func() time.Time
+}
+
+func NewRelay(configuration ...RelayConfiguration) (*Relay, error) {
+	r := &Relay{
+		interval:    DefaultInterval,
+		batchSize:   DefaultBatchSize,
+		maxAttempts: DefaultMaxAttempts,
+		retryDelay:  DefaultRetryDelay,
+		maxDelay:    DefaultMaxDelay,
+		retention:   DefaultRetention,
+		now:         time.Now,
+	}
+
+	for _, cfg := range configuration {
+		if err := cfg(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.validateRequiredResources(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r Relay) validateRequiredResources() error {
+	if r.store == nil {
+		return services.NewErrResourceIsRequired(ServiceName, "outbox store")
+	}
+
+	if r.publisher == nil {
+		return services.NewErrResourceIsRequired(ServiceName, "publisher")
+	}
+
+	return nil
+}
+
+// Run relays pending messages and purges the sent ones until the context is done
+func (r Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	var purgedAt time.Time
+
+	for {
+		if r.now().Sub(purgedAt) >= PurgeInterval {
+			if err := r.Purge(ctx); err != nil {
+				log.WithError(err).Error("outbox purge failed")
+			}
+
+			purgedAt = r.now()
+		}
+
+		for {
+			sent, err := r.RelayPending(ctx)
+			if err != nil {
+				log.WithError(err).Error("outbox relay failed")
+			}
+
+			if err != nil || sent < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes single batch of pending messages and returns number of the sent and dead ones,
+// the batch is claimed in the transaction so concurrent relays never publish it twice
+func (r Relay) RelayPending(ctx context.Context) (sent int, err error) {
+	err = services.RunInTransaction(ctx, r.transactor, func(ctx context.Context) error {
+		sent, err = r.relayBatch(ctx)
+
+		return err
+	})
+
+	return sent, err
+}
+
+func (r Relay) relayBatch(ctx context.Context) (int, error) {
+	messages, err := r.store.Pending(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for relayed, m := range messages {
+		if m.NextAttemptAt.After(r.now()) {
+			return relayed, nil
+		}
+
+		if publishErr := r.publisher.Publish(ctx, m); publishErr != nil {
+			metrics.MessagesPublishFailed.WithLabelValues(m.Exchange, m.Type).Inc()
+			logger := log.WithError(publishErr).WithField("message", m.ID).WithField("attempt", m.Attempts+1)
+
+			if m.Attempts+1 < r.maxAttempts {
+				logger.Warn("cannot publish outbox message")
+
+				return relayed, r.store.MarkFailed(ctx, m.ID, publishErr.Error(), r.now().Add(r.backoff(m.Attempts)))
+			}
+
+			metrics.MessagesPublishDead.WithLabelValues(m.Exchange, m.Type).Inc()
+			logger.Error("outbox message is marked dead after too many attempts")
+
+			if err := r.store.MarkDead(ctx, m.ID, publishErr.Error(), r.now()); err != nil {
+				return relayed, err
+			}
+
+			continue
+		}
+
+		metrics.MessagesPublished.WithLabelValues(m.Exchange, m.Type).Inc()
+
+		if err := r.store.MarkSent(ctx, m.ID, r.now()); err != nil {
+			return relayed, err
+		}
+	}
+
+	return len(messages), nil
+}
+
+// Purge deletes messages sent or marked dead before the retention period
+func (r Relay) Purge(ctx context.Context) error {
+	purged, err := r.store.Purge(ctx, r.now().Add(-r.retention))
+	if err != nil {
+		return err
+	}
+
+	if purged > 0 {
+		log.WithField("messages", purged).Info("sent and dead outbox messages purged")
+	}
+
+	return nil
+}
+
+// backoff returns delay before the next attempt of the message that failed given number of times before
+func (r Relay) backoff(attempts int) time.Duration {
+	delay := r.retryDelay
+	for i := 0; i < attempts && delay < r.maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, r.maxDelay)
+}
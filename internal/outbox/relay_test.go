@@ -0,0 +1,251 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"event-service/internal/exchange"
+	"event-service/internal/metrics"
+)
+
+type storeMock struct {
+	messages []Message
+}
+
+func (s *storeMock) Add(_ context.Context, m *Message) error {
+	m.ID = uint(len(s.messages) + 1)
+	s.messages = append(s.messages, *m)
+
+	return nil
+}
+
+func (s *storeMock) Pending(_ context.Context, limit int) (pending []Message, _ error) {
+	for _, m := range s.messages {
+		if m.SentAt == nil && m.DeadAt == nil && len(pending) < limit {
+			pending = append(pending, m)
+		}
+	}
+
+	return pending, nil
+}
+
+func (s *storeMock) MarkSent(_ context.Context, id uint, at time.Time) error {
+	s.messages[id-1].SentAt = &at
+
+	return nil
+}
+
+func (s *storeMock) MarkFailed(_ context.Context, id uint, reason string, retryAt time.Time) error {
+	s.messages[id-1].Attempts++
+	s.messages[id-1].LastError = reason
+	s.messages[id-1].NextAttemptAt = retryAt
+
+	return nil
+}
+
+func (s *storeMock) MarkDead(_ context.Context, id uint, reason string, at time.Time) error {
+	s.messages[id-1].Attempts++
+	s.messages[id-1].LastError = reason
+	s.messages[id-1].DeadAt = &at
+
+	return nil
+}
+
+func (s *storeMock) Purge(_ context.Context, sentBefore time.Time) (purged int64, _ error) {
+	kept := s.messages[:0]
+	for _, m := range s.messages {
+		if (m.SentAt != nil && m.SentAt.Before(sentBefore)) || (m.DeadAt != nil && m.DeadAt.Before(sentBefore)) {
+			purged++
+
+			continue
+		}
+
+		kept = append(kept, m)
+	}
+
+	s.messages = kept
+
+	return purged, nil
+}
+
+type publisherMock struct {
+	failing   map[string]bool
+	published []string
+}
+
+func (p *publisherMock) Publish(_ context.Context, m Message) error {
+	if p.failing[string(m.Body)] {
+		return errors.New("broker unavailable")
+	}
+
+	p.published = append(p.published, string(m.Body))
+
+	return nil
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		messages      []Message
+		failing       map[string]bool
+		wantSent      int
+		wantPublished []string
+		wantRetryAt   map[uint]time.Time
+		wantDead      []uint
+	}{
+		{
+			name: "messages are published in order",
+			messages: []Message{
+				{Body: []byte("first"), NextAttemptAt: now},
+				{Body: []byte("second"), NextAttemptAt: now},
+			},
+			wantSent:      2,
+			wantPublished: []string{"first", "second"},
+		},
+		{
+			name: "failed message is retried later and holds back next ones",
+			messages: []Message{
+				{Body: []byte("first"), NextAttemptAt: now},
+				{Body: []byte("second"), NextAttemptAt: now, Attempts: 2},
+				{Body: []byte("third"), NextAttemptAt: now},
+			},
+			failing:       map[string]bool{"second": true},
+			wantSent:      1,
+			wantPublished: []string{"first"},
+			wantRetryAt:   map[uint]time.Time{2: now.Add(4 * time.Second)},
+		},
+		{
+			name: "message failing max attempts is marked dead and skipped",
+			messages: []Message{
+				{Body: []byte("first"), NextAttemptAt: now},
+				{Body: []byte("second"), NextAttemptAt: now, Attempts: 2},
+				{Body: []byte("third"), NextAttemptAt: now},
+			},
+			failing:       map[string]bool{"second": true},
+			wantSent:      3,
+			wantPublished: []string{"first", "third"},
+			wantDead:      []uint{2},
+		},
+		{
+			name: "message waiting for retry holds back next ones",
+			messages: []Message{
+				{Body: []byte("first"), NextAttemptAt: now.Add(time.Second)},
+				{Body: []byte("second"), NextAttemptAt: now},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &storeMock{}
+			for _, m := range tt.messages {
+				_ = store.Add(context.Background(), &m)
+			}
+
+			publisher := &publisherMock{failing: tt.failing}
+			maxAttempts := 5
+			if len(tt.wantDead) > 0 {
+				maxAttempts = 3
+			}
+
+			r, _ := NewRelay(WithStore(store), WithPublisher(publisher), WithRetryDelay(time.Second, time.Minute), WithMaxAttempts(maxAttempts))
+			r.now = func() time.Time { return now }
+			dead := testutil.ToFloat64(metrics.MessagesPublishDead.WithLabelValues("", ""))
+
+			sent, err := r.RelayPending(context.Background())
+			if err != nil {
+				t.Fatalf("RelayPending() error = %v", err)
+			}
+
+			if sent != tt.wantSent {
+				t.Errorf("RelayPending() sent = %v, want %v", sent, tt.wantSent)
+			}
+
+			if !reflect.DeepEqual(publisher.published, tt.wantPublished) {
+				t.Errorf("RelayPending() published = %v, want %v", publisher.published, tt.wantPublished)
+			}
+
+			for id, retryAt := range tt.wantRetryAt {
+				if m := store.messages[id-1]; !m.NextAttemptAt.Equal(retryAt) || m.SentAt != nil || m.LastError == "" {
+					t.Errorf("RelayPending() failed message = %+v, want retry at %v", m, retryAt)
+				}
+			}
+
+			for _, id := range tt.wantDead {
+				if m := store.messages[id-1]; m.DeadAt == nil || m.SentAt != nil || m.Attempts != maxAttempts {
+					t.Errorf("RelayPending() message = %+v, want marked dead after %d attempts", m, maxAttempts)
+				}
+			}
+
+			if got := testutil.ToFloat64(metrics.MessagesPublishDead.WithLabelValues("", "")); got != dead+float64(len(tt.wantDead)) {
+				t.Errorf("RelayPending() counted %v dead messages, want %d", got-dead, len(tt.wantDead))
+			}
+
+			if pending, _ := store.Pending(context.Background(), 10); len(tt.wantDead) > 0 && len(pending) != 0 {
+				t.Errorf("RelayPending() left pending messages %+v", pending)
+			}
+		})
+	}
+}
+
+func TestRelay_Purge(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	old, recent := now.Add(-8*24*time.Hour), now.Add(-time.Hour)
+
+	store := &storeMock{messages: []Message{
+		{ID: 1, Body: []byte("old"), SentAt: &old},
+		{ID: 2, Body: []byte("dead"), DeadAt: &old},
+		{ID: 3, Body: []byte("recent"), SentAt: &recent},
+		{ID: 4, Body: []byte("pending")},
+	}}
+
+	r, _ := NewRelay(WithStore(store), WithPublisher(&publisherMock{}))
+	r.now = func() time.Time { return now }
+
+	if err := r.Purge(context.Background()); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+
+	var kept []string
+	for _, m := range store.messages {
+		kept = append(kept, string(m.Body))
+	}
+
+	if want := []string{"recent", "pending"}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("Purge() kept = %v, want %v", kept, want)
+	}
+}
+
+func TestRelay_backoff(t *testing.T) {
+	r := Relay{retryDelay: time.Second, maxDelay: 10 * time.Second}
+
+	for attempts, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		if got := r.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestProducer_Publish(t *testing.T) {
+	store := &storeMock{}
+	route := exchange.Route{Exchange: "casper-events", RoutingKey: "event-update", Type: "EventUpdated"}
+
+	if err := NewProducer(store, route).Publish(context.Background(), []byte("body")); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if len(store.messages) != 1 {
+		t.Fatalf("Publish() stored %d messages, want 1", len(store.messages))
+	}
+
+	m := store.messages[0]
+	if m.Exchange != route.Exchange || m.RoutingKey != route.RoutingKey || m.Type != route.Type || string(m.Body) != "body" || m.SentAt != nil {
+		t.Errorf("Publish() stored message = %+v", m)
+	}
+}
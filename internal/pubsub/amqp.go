@@ -0,0 +1,104 @@
+package pubsub
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+)
+
+const DefaultResubscribeDelay = time.Second
+
+// Channel is the part of the amqp channel used by the bridge
+type Channel interface {
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Close() error
+}
+
+// ChannelSource opens a new channel, e.g. on the current connection of the connection manager
+type ChannelSource func(ctx context.Context) (Channel, error)
+
+// Bridge feeds the broker with messages of the exchange, so subscribers of every api replica see changes made
+// through any of them. Each replica consumes its own exclusive queue, which the message broker removes once
+// the replica disconnects. Messages published while the replica is reconnecting are not delivered
+type Bridge struct {
+	channels   ChannelSource
+	exchange   string
+	topics     []string
+	publisher  Publisher
+	retryDelay time.Duration
+}
+
+func NewBridge(channels ChannelSource, exchangeName string, publisher Publisher, topics ...string) *Bridge {
+	return &Bridge{
+		channels:   channels,
+		exchange:   exchangeName,
+		topics:     topics,
+		publisher:  publisher,
+		retryDelay: DefaultResubscribeDelay,
+	}
+}
+
+// Run forwards messages until the context is done, it subscribes again when the channel is closed
+func (b *Bridge) Run(ctx context.Context) error {
+	for {
+		err := b.forward(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			log.WithError(err).WithField("exchange", b.exchange).Warn("subscriptions bridge failed, subscribing again")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(b.retryDelay):
+		}
+	}
+}
+
+// forward publishes messages delivered on a single channel until the channel is closed or the context is done
+func (b *Bridge) forward(ctx context.Context) error {
+	ch, err := b.channels(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer ch.Close()
+
+	queue, err := ch.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, topic := range b.topics {
+		if err := ch.QueueBind(queue.Name, topic, b.exchange, false, nil); err != nil {
+			return err
+		}
+	}
+
+	deliveries, err := ch.Consume(queue.Name, "", true, true, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-deliveries:
+			if !ok {
+				return amqp.ErrClosed
+			}
+
+			if err := b.publisher.Publish(ctx, d.RoutingKey, d.Body); err != nil {
+				return err
+			}
+		}
+	}
+}
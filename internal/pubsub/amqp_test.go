@@ -0,0 +1,84 @@
+package pubsub
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+type channelMock struct {
+	deliveries chan amqp.Delivery
+	bindings   []string
+	closed     chan struct{}
+}
+
+func (c *channelMock) QueueDeclare(string, bool, bool, bool, bool, amqp.Table) (amqp.Queue, error) {
+	return amqp.Queue{Name: "amq.gen-replica"}, nil
+}
+
+func (c *channelMock) QueueBind(_, key, exchange string, _ bool, _ amqp.Table) error {
+	c.bindings = append(c.bindings, exchange+":"+key)
+
+	return nil
+}
+
+func (c *channelMock) Consume(string, string, bool, bool, bool, bool, amqp.Table) (<-chan amqp.Delivery, error) {
+	return c.deliveries, nil
+}
+
+func (c *channelMock) Close() error {
+	close(c.closed)
+
+	return nil
+}
+
+func newChannelMock() *channelMock {
+	return &channelMock{deliveries: make(chan amqp.Delivery, 1), closed: make(chan struct{})}
+}
+
+func TestBridge_Run(t *testing.T) {
+	first, second := newChannelMock(), newChannelMock()
+	channels := make(chan *channelMock, 2)
+	channels <- first
+	channels <- second
+
+	broker := NewBroker()
+	updates, _ := broker.Subscribe(context.Background(), "event-update")
+
+	bridge := NewBridge(func(context.Context) (Channel, error) {
+		return <-channels, nil
+	}, "casper-events", broker, "event-update", "invitation.joined")
+	bridge.retryDelay = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		_ = bridge.Run(ctx)
+	}()
+
+	first.deliveries <- amqp.Delivery{RoutingKey: "event-update", Body: []byte("first")}
+	if got := <-updates; string(got.Body) != "first" {
+		t.Errorf("Run() forwarded %q, want %q", got.Body, "first")
+	}
+
+	// the broker closes the channel, e.g. on restart, the bridge subscribes again on a new one
+	close(first.deliveries)
+	<-first.closed
+
+	second.deliveries <- amqp.Delivery{RoutingKey: "event-update", Body: []byte("second")}
+	if got := <-updates; string(got.Body) != "second" {
+		t.Errorf("Run() forwarded %q after resubscribing, want %q", got.Body, "second")
+	}
+
+	cancel()
+	<-done
+
+	if want := []string{"casper-events:event-update", "casper-events:invitation.joined"}; !reflect.DeepEqual(second.bindings, want) {
+		t.Errorf("Run() bindings = %v, want %v", second.bindings, want)
+	}
+}
@@ -0,0 +1,169 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"event-service/internal/services"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultBufferSize is number of messages kept for the subscriber that reads slower than they are published
+const DefaultBufferSize = 16
+
+var ErrClosed = errors.New("pub/sub broker is closed")
+
+// Message is the body published on the topic, topics are routing keys of the casper-events exchange
+type Message struct {
+	Topic string
+	Body  []byte
+}
+
+// Subscriber delivers messages of the topics until the context is done, the channel is closed then
+type Subscriber interface {
+	Subscribe(ctx context.Context, topics ...string) (<-chan Message, error)
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, topic string, body []byte) error
+}
+
+type subscription struct {
+	topics   map[string]bool
+	messages chan Message
+}
+
+type BrokerConfiguration func(*Broker)
+
+// WithBufferSize sets number of messages kept for every subscriber
+func WithBufferSize(size int) BrokerConfiguration {
+	return func(b *Broker) {
+		if size > 0 {
+			b.bufferSize = size
+		}
+	}
+}
+
+// Broker passes messages between publishers and subscribers of the process. Publishing never blocks,
+// messages are dropped for the subscriber whose buffer is full
+type Broker struct {
+	mu            sync.RWMutex
+	subscriptions map[*subscription]struct{}
+	bufferSize    int
+	closed        bool
+}
+
+func NewBroker(configuration ...BrokerConfiguration) *Broker {
+	b := &Broker{
+		subscriptions: map[*subscription]struct{}{},
+		bufferSize:    DefaultBufferSize,
+	}
+
+	for _, cfg := range configuration {
+		cfg(b)
+	}
+
+	return b
+}
+
+func (b *Broker) Publish(_ context.Context, topic string, body []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	for s := range b.subscriptions {
+		if !s.topics[topic] {
+			continue
+		}
+
+		select {
+		case s.messages <- Message{Topic: topic, Body: body}:
+		default:
+			log.WithField("topic", topic).Warn("subscriber is too slow, message dropped")
+		}
+	}
+
+	return nil
+}
+
+func (b *Broker) Subscribe(ctx context.Context, topics ...string) (<-chan Message, error) {
+	s := &subscription{
+		topics:   make(map[string]bool, len(topics)),
+		messages: make(chan Message, b.bufferSize),
+	}
+
+	for _, topic := range topics {
+		s.topics[topic] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	b.subscriptions[s] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.unsubscribe(s)
+	}()
+
+	return s.messages, nil
+}
+
+func (b *Broker) unsubscribe(s *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscriptions[s]; !ok {
+		return
+	}
+
+	delete(b.subscriptions, s)
+	close(s.messages)
+}
+
+// Close ends all subscriptions, e.g. on shutdown the clients reconnect to another replica
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	for s := range b.subscriptions {
+		delete(b.subscriptions, s)
+		close(s.messages)
+	}
+}
+
+// Producer publishes bodies of the exchange producers on the topic of the broker, so the observers
+// feed subscriptions the same way they feed the exchange
+type Producer struct {
+	publisher Publisher
+	topic     string
+}
+
+func NewProducer(publisher Publisher, topic string) *Producer {
+	return &Producer{publisher: publisher, topic: topic}
+}
+
+// Publish delivers the body after the transaction of the context commits, so subscribers never see changes
+// that are rolled back
+func (p Producer) Publish(ctx context.Context, body []byte) error {
+	ctx = context.WithoutCancel(ctx)
+
+	services.AfterCommit(ctx, func() {
+		if err := p.publisher.Publish(ctx, p.topic, body); err != nil {
+			log.WithContext(ctx).WithError(err).WithField("topic", p.topic).Warn("cannot publish message to subscribers")
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBroker_Subscribe(t *testing.T) {
+	broker := NewBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	updates, err := broker.Subscribe(ctx, "event-update", "invitation.joined")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	for _, topic := range []string{"event-update", "event-cancelled", "invitation.joined"} {
+		if err := broker.Publish(context.Background(), topic, []byte(topic)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	var got []string
+	for i := 0; i < 2; i++ {
+		got = append(got, (<-updates).Topic)
+	}
+
+	if want := []string{"event-update", "invitation.joined"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Subscribe() topics = %v, want %v", got, want)
+	}
+
+	cancel()
+
+	select {
+	case _, open := <-updates:
+		if open {
+			t.Error("Subscribe() delivered message of the other topic")
+		}
+	case <-time.After(time.Second):
+		t.Error("Subscribe() channel is not closed when the context is done")
+	}
+}
+
+func TestBroker_DropsMessagesOfSlowSubscriber(t *testing.T) {
+	broker := NewBroker(WithBufferSize(1))
+
+	updates, _ := broker.Subscribe(context.Background(), "event-update")
+
+	for i := 0; i < 3; i++ {
+		if err := broker.Publish(context.Background(), "event-update", []byte{byte(i)}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	if got := (<-updates).Body; !reflect.DeepEqual(got, []byte{0}) {
+		t.Errorf("Subscribe() body = %v, want the first message", got)
+	}
+
+	select {
+	case m := <-updates:
+		t.Errorf("Subscribe() delivered message %v over the buffer", m.Body)
+	default:
+	}
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := NewBroker()
+	updates, _ := broker.Subscribe(context.Background(), "event-update")
+
+	broker.Close()
+
+	if _, open := <-updates; open {
+		t.Error("Close() left the subscription open")
+	}
+
+	if err := broker.Publish(context.Background(), "event-update", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish() error = %v, want %v", err, ErrClosed)
+	}
+
+	if _, err := broker.Subscribe(context.Background(), "event-update"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Subscribe() error = %v, want %v", err, ErrClosed)
+	}
+}
@@ -1,6 +1,11 @@
 package services
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
 
 type ErrResourceIsRequired struct {
 	service  string
@@ -17,3 +22,15 @@ func NewErrResourceIsRequired(service string, resource string) *ErrResourceIsReq
 func (e ErrResourceIsRequired) Error() string {
 	return fmt.Sprintf("resource: %s is required for %s service", e.resource, e.service)
 }
+
+// ErrInvalidID is returned when the identifier given to the service is not a valid UUID
+var ErrInvalidID = errors.New("invalid identifier")
+
+func ParseID(id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w %q: %w", ErrInvalidID, id, err)
+	}
+
+	return parsed, nil
+}
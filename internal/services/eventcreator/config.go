@@ -2,6 +2,7 @@ package eventcreator
 
 import (
 	"event-service/internal/domain/event"
+	"event-service/internal/services"
 )
 
 type Configuration func(*EventCreator) error
@@ -21,3 +22,12 @@ func WithObservers(observers ...Observer) Configuration {
 		return nil
 	}
 }
+
+// WithTransactor runs the changes together with the observers in a single transaction
+func WithTransactor(transactor services.Transactor) Configuration {
+	return func(ec *EventCreator) error {
+		ec.transactor = transactor
+
+		return nil
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"time"
 
+	"event-service/internal/auth"
 	"event-service/internal/domain/event"
 	"event-service/internal/domain/event/aggregate"
 	"event-service/internal/services"
+	"event-service/internal/tracing"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -24,6 +26,7 @@ type Observer interface {
 
 type EventCreator struct {
 	adder         event.Adder
+	transactor    services.Transactor
 	observersList []Observer
 }
 
@@ -51,27 +54,41 @@ func (ec EventCreator) validateRequiredResources() error {
 	return nil
 }
 
-func (ec EventCreator) CreateEvent(ctx context.Context, r Request) (*aggregate.Event, error) {
-	e, convErr := convertRequestToEvent(r)
+// CreateEvent creates new event organized by the authenticated user
+func (ec EventCreator) CreateEvent(ctx context.Context, r Request) (_ *aggregate.Event, err error) {
+	ctx, span := tracing.Start(ctx, "eventcreator.CreateEvent")
+	defer tracing.End(span, &err)
+
+	actor, authErr := auth.ActorFromContext(ctx)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	e, convErr := convertRequestToEvent(actor, r)
 	if convErr != nil {
 		return nil, errors.Wrap(convErr, "cannot convert request to entry")
 	}
 
-	if err := ec.adder.Add(ctx, e); err != nil {
-		return nil, errors.Wrap(err, "creating new event failed")
-	}
+	if err := services.RunInTransaction(ctx, ec.transactor, func(ctx context.Context) error {
+		if err := ec.adder.Add(ctx, e); err != nil {
+			return errors.Wrap(err, "creating new event failed")
+		}
 
-	for _, observer := range ec.observersList {
-		if err := observer.Notify(ctx, *e); err != nil {
-			return nil, err
+		for _, observer := range ec.observersList {
+			if err := observer.Notify(ctx, *e); err != nil {
+				return err
+			}
 		}
+
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	return e, nil
 }
 
 type Request struct {
-	User                string
 	Name, Description   string
 	Capacity            int
 	Duration            time.Duration
@@ -80,24 +97,24 @@ type Request struct {
 	DateStart           time.Time
 	DateRegistrationEnd time.Time
 	Public              bool
+	// RecurrenceRule is an iCalendar RRULE creating series of events starting with DateStart
+	RecurrenceRule       string
+	RecurrenceExceptions []time.Time
 }
 
-func convertRequestToEvent(r Request) (*aggregate.Event, error) {
-	userID, uuidErr := uuid.Parse(r.User)
-	if uuidErr != nil {
-		return nil, uuidErr
-	}
-
+func convertRequestToEvent(userID uuid.UUID, r Request) (*aggregate.Event, error) {
 	return aggregate.NewEvent(aggregate.EventPayload{
-		UserID:              userID,
-		Name:                r.Name,
-		Description:         r.Description,
-		Lat:                 r.Latitude,
-		Long:                r.Longitude,
-		Capacity:            r.Capacity,
-		Duration:            r.Duration,
-		StartDate:           r.DateStart,
-		RegistrationEndDate: r.DateRegistrationEnd,
-		Public:              r.Public,
+		UserID:               userID,
+		Name:                 r.Name,
+		Description:          r.Description,
+		Lat:                  r.Latitude,
+		Long:                 r.Longitude,
+		Capacity:             r.Capacity,
+		Duration:             r.Duration,
+		StartDate:            r.DateStart,
+		RegistrationEndDate:  r.DateRegistrationEnd,
+		Public:               r.Public,
+		RecurrenceRule:       r.RecurrenceRule,
+		RecurrenceExceptions: r.RecurrenceExceptions,
 	})
 }
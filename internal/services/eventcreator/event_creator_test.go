@@ -0,0 +1,127 @@
+package eventcreator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-service/internal/auth"
+	"event-service/internal/database/inmemmory/repository"
+	"event-service/internal/domain/event/aggregate"
+
+	"github.com/google/uuid"
+)
+
+func TestEventCreator_CreateEvent(t *testing.T) {
+	organizer := uuid.New()
+	validRequest := Request{
+		Name:      "Test Event",
+		Capacity:  10,
+		Duration:  24 * time.Hour,
+		DateStart: time.Now().Add(48 * time.Hour),
+		Public:    true,
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		request Request
+		wantErr error
+	}{
+		{
+			name:    "event is organized by the authenticated user",
+			ctx:     auth.ContextWithIdentity(context.Background(), auth.Identity{UserID: organizer}),
+			request: validRequest,
+		},
+		{
+			name:    "anonymous user cannot create events",
+			ctx:     context.Background(),
+			request: validRequest,
+			wantErr: auth.ErrUnauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ec, _ := NewEventCreator(WithAdderRepository(repository.NewEventsStorage()))
+
+			got, err := ec.CreateEvent(tt.ctx, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateEvent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err == nil && got.UserID != organizer {
+				t.Errorf("CreateEvent() organizer = %v, want %v", got.UserID, organizer)
+			}
+		})
+	}
+}
+
+type transactionKey struct{}
+
+// transactorMock marks the context passed to the function and remembers if the transaction was rolled back
+type transactorMock struct {
+	rolledBack bool
+}
+
+func (tm *transactorMock) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(context.WithValue(ctx, transactionKey{}, true))
+	tm.rolledBack = err != nil
+
+	return err
+}
+
+type observerFunc func(context.Context, aggregate.Event) error
+
+func (f observerFunc) Notify(ctx context.Context, e aggregate.Event) error {
+	return f(ctx, e)
+}
+
+func TestEventCreator_CreateEventInTransaction(t *testing.T) {
+	errPublish := errors.New("cannot write message")
+
+	tests := []struct {
+		name           string
+		observerErr    error
+		wantRolledBack bool
+	}{
+		{
+			name: "observers run in the transaction of the change",
+		},
+		{
+			name:           "observer failure rolls the change back",
+			observerErr:    errPublish,
+			wantRolledBack: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactor := &transactorMock{}
+			inTransaction := false
+
+			ec, _ := NewEventCreator(
+				WithAdderRepository(repository.NewEventsStorage()),
+				WithTransactor(transactor),
+				WithObservers(observerFunc(func(ctx context.Context, _ aggregate.Event) error {
+					inTransaction, _ = ctx.Value(transactionKey{}).(bool)
+
+					return tt.observerErr
+				})),
+			)
+
+			ctx := auth.ContextWithIdentity(context.Background(), auth.Identity{UserID: uuid.New()})
+			if _, err := ec.CreateEvent(ctx, Request{Name: "Test Event", Capacity: 10, Duration: time.Hour, DateStart: time.Now().Add(48 * time.Hour)}); !errors.Is(err, tt.observerErr) {
+				t.Fatalf("CreateEvent() error = %v, wantErr %v", err, tt.observerErr)
+			}
+
+			if !inTransaction {
+				t.Errorf("CreateEvent() observer was called outside of the transaction")
+			}
+
+			if transactor.rolledBack != tt.wantRolledBack {
+				t.Errorf("CreateEvent() rolled back = %v, want %v", transactor.rolledBack, tt.wantRolledBack)
+			}
+		})
+	}
+}
@@ -11,3 +11,19 @@ func WithFinderRepository(finder event.Finder) Configuration {
 		return nil
 	}
 }
+
+func WithBatchFinderRepository(finder event.BatchFinder) Configuration {
+	return func(ec *EventFinder) error {
+		ec.batchFinder = finder
+
+		return nil
+	}
+}
+
+func WithInvitationListerRepository(lister event.InvitationLister) Configuration {
+	return func(ec *EventFinder) error {
+		ec.invitationLister = lister
+
+		return nil
+	}
+}
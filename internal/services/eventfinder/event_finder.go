@@ -2,10 +2,13 @@ package eventfinder
 
 import (
 	"context"
-	"event-service/internal/services"
 
+	"event-service/internal/auth"
 	"event-service/internal/domain/event"
 	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
+	"event-service/internal/services"
+	"event-service/internal/tracing"
 
 	"github.com/google/uuid"
 )
@@ -13,12 +16,20 @@ import (
 var ServiceName = "event finder"
 
 type ListHandler interface {
-	List(context.Context, Request) ([]*aggregate.Event, error)
+	List(context.Context, Request) (event.Page, error)
 	GetByID(context.Context, string) (*aggregate.Event, error)
+	// GetByIDs returns the events found by the ids, missing events are left out
+	GetByIDs(context.Context, []uuid.UUID) (map[uuid.UUID]*aggregate.Event, error)
+	// ParticipantsOf returns accepted invitations followed by the waitlisted ones, events nobody takes part in are left out
+	ParticipantsOf(context.Context, []uuid.UUID) (map[uuid.UUID][]*aggregate.Invitation, error)
+	// Participants returns the page of the invitations of the event the authenticated or anonymous user can see
+	Participants(ctx context.Context, eventID string, r ParticipantsRequest) (event.ParticipantPage, error)
 }
 
 type EventFinder struct {
-	finder event.Finder
+	finder           event.Finder
+	batchFinder      event.BatchFinder
+	invitationLister event.InvitationLister
 }
 
 func NewEventFinder(configuration ...Configuration) (*EventFinder, error) {
@@ -42,18 +53,127 @@ func (ef EventFinder) validateRequiredResources() error {
 		return services.NewErrResourceIsRequired(ServiceName, "event finder repository")
 	}
 
+	if ef.batchFinder == nil {
+		return services.NewErrResourceIsRequired(ServiceName, "event batch finder repository")
+	}
+
+	if ef.invitationLister == nil {
+		return services.NewErrResourceIsRequired(ServiceName, "invitation lister repository")
+	}
+
 	return nil
 }
 
-func (ef EventFinder) List(ctx context.Context, r Request) ([]*aggregate.Event, error) {
-	return ef.finder.FindBy(ctx, convertRequestToListRequest(r))
+// List returns the page of the events the authenticated or anonymous user can see, recurring events are not expanded.
+// The series is listed once and it is ordered and paginated by the start of its first occurrence, the occurrences
+// are listed by the event itself
+func (ef EventFinder) List(ctx context.Context, r Request) (_ event.Page, err error) {
+	ctx, span := tracing.Start(ctx, "eventfinder.List")
+	defer tracing.End(span, &err)
+
+	actor, _ := auth.ActorFromContext(ctx)
+
+	listRequest, err := convertRequestToListRequest(r, actor)
+	if err != nil {
+		return event.Page{}, err
+	}
+
+	page, findErr := ef.finder.FindBy(ctx, listRequest)
+	if findErr != nil {
+		return event.Page{}, findErr
+	}
+
+	return page, nil
 }
 
-func (ef EventFinder) GetByID(ctx context.Context, id string) (*aggregate.Event, error) {
-	externalID, err := uuid.Parse(id)
+func (ef EventFinder) GetByID(ctx context.Context, id string) (_ *aggregate.Event, err error) {
+	ctx, span := tracing.Start(ctx, "eventfinder.GetByID")
+	defer tracing.End(span, &err)
+
+	externalID, err := services.ParseID(id)
 	if err != nil {
 		return nil, err
 	}
 
 	return ef.finder.FindByExternalID(ctx, externalID)
 }
+
+func (ef EventFinder) GetByIDs(ctx context.Context, ids []uuid.UUID) (_ map[uuid.UUID]*aggregate.Event, err error) {
+	ctx, span := tracing.Start(ctx, "eventfinder.GetByIDs")
+	defer tracing.End(span, &err)
+
+	found, err := ef.batchFinder.FindByExternalIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(map[uuid.UUID]*aggregate.Event, len(found))
+	for _, e := range found {
+		events[e.Event.ExternalID] = e
+	}
+
+	return events, nil
+}
+
+func (ef EventFinder) ParticipantsOf(ctx context.Context, ids []uuid.UUID) (_ map[uuid.UUID][]*aggregate.Invitation, err error) {
+	ctx, span := tracing.Start(ctx, "eventfinder.ParticipantsOf")
+	defer tracing.End(span, &err)
+
+	return ef.invitationLister.FindParticipants(ctx, ids)
+}
+
+func (ef EventFinder) Participants(ctx context.Context, eventID string, r ParticipantsRequest) (_ event.ParticipantPage, err error) {
+	ctx, span := tracing.Start(ctx, "eventfinder.Participants")
+	defer tracing.End(span, &err)
+
+	request, err := convertParticipantsRequest(r)
+	if err != nil {
+		return event.ParticipantPage{}, err
+	}
+
+	externalID, err := services.ParseID(eventID)
+	if err != nil {
+		return event.ParticipantPage{}, err
+	}
+
+	ea, err := ef.finder.FindByExternalID(ctx, externalID)
+	if err != nil {
+		return event.ParticipantPage{}, err
+	}
+
+	actor, _ := auth.ActorFromContext(ctx)
+
+	if err := policy.CanListInvitations(ea, actor, request.Status); err != nil {
+		return event.ParticipantPage{}, err
+	}
+
+	page, err := ef.invitationLister.FindByEvent(ctx, externalID, request)
+	if err != nil {
+		return event.ParticipantPage{}, err
+	}
+
+	return ef.withWaitlistPositions(ctx, externalID, page)
+}
+
+// withWaitlistPositions sets positions of the waitlisted users of the page, they are taken from the whole waitlist
+func (ef EventFinder) withWaitlistPositions(ctx context.Context, eventID uuid.UUID, page event.ParticipantPage) (event.ParticipantPage, error) {
+	waitlisted := false
+	for _, edge := range page.Edges {
+		waitlisted = waitlisted || edge.Invitation.IsWaitlisted()
+	}
+
+	if !waitlisted {
+		return page, nil
+	}
+
+	participants, err := ef.invitationLister.FindParticipants(ctx, []uuid.UUID{eventID})
+	if err != nil {
+		return event.ParticipantPage{}, err
+	}
+
+	for i, edge := range page.Edges {
+		page.Edges[i].WaitlistPosition = event.WaitlistPosition(participants[eventID], edge.Invitation.InvitedUser)
+	}
+
+	return page, nil
+}
@@ -1,16 +1,38 @@
 package eventfinder
 
 import (
-	"event-service/internal/domain/event/valueobject"
+	"errors"
 	"time"
+
+	"event-service/internal/domain/common/validation"
+	"event-service/internal/domain/event"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/valueobject"
+
+	"github.com/google/uuid"
 )
 
+var ErrCursorOrderMismatch = errors.New("cursor was created for a different ordering")
+
 type Request struct {
-	User     string
-	Name     string
-	Location *LocationRequest
-	Upcoming *UpcomingEventRequest
-	Public   bool
+	User        string
+	Participant string // lists events the user takes part in
+	Name        string
+	Location    *LocationRequest
+	Upcoming    *UpcomingEventRequest
+	Public      *bool
+	First       *int
+	Last        *int
+	After       string
+	Before      string
+	OrderBy     *OrderRequest
+}
+
+// ParticipantsRequest selects invitations of the event, at most 100 invitations are returned in a single page
+type ParticipantsRequest struct {
+	Status *aggregate.InvitationStatus
+	First  *int
+	After  string
 }
 
 type LocationRequest struct {
@@ -24,11 +46,22 @@ type UpcomingEventRequest struct {
 	Interval time.Duration
 }
 
-func convertRequestToListRequest(r Request) valueobject.ListRequest {
+type OrderRequest struct {
+	Field     valueobject.OrderField
+	Direction valueobject.OrderDirection
+}
+
+// convertRequestToListRequest converts the request of the viewer, anonymous viewer is the nil uuid
+func convertRequestToListRequest(r Request, viewer uuid.UUID) (valueobject.ListRequest, error) {
 	cfg := []valueobject.ListRequestConfiguration{
+		valueobject.WithViewer(viewer),
 		valueobject.WithName(r.Name),
 		valueobject.WithUser(r.User),
-		valueobject.WithPublicParam(r.Public),
+		valueobject.WithParticipant(r.Participant),
+	}
+
+	if r.Public != nil {
+		cfg = append(cfg, valueobject.WithPublicParam(*r.Public))
 	}
 
 	if r.Location != nil {
@@ -39,5 +72,93 @@ func convertRequestToListRequest(r Request) valueobject.ListRequest {
 		cfg = append(cfg, valueobject.WithTimeInterval(r.Upcoming.Date, r.Upcoming.Interval))
 	}
 
-	return valueobject.NewListRequest(cfg...)
+	order := valueobject.NewOrder("", "")
+	if r.OrderBy != nil {
+		order = valueobject.NewOrder(r.OrderBy.Field, r.OrderBy.Direction)
+	}
+
+	if order.Field() == valueobject.OrderByDistance && r.Location == nil {
+		return valueobject.ListRequest{}, valueobject.ErrDistanceOrderWithoutOrigin
+	}
+
+	after, afterErr := parseCursor("after", r.After, order)
+	if afterErr != nil {
+		return valueobject.ListRequest{}, afterErr
+	}
+
+	before, beforeErr := parseCursor("before", r.Before, order)
+	if beforeErr != nil {
+		return valueobject.ListRequest{}, beforeErr
+	}
+
+	pagination, paginationErr := valueobject.NewPagination(r.First, r.Last, after, before)
+	if paginationErr != nil {
+		return valueobject.ListRequest{}, paginationErr
+	}
+
+	cfg = append(cfg, valueobject.WithOrder(order), valueobject.WithPagination(pagination))
+
+	return valueobject.NewListRequest(cfg...), nil
+}
+
+// parseCursor decodes the cursor given in the pagination field, the invalid cursor is reported as the error of the field
+func parseCursor(field, value string, order valueobject.Order) (*valueobject.Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	cursor, err := decodeCursor(value, order)
+	if err != nil {
+		return nil, validation.Errors{validation.NewFieldError(field, err)}
+	}
+
+	return &cursor, nil
+}
+
+// decodeCursor checks the value of the cursor as well, so every repository rejects the malformed cursor the same way
+func decodeCursor(value string, order valueobject.Order) (valueobject.Cursor, error) {
+	cursor, err := valueobject.DecodeCursor(value)
+	if err != nil {
+		return valueobject.Cursor{}, valueobject.ErrInvalidCursor
+	}
+
+	if cursor.Field != order.Field() {
+		return valueobject.Cursor{}, ErrCursorOrderMismatch
+	}
+
+	switch cursor.Field {
+	case valueobject.OrderByStartDate, valueobject.OrderByCreatedAt:
+		_, err = cursor.TimeValue()
+	case valueobject.OrderByDistance:
+		_, err = cursor.FloatValue()
+	}
+
+	if err != nil {
+		return valueobject.Cursor{}, valueobject.ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+func convertParticipantsRequest(r ParticipantsRequest) (event.ParticipantsRequest, error) {
+	request := event.ParticipantsRequest{Status: r.Status, Limit: valueobject.DefaultPageSize}
+
+	if r.First != nil {
+		if *r.First < 0 || *r.First > valueobject.MaxPageSize {
+			return event.ParticipantsRequest{}, valueobject.ErrInvalidPageSize
+		}
+
+		request.Limit = *r.First
+	}
+
+	if r.After != "" {
+		cursor, err := valueobject.DecodeParticipantCursor(r.After)
+		if err != nil {
+			return event.ParticipantsRequest{}, validation.Errors{validation.NewFieldError("after", valueobject.ErrInvalidCursor)}
+		}
+
+		request.After = &cursor
+	}
+
+	return request, nil
 }
@@ -0,0 +1,49 @@
+package eventremover
+
+import (
+	"event-service/internal/domain/event"
+	"event-service/internal/services"
+)
+
+type Configuration func(*EventRemover) error
+
+func WithUpdaterRepository(updater event.Updater) Configuration {
+	return func(er *EventRemover) error {
+		er.updater = updater
+
+		return nil
+	}
+}
+
+func WithRemoverRepository(remover event.Remover) Configuration {
+	return func(er *EventRemover) error {
+		er.remover = remover
+
+		return nil
+	}
+}
+
+func WithFinderRepository(finder event.Finder) Configuration {
+	return func(er *EventRemover) error {
+		er.finder = finder
+
+		return nil
+	}
+}
+
+func WithObservers(eventType EventType, observers ...Observer) Configuration {
+	return func(er *EventRemover) error {
+		er.observersList[eventType] = append(er.observersList[eventType], observers...)
+
+		return nil
+	}
+}
+
+// WithTransactor runs the changes together with the observers in a single transaction
+func WithTransactor(transactor services.Transactor) Configuration {
+	return func(er *EventRemover) error {
+		er.transactor = transactor
+
+		return nil
+	}
+}
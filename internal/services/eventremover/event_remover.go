@@ -0,0 +1,155 @@
+package eventremover
+
+import (
+	"context"
+
+	"event-service/internal/auth"
+	"event-service/internal/domain/event"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
+	"event-service/internal/services"
+	"event-service/internal/tracing"
+
+	"github.com/pkg/errors"
+)
+
+var ServiceName = "event remover"
+
+type EventType string
+
+const (
+	EventCancelled EventType = "EventCancelled"
+	EventDeleted   EventType = "EventDeleted"
+)
+
+// Handler ends the lifecycle of the event, cancelled events are kept with their participants
+// while deleted ones are removed permanently together with their invitations
+type Handler interface {
+	CancelEvent(ctx context.Context, id, reason string) (*aggregate.Event, error)
+	DeleteEvent(ctx context.Context, id string) error
+}
+
+type Observer interface {
+	Notify(context.Context, aggregate.Event) error
+}
+
+type EventRemover struct {
+	updater       event.Updater
+	remover       event.Remover
+	finder        event.Finder
+	transactor    services.Transactor
+	observersList map[EventType][]Observer
+}
+
+func NewEventRemover(configuration ...Configuration) (*EventRemover, error) {
+	er := &EventRemover{
+		observersList: map[EventType][]Observer{},
+	}
+
+	for _, cfg := range configuration {
+		if err := cfg(er); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := er.validateRequiredResources(); err != nil {
+		return nil, err
+	}
+
+	return er, nil
+}
+
+func (er EventRemover) validateRequiredResources() error {
+	if er.updater == nil {
+		return services.NewErrResourceIsRequired(ServiceName, "event updater repository")
+	}
+
+	if er.remover == nil {
+		return services.NewErrResourceIsRequired(ServiceName, "event remover repository")
+	}
+
+	if er.finder == nil {
+		return services.NewErrResourceIsRequired(ServiceName, "event finder repository")
+	}
+
+	return nil
+}
+
+// CancelEvent moves the event into the cancelled status, only the organizer can cancel the event
+func (er EventRemover) CancelEvent(ctx context.Context, id, reason string) (_ *aggregate.Event, err error) {
+	ctx, span := tracing.Start(ctx, "eventremover.CancelEvent")
+	defer tracing.End(span, &err)
+
+	ea, findErr := er.findManagedEvent(ctx, id)
+	if findErr != nil {
+		return nil, findErr
+	}
+
+	if err := ea.Cancel(reason); err != nil {
+		return nil, err
+	}
+
+	if err := services.RunInTransaction(ctx, er.transactor, func(ctx context.Context) error {
+		if err := er.updater.Update(ctx, ea); err != nil {
+			return errors.Wrap(err, "cancelling event failed")
+		}
+
+		return er.runObservers(ctx, EventCancelled, *ea)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ea, nil
+}
+
+// DeleteEvent removes the event with all of its invitations, only the organizer can delete the event
+func (er EventRemover) DeleteEvent(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "eventremover.DeleteEvent")
+	defer tracing.End(span, &err)
+
+	ea, findErr := er.findManagedEvent(ctx, id)
+	if findErr != nil {
+		return findErr
+	}
+
+	return services.RunInTransaction(ctx, er.transactor, func(ctx context.Context) error {
+		if err := er.remover.Delete(ctx, ea); err != nil {
+			return errors.Wrap(err, "deleting event failed")
+		}
+
+		return er.runObservers(ctx, EventDeleted, *ea)
+	})
+}
+
+func (er EventRemover) findManagedEvent(ctx context.Context, id string) (*aggregate.Event, error) {
+	actor, authErr := auth.ActorFromContext(ctx)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	eventID, idErr := services.ParseID(id)
+	if idErr != nil {
+		return nil, idErr
+	}
+
+	ea, findErr := er.finder.FindByExternalID(ctx, eventID)
+	if findErr != nil {
+		return nil, errors.Wrap(findErr, "event not found")
+	}
+
+	if err := policy.CanManageEvent(ea, actor); err != nil {
+		return nil, err
+	}
+
+	return ea, nil
+}
+
+func (er EventRemover) runObservers(ctx context.Context, eventType EventType, ea aggregate.Event) error {
+	for _, observer := range er.observersList[eventType] {
+		if err := observer.Notify(ctx, ea); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package eventremover
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-service/internal/auth"
+	"event-service/internal/database/inmemmory/repository"
+	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/common/valueobject"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
+
+	"github.com/google/uuid"
+)
+
+type mockObserver struct {
+	notified *[]aggregate.Event
+}
+
+func (m mockObserver) Notify(_ context.Context, e aggregate.Event) error {
+	*m.notified = append(*m.notified, e)
+
+	return nil
+}
+
+func newEventMock() aggregate.Event {
+	registration, _ := valueobject.Period{}.WithStartAndEndDate(time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour))
+
+	return aggregate.Event{
+		UserID: uuid.New(),
+		Event: &entity.Event{
+			ExternalID: uuid.New(),
+			Name:       "Event",
+			Capacity:   10,
+			Status:     entity.EventStatusScheduled,
+		},
+		RegistrationPeriod: registration,
+	}
+}
+
+func newRemover(ea aggregate.Event) (*EventRemover, *repository.EventsStorage, *repository.InvitationsStorage, *[]aggregate.Event) {
+	events := repository.NewEventsStorage()
+	invitations := repository.NewLinkedInvitationsStorage(events)
+	_ = events.Add(context.Background(), &ea)
+
+	notified := &[]aggregate.Event{}
+	er, _ := NewEventRemover(
+		WithUpdaterRepository(events),
+		WithRemoverRepository(events),
+		WithFinderRepository(events),
+		WithObservers(EventCancelled, mockObserver{notified}),
+		WithObservers(EventDeleted, mockObserver{notified}),
+	)
+
+	return er, events, invitations, notified
+}
+
+func TestEventRemover_CancelEvent(t *testing.T) {
+	mockEvent := newEventMock()
+
+	tests := []struct {
+		name    string
+		actor   uuid.UUID
+		cancel  int
+		wantErr error
+	}{
+		{
+			name:   "organizer cancels the event",
+			actor:  mockEvent.UserID,
+			cancel: 1,
+		},
+		{
+			name:    "event cannot be cancelled twice",
+			actor:   mockEvent.UserID,
+			cancel:  2,
+			wantErr: aggregate.ErrEventCancelled,
+		},
+		{
+			name:    "other user cannot cancel the event",
+			actor:   uuid.New(),
+			cancel:  1,
+			wantErr: policy.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			er, events, _, notified := newRemover(mockEvent)
+			ctx := auth.ContextWithIdentity(context.Background(), auth.Identity{UserID: tt.actor})
+
+			var err error
+			for i := 0; i < tt.cancel; i++ {
+				_, err = er.CancelEvent(ctx, mockEvent.Event.ExternalID.String(), "bad weather")
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CancelEvent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			stored, _ := events.FindByExternalID(ctx, mockEvent.Event.ExternalID)
+			if !stored.IsCancelled() || stored.Event.CancellationReason != "bad weather" {
+				t.Errorf("CancelEvent() stored event = %v, want cancelled", stored.Event)
+			}
+
+			if len(*notified) != 1 {
+				t.Errorf("CancelEvent() notified %d observers, want 1", len(*notified))
+			}
+		})
+	}
+}
+
+func TestEventRemover_DeleteEvent(t *testing.T) {
+	mockEvent := newEventMock()
+	participant := uuid.New()
+
+	tests := []struct {
+		name    string
+		actor   uuid.UUID
+		wantErr error
+	}{
+		{
+			name:  "organizer deletes the event with its invitations",
+			actor: mockEvent.UserID,
+		},
+		{
+			name:    "other user cannot delete the event",
+			actor:   participant,
+			wantErr: policy.ErrForbidden,
+		},
+		{
+			name:    "anonymous user cannot delete the event",
+			wantErr: auth.ErrUnauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			er, events, invitations, _ := newRemover(mockEvent)
+			ctx := context.Background()
+
+			ea, _ := events.FindByExternalID(ctx, mockEvent.Event.ExternalID)
+			ia, _ := aggregate.NewInvitation(ea, participant)
+			_ = invitations.Add(ia)
+
+			if tt.actor != uuid.Nil {
+				ctx = auth.ContextWithIdentity(ctx, auth.Identity{UserID: tt.actor})
+			}
+
+			err := er.DeleteEvent(ctx, mockEvent.Event.ExternalID.String())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteEvent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			_, findErr := events.FindByExternalID(ctx, mockEvent.Event.ExternalID)
+			found, _ := invitations.FindBy(ctx, mockEvent.Event.ExternalID, participant)
+
+			if deleted := tt.wantErr == nil; deleted != (findErr != nil) || deleted != (found == nil) {
+				t.Errorf("DeleteEvent() event found error = %v, invitation = %v, deleted %v", findErr, found, deleted)
+			}
+		})
+	}
+}
+
+func TestEventRemover_validateRequiredResources(t *testing.T) {
+	storage := repository.NewEventsStorage()
+
+	tests := []struct {
+		name    string
+		er      EventRemover
+		wantErr bool
+	}{
+		{
+			name:    "required fields added to event service",
+			er:      EventRemover{updater: storage, remover: storage, finder: storage},
+			wantErr: false,
+		},
+		{
+			name:    "no updater repository",
+			er:      EventRemover{remover: storage, finder: storage},
+			wantErr: true,
+		},
+		{
+			name:    "no remover repository",
+			er:      EventRemover{updater: storage, finder: storage},
+			wantErr: true,
+		},
+		{
+			name:    "no finder repository",
+			er:      EventRemover{updater: storage, remover: storage},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.er.validateRequiredResources(); (err != nil) != tt.wantErr {
+				t.Errorf("validateRequiredResources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package eventsubscription
+
+import (
+	"event-service/internal/domain/event"
+	"event-service/internal/pubsub"
+)
+
+type Configuration func(s *EventSubscription) error
+
+func WithFinderRepository(finder event.Finder) Configuration {
+	return func(s *EventSubscription) error {
+		s.finder = finder
+
+		return nil
+	}
+}
+
+// WithSubscriber delivers messages published by the observers of the event and invitation services
+func WithSubscriber(subscriber pubsub.Subscriber) Configuration {
+	return func(s *EventSubscription) error {
+		s.subscriber = subscriber
+
+		return nil
+	}
+}
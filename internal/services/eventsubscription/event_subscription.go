@@ -0,0 +1,277 @@
+package eventsubscription
+
+import (
+	"context"
+	"encoding/json"
+
+	"event-service/internal/auth"
+	"event-service/internal/domain/event"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
+	"event-service/internal/exchange/event"
+	"event-service/internal/exchange/eventcancel"
+	"event-service/internal/exchange/eventdelete"
+	"event-service/internal/exchange/eventinvitation"
+	"event-service/internal/pubsub"
+	"event-service/internal/services"
+	"event-service/internal/tracing"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+var ServiceName = "event subscription"
+
+var (
+	// eventTopics carry messages with the changed or deleted event
+	eventTopics = []string{eventupdate.ExchangeKey, eventcancel.ExchangeKey, eventdelete.ExchangeKey}
+	// participantChanges are the invitation transitions changing participants or the waitlist of the event
+	participantChanges = []eventinvitation.MessageType{
+		eventinvitation.UserAccepted,
+		eventinvitation.UserJoined,
+		eventinvitation.UserRemoved,
+		eventinvitation.UserWaitlisted,
+		eventinvitation.UserPromoted,
+		eventinvitation.UserLeft,
+	}
+)
+
+// Handler streams changes of the events to the subscribers until the context is done. Subscribers receive
+// the current state of the event fetched after the change, events are streamed only to users who can see them
+type Handler interface {
+	EventUpdated(ctx context.Context, eventID string) (<-chan *aggregate.Event, error)
+	ParticipantsChanged(ctx context.Context, eventID string) (<-chan Change, error)
+	// MyInvitations streams changes of the invitations of the user, which defaults to the authenticated one
+	MyInvitations(ctx context.Context, user string) (<-chan Change, error)
+}
+
+// Change is the invitation transition together with the event after it
+type Change struct {
+	Type       eventinvitation.MessageType
+	User       uuid.UUID
+	Waitlisted bool
+	Event      *aggregate.Event
+}
+
+type EventSubscription struct {
+	finder     event.Finder
+	subscriber pubsub.Subscriber
+}
+
+func NewEventSubscription(configuration ...Configuration) (*EventSubscription, error) {
+	s := &EventSubscription{}
+
+	for _, cfg := range configuration {
+		if err := cfg(s); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.validateRequiredResources(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s EventSubscription) validateRequiredResources() error {
+	if s.finder == nil {
+		return services.NewErrResourceIsRequired(ServiceName, "event finder repository")
+	}
+
+	if s.subscriber == nil {
+		return services.NewErrResourceIsRequired(ServiceName, "subscriber")
+	}
+
+	return nil
+}
+
+func (s EventSubscription) EventUpdated(ctx context.Context, id string) (_ <-chan *aggregate.Event, err error) {
+	ctx, span := tracing.Start(ctx, "eventsubscription.EventUpdated")
+	defer tracing.End(span, &err)
+
+	eventID, actor, err := s.visibleEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return stream(ctx, s.subscriber, eventTopics, func(m pubsub.Message) (*aggregate.Event, bool, error) {
+		// cancellation and deletion messages carry the event the same way as updates
+		var changed eventupdate.Message
+		if err := json.Unmarshal(m.Body, &changed); err != nil || changed.Event == nil || changed.Event.Event == nil {
+			return nil, false, nil
+		}
+
+		if changed.Event.Event.ExternalID != eventID {
+			return nil, false, nil
+		}
+
+		if m.Topic == eventdelete.ExchangeKey {
+			return nil, false, event.ErrNotFound
+		}
+
+		ea, err := s.findVisible(ctx, eventID, actor)
+
+		return ea, err == nil, err
+	})
+}
+
+func (s EventSubscription) ParticipantsChanged(ctx context.Context, id string) (_ <-chan Change, err error) {
+	ctx, span := tracing.Start(ctx, "eventsubscription.ParticipantsChanged")
+	defer tracing.End(span, &err)
+
+	eventID, actor, err := s.visibleEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return stream(ctx, s.subscriber, topicsOf(participantChanges), func(m pubsub.Message) (Change, bool, error) {
+		message, ok := decodeInvitation(m)
+		if !ok || message.EventID != eventID {
+			return Change{}, false, nil
+		}
+
+		ea, err := s.findVisible(ctx, eventID, actor)
+		if err != nil {
+			return Change{}, false, err
+		}
+
+		return changeOf(message, ea), true, nil
+	})
+}
+
+func (s EventSubscription) MyInvitations(ctx context.Context, user string) (_ <-chan Change, err error) {
+	ctx, span := tracing.Start(ctx, "eventsubscription.MyInvitations")
+	defer tracing.End(span, &err)
+
+	actor, err := auth.ActorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := actor
+	if user != "" {
+		if userID, err = services.ParseID(user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := policy.CanActAs(userID, actor); err != nil {
+		return nil, err
+	}
+
+	return stream(ctx, s.subscriber, topicsOf(eventinvitation.MessageTypes), func(m pubsub.Message) (Change, bool, error) {
+		message, ok := decodeInvitation(m)
+		if !ok || message.UserID != userID {
+			return Change{}, false, nil
+		}
+
+		// invited users see the event of their invitation, the event removed in the meantime is skipped
+		ea, err := s.finder.FindByExternalID(ctx, message.EventID)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).Warn("cannot find event of the invitation change")
+
+			return Change{}, false, nil
+		}
+
+		return changeOf(message, ea), true, nil
+	})
+}
+
+// visibleEvent checks the event can be seen by the authenticated or anonymous user before subscribing
+func (s EventSubscription) visibleEvent(ctx context.Context, id string) (uuid.UUID, uuid.UUID, error) {
+	eventID, err := services.ParseID(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	actor, _ := auth.ActorFromContext(ctx)
+
+	if _, err := s.findVisible(ctx, eventID, actor); err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return eventID, actor, nil
+}
+
+func (s EventSubscription) findVisible(ctx context.Context, eventID, actor uuid.UUID) (*aggregate.Event, error) {
+	ea, err := s.finder.FindByExternalID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy.CanView(ea, actor); err != nil {
+		return nil, err
+	}
+
+	return ea, nil
+}
+
+// stream subscribes to the topics and sends messages converted by the function to the subscriber, messages
+// the function skips are left out. The stream ends when the function fails, e.g. the event has been removed
+// or the user cannot see it anymore, the subscription of the broker is cancelled together with the stream
+func stream[T any](ctx context.Context, subscriber pubsub.Subscriber, topics []string, convert func(pubsub.Message) (T, bool, error)) (<-chan T, error) {
+	subscriptionCtx, cancel := context.WithCancel(ctx)
+
+	messages, err := subscriber.Subscribe(subscriptionCtx, topics...)
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	updates := make(chan T)
+
+	go func() {
+		defer close(updates)
+		defer cancel()
+
+		for m := range messages {
+			update, ok, err := convert(m)
+			if err != nil {
+				log.WithContext(ctx).WithError(err).Info("subscription ended")
+
+				return
+			}
+
+			if !ok {
+				continue
+			}
+
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+func decodeInvitation(m pubsub.Message) (eventinvitation.Message, bool) {
+	var message eventinvitation.Message
+	if err := json.Unmarshal(m.Body, &message); err != nil {
+		return message, false
+	}
+
+	return message, true
+}
+
+func changeOf(m eventinvitation.Message, ea *aggregate.Event) Change {
+	return Change{
+		Type:       m.Type,
+		User:       m.UserID,
+		Waitlisted: m.Waitlisted,
+		Event:      ea,
+	}
+}
+
+func topicsOf(types []eventinvitation.MessageType) []string {
+	topics := make([]string, 0, len(types))
+	for _, t := range types {
+		topics = append(topics, string(t))
+	}
+
+	return topics
+}
@@ -0,0 +1,318 @@
+package eventsubscription
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"event-service/internal/auth"
+	"event-service/internal/database/inmemmory/repository"
+	"event-service/internal/domain/common/entity"
+	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
+	"event-service/internal/exchange/event"
+	"event-service/internal/exchange/eventdelete"
+	"event-service/internal/exchange/eventinvitation"
+	"event-service/internal/pubsub"
+	"event-service/internal/services"
+
+	"github.com/google/uuid"
+)
+
+func newEventMock(public bool) *aggregate.Event {
+	return &aggregate.Event{
+		UserID: uuid.New(),
+		Event: &entity.Event{
+			ExternalID: uuid.New(),
+			Name:       "Event",
+			Capacity:   10,
+			Public:     public,
+		},
+	}
+}
+
+func newSubscription(events ...*aggregate.Event) (*EventSubscription, *repository.EventsStorage, *pubsub.Broker) {
+	storage := repository.NewEventsStorage()
+	for _, ea := range events {
+		_ = storage.Add(context.Background(), ea)
+	}
+
+	broker := pubsub.NewBroker()
+	s, _ := NewEventSubscription(WithFinderRepository(storage), WithSubscriber(broker))
+
+	return s, storage, broker
+}
+
+func publishUpdate(t *testing.T, broker *pubsub.Broker, ea *aggregate.Event) {
+	t.Helper()
+
+	body, _ := json.Marshal(eventupdate.Message{Event: ea})
+	if err := broker.Publish(context.Background(), eventupdate.ExchangeKey, body); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+}
+
+func publishInvitation(t *testing.T, broker *pubsub.Broker, m eventinvitation.Message) {
+	t.Helper()
+
+	body, _ := json.Marshal(m)
+	if err := broker.Publish(context.Background(), string(m.Type), body); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+}
+
+func receive[T any](t *testing.T, updates <-chan T) T {
+	t.Helper()
+
+	select {
+	case u := <-updates:
+		return u
+	case <-time.After(time.Second):
+		t.Fatal("no update received")
+	}
+
+	var zero T
+
+	return zero
+}
+
+func TestNewEventSubscription(t *testing.T) {
+	storage := repository.NewEventsStorage()
+
+	tests := []struct {
+		name    string
+		cfg     []Configuration
+		wantErr bool
+	}{
+		{
+			name: "all resources",
+			cfg:  []Configuration{WithFinderRepository(storage), WithSubscriber(pubsub.NewBroker())},
+		},
+		{
+			name:    "missing subscriber",
+			cfg:     []Configuration{WithFinderRepository(storage)},
+			wantErr: true,
+		},
+		{
+			name:    "missing finder",
+			cfg:     []Configuration{WithSubscriber(pubsub.NewBroker())},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEventSubscription(tt.cfg...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewEventSubscription() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventSubscription_EventUpdated(t *testing.T) {
+	ea, other := newEventMock(true), newEventMock(true)
+	s, storage, broker := newSubscription(ea, other)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := s.EventUpdated(ctx, ea.Event.ExternalID.String())
+	if err != nil {
+		t.Fatalf("EventUpdated() error = %v", err)
+	}
+
+	// messages of other events are skipped, the subscriber receives the event fetched after the change
+	publishUpdate(t, broker, other)
+
+	ea.Event.Name = "Renamed"
+	_ = storage.Update(context.Background(), ea)
+	publishUpdate(t, broker, &aggregate.Event{Event: &entity.Event{ExternalID: ea.Event.ExternalID}})
+
+	if got := receive(t, updates); got.Event.Name != "Renamed" {
+		t.Errorf("EventUpdated() name = %q, want %q", got.Event.Name, "Renamed")
+	}
+
+	cancel()
+
+	if _, open := <-updates; open {
+		t.Error("EventUpdated() stream is not closed when the context is done")
+	}
+}
+
+// recordingSubscriber keeps context of the subscription to the broker
+type recordingSubscriber struct {
+	pubsub.Subscriber
+	ctx context.Context
+}
+
+func (r *recordingSubscriber) Subscribe(ctx context.Context, topics ...string) (<-chan pubsub.Message, error) {
+	r.ctx = ctx
+
+	return r.Subscriber.Subscribe(ctx, topics...)
+}
+
+func TestEventSubscription_EventUpdatedEndsSubscriptionOfRemovedEvent(t *testing.T) {
+	ea := newEventMock(true)
+	_, storage, broker := newSubscription(ea)
+	subscriber := &recordingSubscriber{Subscriber: broker}
+	s, _ := NewEventSubscription(WithFinderRepository(storage), WithSubscriber(subscriber))
+
+	updates, err := s.EventUpdated(context.Background(), ea.Event.ExternalID.String())
+	if err != nil {
+		t.Fatalf("EventUpdated() error = %v", err)
+	}
+
+	_ = storage.Delete(context.Background(), ea)
+	publishUpdate(t, broker, ea)
+
+	if _, open := <-updates; open {
+		t.Fatal("EventUpdated() stream is not closed when the event is removed")
+	}
+
+	select {
+	case <-subscriber.ctx.Done():
+	case <-time.After(time.Second):
+		t.Error("EventUpdated() subscription of the broker is not cancelled when the stream ends")
+	}
+}
+
+func TestEventSubscription_EventUpdatedEndsOnDeletion(t *testing.T) {
+	ea, other := newEventMock(true), newEventMock(true)
+	s, _, broker := newSubscription(ea, other)
+
+	updates, err := s.EventUpdated(context.Background(), ea.Event.ExternalID.String())
+	if err != nil {
+		t.Fatalf("EventUpdated() error = %v", err)
+	}
+
+	// the deletion ends the stream even before the event is removed from the repository of the replica
+	for _, deleted := range []*aggregate.Event{other, ea} {
+		body, _ := json.Marshal(eventdelete.Message{Type: eventdelete.MessageType, Event: deleted})
+		if err := broker.Publish(context.Background(), eventdelete.ExchangeKey, body); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	select {
+	case _, open := <-updates:
+		if open {
+			t.Error("EventUpdated() sent an update of the deleted event")
+		}
+	case <-time.After(time.Second):
+		t.Error("EventUpdated() stream is not closed when the event is deleted")
+	}
+}
+
+func TestEventSubscription_EventUpdatedRequiresAccess(t *testing.T) {
+	private := newEventMock(false)
+	s, _, _ := newSubscription(private)
+
+	tests := []struct {
+		name    string
+		actor   uuid.UUID
+		id      string
+		wantErr error
+	}{
+		{
+			name:  "organizer",
+			actor: private.UserID,
+			id:    private.Event.ExternalID.String(),
+		},
+		{
+			name:    "stranger",
+			actor:   uuid.New(),
+			id:      private.Event.ExternalID.String(),
+			wantErr: policy.ErrForbidden,
+		},
+		{
+			name:    "anonymous",
+			id:      private.Event.ExternalID.String(),
+			wantErr: policy.ErrForbidden,
+		},
+		{
+			name:    "invalid id",
+			actor:   private.UserID,
+			id:      "event",
+			wantErr: services.ErrInvalidID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tt.actor != uuid.Nil {
+				ctx = auth.ContextWithIdentity(ctx, auth.Identity{UserID: tt.actor})
+			}
+
+			if _, err := s.EventUpdated(ctx, tt.id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("EventUpdated() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventSubscription_ParticipantsChanged(t *testing.T) {
+	ea := newEventMock(true)
+	s, _, broker := newSubscription(ea)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, err := s.ParticipantsChanged(ctx, ea.Event.ExternalID.String())
+	if err != nil {
+		t.Fatalf("ParticipantsChanged() error = %v", err)
+	}
+
+	user := uuid.New()
+
+	// invitations do not change participants, transitions of other events are skipped
+	publishInvitation(t, broker, eventinvitation.Message{Type: eventinvitation.UserInvited, EventID: ea.Event.ExternalID, UserID: user})
+	publishInvitation(t, broker, eventinvitation.Message{Type: eventinvitation.UserJoined, EventID: uuid.New(), UserID: user})
+	publishInvitation(t, broker, eventinvitation.Message{Type: eventinvitation.UserWaitlisted, EventID: ea.Event.ExternalID, UserID: user, Waitlisted: true})
+
+	got := receive(t, changes)
+	if got.Type != eventinvitation.UserWaitlisted || got.User != user || !got.Waitlisted {
+		t.Errorf("ParticipantsChanged() = %+v, want waitlisted user %s", got, user)
+	}
+
+	if got.Event == nil || got.Event.Event.ExternalID != ea.Event.ExternalID {
+		t.Errorf("ParticipantsChanged() event = %v, want %s", got.Event, ea.Event.ExternalID)
+	}
+}
+
+func TestEventSubscription_MyInvitations(t *testing.T) {
+	ea := newEventMock(false)
+	s, _, broker := newSubscription(ea)
+	user := uuid.New()
+
+	if _, err := s.MyInvitations(context.Background(), ""); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("MyInvitations() error = %v, want %v", err, auth.ErrUnauthenticated)
+	}
+
+	ctx, cancel := context.WithCancel(auth.ContextWithIdentity(context.Background(), auth.Identity{UserID: user}))
+	defer cancel()
+
+	if _, err := s.MyInvitations(ctx, uuid.NewString()); !errors.Is(err, policy.ErrForbidden) {
+		t.Errorf("MyInvitations() of the other user error = %v, want %v", err, policy.ErrForbidden)
+	}
+
+	changes, err := s.MyInvitations(ctx, "")
+	if err != nil {
+		t.Fatalf("MyInvitations() error = %v", err)
+	}
+
+	// invitations of other users and of removed events are skipped
+	publishInvitation(t, broker, eventinvitation.Message{Type: eventinvitation.UserInvited, EventID: ea.Event.ExternalID, UserID: uuid.New()})
+	publishInvitation(t, broker, eventinvitation.Message{Type: eventinvitation.UserInvited, EventID: uuid.New(), UserID: user})
+	publishInvitation(t, broker, eventinvitation.Message{Type: eventinvitation.UserInvited, EventID: ea.Event.ExternalID, UserID: user})
+
+	got := receive(t, changes)
+	if got.Type != eventinvitation.UserInvited || got.User != user || got.Event.Event.ExternalID != ea.Event.ExternalID {
+		t.Errorf("MyInvitations() = %+v, want invitation of %s to %s", got, user, ea.Event.ExternalID)
+	}
+}
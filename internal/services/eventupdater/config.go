@@ -2,6 +2,7 @@ package eventupdater
 
 import (
 	"event-service/internal/domain/event"
+	"event-service/internal/services"
 )
 
 type Configuration func(*EventUpdater) error
@@ -29,3 +30,12 @@ func WithObservers(observers ...Observer) Configuration {
 		return nil
 	}
 }
+
+// WithTransactor runs the changes together with the observers in a single transaction
+func WithTransactor(transactor services.Transactor) Configuration {
+	return func(ec *EventUpdater) error {
+		ec.transactor = transactor
+
+		return nil
+	}
+}
@@ -4,16 +4,23 @@ import (
 	"context"
 	"time"
 
+	"event-service/internal/auth"
+	"event-service/internal/domain/common/validation"
 	"event-service/internal/domain/common/valueobject"
 	"event-service/internal/domain/event"
 	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
 	"event-service/internal/services"
+	"event-service/internal/tracing"
 
-	"github.com/google/uuid"
+	"github.com/markphelps/optional"
 	"github.com/pkg/errors"
 )
 
-var ServiceName = "event updater"
+var (
+	ServiceName                = "event updater"
+	ErrEmptyOccurrenceOverride = errors.New("occurrence must be either cancelled or moved")
+)
 
 type Handler interface {
 	UpdateEvent(context.Context, Request) (*aggregate.Event, error)
@@ -26,6 +33,7 @@ type Observer interface {
 type EventUpdater struct {
 	updater       event.Updater
 	finder        event.Finder
+	transactor    services.Transactor
 	observersList []Observer
 }
 
@@ -57,8 +65,16 @@ func (ec EventUpdater) validateRequiredResources() error {
 	return nil
 }
 
-func (ec EventUpdater) UpdateEvent(ctx context.Context, r Request) (*aggregate.Event, error) {
-	id, idErr := uuid.Parse(r.ID)
+func (ec EventUpdater) UpdateEvent(ctx context.Context, r Request) (_ *aggregate.Event, err error) {
+	ctx, span := tracing.Start(ctx, "eventupdater.UpdateEvent")
+	defer tracing.End(span, &err)
+
+	actor, authErr := auth.ActorFromContext(ctx)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	id, idErr := services.ParseID(r.ID)
 	if idErr != nil {
 		return nil, idErr
 	}
@@ -68,72 +84,167 @@ func (ec EventUpdater) UpdateEvent(ctx context.Context, r Request) (*aggregate.E
 		return nil, errors.Wrap(findErr, "event not found")
 	}
 
+	if err := policy.CanManageEvent(ia, actor); err != nil {
+		return nil, err
+	}
+
+	if ia.IsCancelled() {
+		return nil, aggregate.ErrEventCancelled
+	}
+
+	// the client must have seen the latest state of the event, the repository repeats the check atomically
+	if err := ia.CheckVersion(r.Version); err != nil {
+		return nil, err
+	}
+
 	if err := updateAggregateWithRequest(ia, r); err != nil {
 		return nil, errors.Wrap(err, "cannot convert request to entry")
 	}
 
-	if err := ec.updater.Update(ctx, ia); err != nil {
-		return nil, errors.Wrap(err, "creating new event failed")
-	}
+	ia.Revise()
 
-	for _, observer := range ec.observersList {
-		if err := observer.Notify(ctx, *ia); err != nil {
-			return nil, err
+	if err := services.RunInTransaction(ctx, ec.transactor, func(ctx context.Context) error {
+		if err := ec.updater.Update(ctx, ia); err != nil {
+			return errors.Wrap(err, "creating new event failed")
 		}
+
+		for _, observer := range ec.observersList {
+			if err := observer.Notify(ctx, *ia); err != nil {
+				return err
+			}
+		}
+
+		if len(ec.observersList) == 0 {
+			return nil
+		}
+
+		// observers can change the event further, e.g. promote the waitlist, so the returned event has the final version
+		reloaded, findErr := ec.finder.FindByExternalID(ctx, ia.Event.ExternalID)
+		if findErr != nil {
+			return errors.Wrap(findErr, "cannot reload updated event")
+		}
+
+		ia = reloaded
+
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	return ia, nil
 }
 
+// Request holds the changes of the event, the fields that are not present stay unchanged
 type Request struct {
 	ID                    string
-	Name, Description     string
-	Capacity              int
-	Longitude             float64
-	Latitude              float64
+	Version               uint // version of the event the changes are based on
+	Name, Description     optional.String
+	Capacity              optional.Int
+	Longitude             optional.Float64
+	Latitude              optional.Float64
 	DateStart             *time.Time
 	DateEnd               *time.Time
 	DateRegistrationStart *time.Time
 	DateRegistrationEnd   *time.Time
-	Public                *bool
+	Public                optional.Bool
+	Recurrence            *RecurrenceRequest
+	Occurrence            *OccurrenceRequest
 }
 
-func updateAggregateWithRequest(a *aggregate.Event, r Request) (err error) {
-	if r.Name != "" {
-		a.Event.Name = r.Name
-	}
+// RecurrenceRequest replaces recurrence of the event, the empty rule makes the event a single one again
+type RecurrenceRequest struct {
+	Rule       string
+	Exceptions []time.Time
+}
 
-	if r.Description != "" {
-		a.Event.Description = r.Description
-	}
+// OccurrenceRequest overrides a single occurrence of the recurring event starting at OriginalStart
+type OccurrenceRequest struct {
+	OriginalStart time.Time
+	Cancel        bool
+	DateStart     *time.Time
+	DateEnd       *time.Time
+}
 
-	if r.Capacity != 0 {
-		a.Event.Capacity = r.Capacity
-	}
+func updateAggregateWithRequest(a *aggregate.Event, r Request) error {
+	r.Name.If(func(name string) { a.Event.Name = name })
+	r.Description.If(func(description string) { a.Event.Description = description })
+	r.Capacity.If(func(capacity int) { a.Event.Capacity = capacity })
+	r.Public.If(func(public bool) { a.Event.Public = public })
+
+	if r.Latitude.Present() || r.Longitude.Present() {
+		// the coordinate that is not sent keeps its value
+		location := aggregate.Location{}
+		if a.Location != nil {
+			location = *a.Location
+		}
 
-	if r.Public != nil {
-		a.Event.Public = *r.Public
+		location.Spot = valueobject.NewLocation(r.Latitude.OrElse(location.Spot.Lat()), r.Longitude.OrElse(location.Spot.Long()))
+		a.Location = &location
 	}
 
-	if r.Latitude != 0 && r.Longitude != 0 {
-		a.Location = &aggregate.Location{
-			Spot: valueobject.NewLocation(r.Latitude, r.Longitude),
-		}
-	}
+	v := validation.Validator{}
 
 	if r.DateStart != nil && r.DateEnd != nil {
-		a.EventPeriod, err = valueobject.EventPeriod{}.WithStartAndEndDate(*r.DateStart, *r.DateEnd)
-		if err != nil {
-			return err
+		period, err := valueobject.EventPeriod{}.WithStartAndEndDate(*r.DateStart, *r.DateEnd)
+		v.Add("eventDate", err)
+		aggregate.ValidateStartDate(&v, *r.DateStart, time.Now())
+
+		if err == nil {
+			a.EventPeriod = period
 		}
 	}
 
 	if r.DateRegistrationStart != nil && r.DateRegistrationEnd != nil {
-		a.RegistrationPeriod, err = valueobject.Period{}.WithStartAndEndDate(*r.DateRegistrationStart, *r.DateRegistrationEnd)
-		if err != nil {
-			return err
+		period, err := valueobject.Period{}.WithStartAndEndDate(*r.DateRegistrationStart, *r.DateRegistrationEnd)
+		v.Add("registrationDate", err)
+
+		if err == nil {
+			a.RegistrationPeriod = period
 		}
 	}
 
+	if r.Recurrence != nil {
+		recurrence, err := valueobject.NewRecurrence(r.Recurrence.Rule, r.Recurrence.Exceptions...)
+		v.Add("recurrence.rule", err)
+
+		// overrides point to occurrences of the previous rule
+		if err == nil && recurrence.Rule() != a.Recurrence.Rule() {
+			a.Overrides = nil
+		}
+
+		if err == nil {
+			a.Recurrence = recurrence
+		}
+	}
+
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	if r.Occurrence != nil {
+		return overrideOccurrence(a, *r.Occurrence)
+	}
+
 	return nil
 }
+
+func overrideOccurrence(a *aggregate.Event, r OccurrenceRequest) error {
+	if r.Cancel {
+		return a.CancelOccurrence(r.OriginalStart)
+	}
+
+	if r.DateStart == nil || r.DateEnd == nil {
+		return ErrEmptyOccurrenceOverride
+	}
+
+	period, err := valueobject.EventPeriod{}.WithStartAndEndDate(*r.DateStart, *r.DateEnd)
+	if err != nil {
+		return err
+	}
+
+	return a.MoveOccurrence(r.OriginalStart, period)
+}
@@ -2,17 +2,21 @@ package eventupdater
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
 
+	"event-service/internal/auth"
 	"event-service/internal/database/inmemmory/repository"
 	"event-service/internal/domain/common/entity"
 	"event-service/internal/domain/common/valueobject"
 	"event-service/internal/domain/event"
 	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
 
 	"github.com/google/uuid"
+	"github.com/markphelps/optional"
 )
 
 type eventServiceFields struct {
@@ -36,6 +40,23 @@ func newValidEventServiceFields(ea ...aggregate.Event) eventServiceFields {
 	}
 }
 
+// copyEventMock copies the event entity so that expectations of one test case do not leak into others
+func copyEventMock(ea aggregate.Event) aggregate.Event {
+	e := *ea.Event
+	ea.Event = &e
+
+	return ea
+}
+
+// revisedEventMock copies the event expected after the update, every update revises the event and moves its version
+func revisedEventMock(ea aggregate.Event) aggregate.Event {
+	ea = copyEventMock(ea)
+	ea.Revise()
+	ea.Version++
+
+	return ea
+}
+
 type mockObserver struct{}
 
 func (m mockObserver) Notify(_ context.Context, _ aggregate.Event) error {
@@ -44,11 +65,12 @@ func (m mockObserver) Notify(_ context.Context, _ aggregate.Event) error {
 
 func TestEventUpdater_UpdateEvent(t *testing.T) {
 	eventID := uuid.New()
-	mockEventPeriod, _ := valueobject.EventPeriod{}.WithStartAndEndDate(time.Now().Add(time.Hour*-24), time.Now().Add(time.Hour*48))
-	mockPeriod, _ := valueobject.Period{}.WithStartAndEndDate(time.Now().Add(time.Hour*-24), time.Now().Add(time.Hour*48))
-	trueValue := true
+	mockEventPeriod, _ := valueobject.EventPeriod{}.WithStartAndEndDate(time.Now().Add(time.Hour*48), time.Now().Add(time.Hour*72))
+	mockPeriod, _ := valueobject.Period{}.WithStartAndEndDate(time.Now().Add(time.Hour*-24), time.Now().Add(time.Hour*24))
 	newDateStart := time.Now().Add(time.Hour * 240)
 	newDateEnd := time.Now().Add(time.Hour * 480)
+	newRegistrationStart := time.Now().Add(time.Hour)
+	mockPeriodStart := time.Now().Add(time.Hour * -24)
 
 	initialEvent := aggregate.Event{
 		ID:     1,
@@ -81,10 +103,10 @@ func TestEventUpdater_UpdateEvent(t *testing.T) {
 			fields: newValidEventServiceFields(initialEvent),
 			request: Request{
 				ID:   eventID.String(),
-				Name: "New Name",
+				Name: optional.NewString("New Name"),
 			},
 			want: func() aggregate.Event {
-				ia := initialEvent
+				ia := revisedEventMock(initialEvent)
 				ia.Event.Name = "New Name"
 
 				return ia
@@ -95,10 +117,10 @@ func TestEventUpdater_UpdateEvent(t *testing.T) {
 			fields: newValidEventServiceFields(initialEvent),
 			request: Request{
 				ID:          eventID.String(),
-				Description: "New Description",
+				Description: optional.NewString("New Description"),
 			},
 			want: func() aggregate.Event {
-				ia := initialEvent
+				ia := revisedEventMock(initialEvent)
 				ia.Event.Description = "New Description"
 
 				return ia
@@ -109,10 +131,10 @@ func TestEventUpdater_UpdateEvent(t *testing.T) {
 			fields: newValidEventServiceFields(initialEvent),
 			request: Request{
 				ID:       eventID.String(),
-				Capacity: 20,
+				Capacity: optional.NewInt(20),
 			},
 			want: func() aggregate.Event {
-				ia := initialEvent
+				ia := revisedEventMock(initialEvent)
 				ia.Event.Capacity = 20
 
 				return ia
@@ -123,11 +145,11 @@ func TestEventUpdater_UpdateEvent(t *testing.T) {
 			fields: newValidEventServiceFields(initialEvent),
 			request: Request{
 				ID:     eventID.String(),
-				Public: &trueValue,
+				Public: optional.NewBool(true),
 			},
 			want: func() aggregate.Event {
-				ia := initialEvent
-				ia.Event.Public = trueValue
+				ia := revisedEventMock(initialEvent)
+				ia.Event.Public = true
 
 				return ia
 			}(),
@@ -141,7 +163,7 @@ func TestEventUpdater_UpdateEvent(t *testing.T) {
 				DateEnd:   &newDateEnd,
 			},
 			want: func() aggregate.Event {
-				ia := initialEvent
+				ia := revisedEventMock(initialEvent)
 				ia.EventPeriod, _ = valueobject.EventPeriod{}.WithStartAndEndDate(newDateStart, newDateEnd)
 
 				return ia
@@ -152,12 +174,15 @@ func TestEventUpdater_UpdateEvent(t *testing.T) {
 			fields: newValidEventServiceFields(initialEvent),
 			request: Request{
 				ID:                    eventID.String(),
-				DateRegistrationStart: &newDateStart,
-				DateRegistrationEnd:   &newDateEnd,
+				DateStart:             &newDateStart,
+				DateEnd:               &newDateEnd,
+				DateRegistrationStart: &newRegistrationStart,
+				DateRegistrationEnd:   &newDateStart,
 			},
 			want: func() aggregate.Event {
-				ia := initialEvent
-				ia.RegistrationPeriod, _ = valueobject.Period{}.WithStartAndEndDate(newDateStart, newDateEnd)
+				ia := revisedEventMock(initialEvent)
+				ia.EventPeriod, _ = valueobject.EventPeriod{}.WithStartAndEndDate(newDateStart, newDateEnd)
+				ia.RegistrationPeriod, _ = valueobject.Period{}.WithStartAndEndDate(newRegistrationStart, newDateStart)
 
 				return ia
 			}(),
@@ -167,15 +192,97 @@ func TestEventUpdater_UpdateEvent(t *testing.T) {
 			fields: newValidEventServiceFields(),
 			request: Request{
 				ID:   eventID.String(),
-				Name: "New Name",
+				Name: optional.NewString("New Name"),
 			},
 			want: func() aggregate.Event {
-				ia := initialEvent
+				ia := revisedEventMock(initialEvent)
 				ia.Event.Name = "New Name"
 
 				return ia
 			}(),
 			wantErr: true,
+		}, {
+			name:   "update event capacity to zero",
+			fields: newValidEventServiceFields(initialEvent),
+			request: Request{
+				ID:       eventID.String(),
+				Capacity: optional.NewInt(0),
+			},
+			want: func() aggregate.Event {
+				ia := revisedEventMock(initialEvent)
+				ia.Event.Capacity = 0
+
+				return ia
+			}(),
+		}, {
+			name:   "clear event description",
+			fields: newValidEventServiceFields(initialEvent),
+			request: Request{
+				ID:          eventID.String(),
+				Description: optional.NewString(""),
+			},
+			want: func() aggregate.Event {
+				ia := revisedEventMock(initialEvent)
+				ia.Event.Description = ""
+
+				return ia
+			}(),
+		}, {
+			name:   "move event to the equator",
+			fields: newValidEventServiceFields(initialEvent),
+			request: Request{
+				ID:       eventID.String(),
+				Latitude: optional.NewFloat64(0),
+			},
+			want: func() aggregate.Event {
+				ia := revisedEventMock(initialEvent)
+				ia.Location = &aggregate.Location{ID: 1, Spot: valueobject.NewLocation(0, 10)}
+
+				return ia
+			}(),
+		}, {
+			name:   "negative capacity",
+			fields: newValidEventServiceFields(initialEvent),
+			request: Request{
+				ID:       eventID.String(),
+				Capacity: optional.NewInt(-1),
+			},
+			wantErr: true,
+		}, {
+			name:   "latitude out of range",
+			fields: newValidEventServiceFields(initialEvent),
+			request: Request{
+				ID:       eventID.String(),
+				Latitude: optional.NewFloat64(500),
+			},
+			wantErr: true,
+		}, {
+			name:   "event moved to the past",
+			fields: newValidEventServiceFields(initialEvent),
+			request: Request{
+				ID:        eventID.String(),
+				DateStart: &mockPeriodStart,
+				DateEnd:   &newDateEnd,
+			},
+			wantErr: true,
+		}, {
+			name:   "update based on outdated version",
+			fields: newValidEventServiceFields(initialEvent),
+			request: Request{
+				ID:      eventID.String(),
+				Version: 3,
+				Name:    optional.NewString("New Name"),
+			},
+			wantErr: true,
+		}, {
+			name:   "registration ending after the event starts",
+			fields: newValidEventServiceFields(initialEvent),
+			request: Request{
+				ID:                    eventID.String(),
+				DateRegistrationStart: &newRegistrationStart,
+				DateRegistrationEnd:   &newDateEnd,
+			},
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
@@ -185,7 +292,8 @@ func TestEventUpdater_UpdateEvent(t *testing.T) {
 				finder:        tt.fields.finder,
 				observersList: tt.fields.observersList,
 			}
-			got, err := ec.UpdateEvent(context.Background(), tt.request)
+			ctx := auth.ContextWithIdentity(context.Background(), auth.Identity{UserID: initialEvent.UserID})
+			got, err := ec.UpdateEvent(ctx, tt.request)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UpdateEvent() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -202,6 +310,154 @@ func TestEventUpdater_UpdateEvent(t *testing.T) {
 	}
 }
 
+// versionMovingObserver changes the event once more, e.g. the way the waitlist promotion does
+type versionMovingObserver struct {
+	storage *repository.EventsStorage
+}
+
+func (m versionMovingObserver) Notify(ctx context.Context, e aggregate.Event) error {
+	stored, err := m.storage.FindByExternalID(ctx, e.Event.ExternalID)
+	if err != nil {
+		return err
+	}
+
+	return m.storage.Update(ctx, stored)
+}
+
+func TestEventUpdater_UpdateEventReturnsVersionAfterObservers(t *testing.T) {
+	organizer := uuid.New()
+	initialEvent := aggregate.Event{
+		UserID:   organizer,
+		Event:    &entity.Event{ExternalID: uuid.New(), Name: "Initial Event Name", Capacity: 10},
+		Location: &aggregate.Location{},
+		Version:  3,
+	}
+	initialEvent.EventPeriod, _ = initialEvent.EventPeriod.WithStartAndDuration(time.Now().Add(24*time.Hour), time.Hour)
+
+	storage := repository.NewEventsStorage()
+	_ = storage.Add(context.Background(), &initialEvent)
+
+	ec := EventUpdater{
+		updater:       storage,
+		finder:        storage,
+		observersList: []Observer{versionMovingObserver{storage: storage}},
+	}
+
+	ctx := auth.ContextWithIdentity(context.Background(), auth.Identity{UserID: organizer})
+	got, err := ec.UpdateEvent(ctx, Request{ID: initialEvent.Event.ExternalID.String(), Version: 3, Capacity: optional.NewInt(20)})
+	if err != nil {
+		t.Fatalf("UpdateEvent() error = %v", err)
+	}
+
+	stored, _ := storage.FindByExternalID(context.Background(), initialEvent.Event.ExternalID)
+	if got.Version != stored.Version || got.Version != 5 {
+		t.Errorf("UpdateEvent() version = %d, stored %d, want %d", got.Version, stored.Version, 5)
+	}
+}
+
+func TestEventUpdater_UpdateEventRequiresAuthentication(t *testing.T) {
+	ec := EventUpdater{
+		updater: repository.NewEventsStorage(),
+		finder:  repository.NewEventsStorage(),
+	}
+
+	if _, err := ec.UpdateEvent(context.Background(), Request{ID: uuid.NewString(), Name: optional.NewString("New Name")}); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("UpdateEvent() error = %v, wantErr %v", err, auth.ErrUnauthenticated)
+	}
+}
+
+func TestEventUpdater_UpdateEventByOtherUser(t *testing.T) {
+	initialEvent := aggregate.Event{
+		UserID: uuid.New(),
+		Event: &entity.Event{
+			ExternalID: uuid.New(),
+			Name:       "Initial Event Name",
+		},
+	}
+	fields := newValidEventServiceFields(initialEvent)
+	ec := EventUpdater{
+		updater: fields.updater,
+		finder:  fields.finder,
+	}
+
+	ctx := auth.ContextWithIdentity(context.Background(), auth.Identity{UserID: uuid.New()})
+	if _, err := ec.UpdateEvent(ctx, Request{ID: initialEvent.Event.ExternalID.String(), Name: optional.NewString("New Name")}); !errors.Is(err, policy.ErrForbidden) {
+		t.Errorf("UpdateEvent() error = %v, wantErr %v", err, policy.ErrForbidden)
+	}
+}
+
+func TestEventUpdater_UpdateEventOccurrence(t *testing.T) {
+	start := time.Date(2023, 1, 3, 18, 0, 0, 0, time.UTC)
+	period, _ := valueobject.EventPeriod{}.WithStartAndDuration(start, 2*time.Hour)
+	recurrence, _ := valueobject.NewRecurrence("FREQ=WEEKLY;COUNT=4")
+	second, third := start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)
+	movedStart, movedEnd := third.Add(24*time.Hour), third.Add(26*time.Hour)
+
+	initialEvent := aggregate.Event{
+		UserID:      uuid.New(),
+		Event:       &entity.Event{ExternalID: uuid.New(), Name: "Weekly meetup"},
+		EventPeriod: period,
+		Recurrence:  recurrence,
+	}
+
+	tests := []struct {
+		name       string
+		occurrence OccurrenceRequest
+		want       []time.Time
+		wantErr    bool
+	}{
+		{
+			name:       "cancel single occurrence",
+			occurrence: OccurrenceRequest{OriginalStart: second, Cancel: true},
+			want:       []time.Time{start, third, start.AddDate(0, 0, 21)},
+		},
+		{
+			name:       "move single occurrence",
+			occurrence: OccurrenceRequest{OriginalStart: third, DateStart: &movedStart, DateEnd: &movedEnd},
+			want:       []time.Time{start, second, movedStart, start.AddDate(0, 0, 21)},
+		},
+		{
+			name:       "occurrence must be cancelled or moved",
+			occurrence: OccurrenceRequest{OriginalStart: third},
+			wantErr:    true,
+		},
+		{
+			name:       "date out of the series",
+			occurrence: OccurrenceRequest{OriginalStart: start.Add(time.Hour), Cancel: true},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := newValidEventServiceFields(copyEventMock(initialEvent))
+			ec := EventUpdater{
+				updater: fields.updater,
+				finder:  fields.finder,
+			}
+
+			ctx := auth.ContextWithIdentity(context.Background(), auth.Identity{UserID: initialEvent.UserID})
+			got, err := ec.UpdateEvent(ctx, Request{ID: initialEvent.Event.ExternalID.String(), Occurrence: &tt.occurrence})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateEvent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			var starts []time.Time
+			for _, o := range got.Occurrences(start, start.AddDate(1, 0, 0)) {
+				starts = append(starts, o.Period.Start())
+			}
+
+			if !reflect.DeepEqual(starts, tt.want) {
+				t.Errorf("UpdateEvent() occurrences = %v, want %v", starts, tt.want)
+			}
+		})
+	}
+}
+
 func TestEventUpdater_validateRequiredResources(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -210,27 +466,27 @@ func TestEventUpdater_validateRequiredResources(t *testing.T) {
 	}{
 		{
 			name: "required fields added to event service", fields: eventServiceFields{
-				updater: repository.EventsStorage{},
-				finder:  repository.EventsStorage{},
+				updater: &repository.EventsStorage{},
+				finder:  &repository.EventsStorage{},
 			},
 			wantErr: false,
 		},
 		{
 			name: "no updater repository", fields: eventServiceFields{
-				finder: repository.EventsStorage{},
+				finder: &repository.EventsStorage{},
 			},
 			wantErr: true,
 		},
 		{
 			name: "no finder repository", fields: eventServiceFields{
-				updater: repository.EventsStorage{},
+				updater: &repository.EventsStorage{},
 			},
 			wantErr: true,
 		},
 		{
 			name: "no observers", fields: eventServiceFields{
-				updater: repository.EventsStorage{},
-				finder:  repository.EventsStorage{},
+				updater: &repository.EventsStorage{},
+				finder:  &repository.EventsStorage{},
 			},
 			wantErr: false,
 		},
@@ -259,13 +515,13 @@ func TestNewEventUpdater(t *testing.T) {
 		{
 			name: "creates valid event updater",
 			configuration: []Configuration{
-				WithUpdaterRepository(repository.EventsStorage{}),
-				WithFinderRepository(repository.EventsStorage{}),
+				WithUpdaterRepository(&repository.EventsStorage{}),
+				WithFinderRepository(&repository.EventsStorage{}),
 				WithObservers(mockObserver{}),
 			},
 			want: &EventUpdater{
-				updater:       repository.EventsStorage{},
-				finder:        repository.EventsStorage{},
+				updater:       &repository.EventsStorage{},
+				finder:        &repository.EventsStorage{},
 				observersList: []Observer{mockObserver{}},
 			},
 			wantErr: false,
@@ -2,6 +2,7 @@ package invitation
 
 import (
 	"event-service/internal/domain/event"
+	"event-service/internal/services"
 )
 
 type Configuration func(i *Invitation) error
@@ -29,3 +30,12 @@ func WithInviteFinderRepository(finder event.InviteFinder) Configuration {
 		return nil
 	}
 }
+
+// WithTransactor runs the changes together with the observers in a single transaction
+func WithTransactor(transactor services.Transactor) Configuration {
+	return func(i *Invitation) error {
+		i.transactor = transactor
+
+		return nil
+	}
+}
@@ -3,10 +3,15 @@ package invitation
 import (
 	"context"
 	"errors"
+	"slices"
 
+	"event-service/internal/auth"
 	"event-service/internal/domain/event"
 	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
+	"event-service/internal/metrics"
 	"event-service/internal/services"
+	"event-service/internal/tracing"
 
 	"github.com/google/uuid"
 )
@@ -19,6 +24,7 @@ var (
 	ErrParticipantNotFound     = errors.New("there is no accepted participant in given event")
 	ErrInvitationNotFound      = errors.New("there is no invitation found")
 	ErrEventIsNotPublic        = errors.New("event is private")
+	ErrInvitationDeclined      = errors.New("user has declined the invitation")
 )
 
 type EventType string
@@ -27,12 +33,32 @@ const (
 	UserAcceptedEvent EventType = "UserAcceptedEvent"
 	UserInvitedEvent  EventType = "UserInvitedEvent"
 	UserJoinedEvent   EventType = "UserJoinedEvent"
+	// UserWaitlistedEvent is fired when user joins or accepts invitation of the full event
+	UserWaitlistedEvent EventType = "UserWaitlistedEvent"
+	// UserPromotedEvent is fired when waitlisted user takes the freed spot
+	UserPromotedEvent EventType = "UserPromotedEvent"
+	// UserRemovedEvent is fired when participant or waitlisted user is removed from the event
+	UserRemovedEvent EventType = "UserRemovedEvent"
+	// UserLeftEvent is fired when participant or waitlisted user leaves the event
+	UserLeftEvent     EventType = "UserLeftEvent"
+	UserDeclinedEvent EventType = "UserDeclinedEvent"
+	UserRevokedEvent  EventType = "UserRevokedEvent"
 )
 
+// Handler performs invitation actions as the authenticated user, the user taking part in the accepted
+// or joined event defaults to the authenticated one when it is not given. Only the organizer invites users
+// and revokes pending invitations, invited users accept or decline their own invitations and participants
+// can be removed by the organizer or leave the event themselves. Users joining or accepting invitation
+// of the full event are put on the waitlist. Users who declined the invitation are not invited again,
+// they can still accept it or join the public event, while removed users and users whose invitation
+// has been revoked need a new invitation
 type Handler interface {
 	Invite(ctx context.Context, eventID, userID string) error
 	Accept(ctx context.Context, eventID, userID string) error
+	Decline(ctx context.Context, eventID, userID string) error
+	Revoke(ctx context.Context, eventID, userID string) error
 	Remove(ctx context.Context, eventID, userID string) error
+	Leave(ctx context.Context, eventID string) error
 	Join(ctx context.Context, eventID, userID string) error
 }
 
@@ -44,6 +70,7 @@ type Invitation struct {
 	inviter       event.Inviter
 	inviteFinder  event.InviteFinder
 	eventFinder   event.Finder
+	transactor    services.Transactor
 	observersList map[EventType][]Observer
 }
 
@@ -86,6 +113,20 @@ func (i Invitation) validateRequiredResources() error {
 	return nil
 }
 
+// participant returns the authenticated user and user given for the action, which defaults to the authenticated one
+func (i Invitation) participant(ctx context.Context, user string) (uuid.UUID, string, error) {
+	actor, err := auth.ActorFromContext(ctx)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	if user == "" {
+		return actor, actor.String(), nil
+	}
+
+	return actor, user, nil
+}
+
 func (i Invitation) parseInvitationData(eventID, userID string) (e uuid.UUID, u uuid.UUID, err error) {
 	if u, err = uuid.Parse(userID); err != nil {
 		return e, u, errors.Join(err, ErrInvalidUserID)
@@ -99,38 +140,140 @@ func (i Invitation) parseInvitationData(eventID, userID string) (e uuid.UUID, u
 }
 
 // Invite invites a user to an event
-func (i Invitation) Invite(ctx context.Context, eventExternalID, user string) error {
+func (i Invitation) Invite(ctx context.Context, eventExternalID, user string) (err error) {
+	ctx, span := tracing.Start(ctx, "invitation.Invite")
+	defer tracing.End(span, &err)
+
+	actor, authErr := auth.ActorFromContext(ctx)
+	if authErr != nil {
+		return authErr
+	}
+
 	eventID, userID, parseErr := i.parseInvitationData(eventExternalID, user)
 	if parseErr != nil {
 		return parseErr
 	}
 
+	eventAggregate, finderErr := i.eventFinder.FindByExternalID(ctx, eventID)
+	if finderErr != nil {
+		return finderErr
+	}
+
+	if err := policy.CanInvite(eventAggregate, actor); err != nil {
+		return err
+	}
+
+	if eventAggregate.IsCancelled() {
+		return aggregate.ErrEventCancelled
+	}
+
+	// the removed user and the user whose invitation has been revoked can be invited again
 	if ia, err := i.inviteFinder.FindBy(ctx, eventID, userID); err != nil {
 		return err
-	} else if ia != nil {
+	} else if ia != nil && ia.Status() == aggregate.InvitationDeclined {
+		return ErrInvitationDeclined
+	} else if ia != nil && ia.IsActive() {
 		return ErrInvitationAlreadyExists
 	}
 
-	eventAggregate, finderErr := i.eventFinder.FindByExternalID(ctx, eventID)
-	if finderErr != nil {
-		return finderErr
+	ia, iaErr := aggregate.NewInvitation(eventAggregate, userID)
+	if iaErr != nil {
+		return iaErr
 	}
 
-	ia, iaErr := aggregate.NewInvitation(eventAggregate, userID)
+	ia.InvitedBy = actor
+
+	return services.RunInTransaction(ctx, i.transactor, func(ctx context.Context) error {
+		if err := i.inviter.Invite(ctx, ia); err != nil {
+			return err
+		}
+
+		return i.runObservers(ctx, UserInvitedEvent, *ia)
+	})
+}
+
+// Accept accepts an invitation
+func (i Invitation) Accept(ctx context.Context, eventExternalID, user string) (err error) {
+	ctx, span := tracing.Start(ctx, "invitation.Accept")
+	defer tracing.End(span, &err)
+
+	actor, user, authErr := i.participant(ctx, user)
+	if authErr != nil {
+		return authErr
+	}
+
+	eventID, userID, parseErr := i.parseInvitationData(eventExternalID, user)
+	if parseErr != nil {
+		return parseErr
+	}
+
+	if err := policy.CanActAs(userID, actor); err != nil {
+		return err
+	}
+
+	ia, iaErr := i.inviteFinder.FindBy(ctx, eventID, userID)
+	if iaErr != nil {
+		return iaErr
+	}
+
+	if ia == nil || !ia.IsActive() {
+		return ErrInvitationNotFound
+	}
+
+	return i.accept(ctx, ia, i.inviter.Accept, UserAcceptedEvent)
+}
+
+// Decline declines the pending invitation on behalf of the invited user
+func (i Invitation) Decline(ctx context.Context, eventExternalID, user string) (err error) {
+	ctx, span := tracing.Start(ctx, "invitation.Decline")
+	defer tracing.End(span, &err)
+
+	actor, user, authErr := i.participant(ctx, user)
+	if authErr != nil {
+		return authErr
+	}
+
+	eventID, userID, parseErr := i.parseInvitationData(eventExternalID, user)
+	if parseErr != nil {
+		return parseErr
+	}
+
+	if err := policy.CanActAs(userID, actor); err != nil {
+		return err
+	}
+
+	ia, iaErr := i.inviteFinder.FindBy(ctx, eventID, userID)
 	if iaErr != nil {
 		return iaErr
 	}
 
-	if err := i.inviter.Invite(ctx, ia); err != nil {
+	if ia == nil || !ia.IsActive() {
+		return ErrInvitationNotFound
+	}
+
+	if err := ia.Decline(); err != nil {
 		return err
 	}
 
-	return i.runObservers(ctx, UserInvitedEvent, *ia)
+	return services.RunInTransaction(ctx, i.transactor, func(ctx context.Context) error {
+		if err := i.inviter.Decline(ctx, ia); err != nil {
+			return err
+		}
 
+		return i.runObservers(ctx, UserDeclinedEvent, *ia)
+	})
 }
 
-// Accept accepts an invitation
-func (i Invitation) Accept(ctx context.Context, eventExternalID, user string) error {
+// Revoke withdraws the invitation the user has not answered yet
+func (i Invitation) Revoke(ctx context.Context, eventExternalID, user string) (err error) {
+	ctx, span := tracing.Start(ctx, "invitation.Revoke")
+	defer tracing.End(span, &err)
+
+	actor, authErr := auth.ActorFromContext(ctx)
+	if authErr != nil {
+		return authErr
+	}
+
 	eventID, userID, parseErr := i.parseInvitationData(eventExternalID, user)
 	if parseErr != nil {
 		return parseErr
@@ -145,19 +288,33 @@ func (i Invitation) Accept(ctx context.Context, eventExternalID, user string) er
 		return ErrInvitationNotFound
 	}
 
-	if err := ia.Accept(); err != nil {
+	if err := policy.CanInvite(ia.Event, actor); err != nil {
 		return err
 	}
 
-	if err := i.inviter.Accept(ctx, ia); err != nil {
+	if err := ia.Revoke(); err != nil {
 		return err
 	}
 
-	return i.runObservers(ctx, UserAcceptedEvent, *ia)
+	return services.RunInTransaction(ctx, i.transactor, func(ctx context.Context) error {
+		if err := i.inviter.Revoke(ctx, ia); err != nil {
+			return err
+		}
+
+		return i.runObservers(ctx, UserRevokedEvent, *ia)
+	})
 }
 
 // Remove removes an invitation
-func (i Invitation) Remove(ctx context.Context, eventExternalID, user string) error {
+func (i Invitation) Remove(ctx context.Context, eventExternalID, user string) (err error) {
+	ctx, span := tracing.Start(ctx, "invitation.Remove")
+	defer tracing.End(span, &err)
+
+	actor, authErr := auth.ActorFromContext(ctx)
+	if authErr != nil {
+		return authErr
+	}
+
 	eventID, userID, parseErr := i.parseInvitationData(eventExternalID, user)
 	if parseErr != nil {
 		return parseErr
@@ -172,24 +329,136 @@ func (i Invitation) Remove(ctx context.Context, eventExternalID, user string) er
 		return ErrInvitationNotFound
 	}
 
-	if !ia.IsAccepted() {
+	if err := policy.CanRemove(ia, actor); err != nil {
+		return err
+	}
+
+	return i.remove(ctx, ia, UserRemovedEvent)
+}
+
+// Leave removes the authenticated user from the participants or the waitlist of the event
+func (i Invitation) Leave(ctx context.Context, eventExternalID string) (err error) {
+	ctx, span := tracing.Start(ctx, "invitation.Leave")
+	defer tracing.End(span, &err)
+
+	actor, authErr := auth.ActorFromContext(ctx)
+	if authErr != nil {
+		return authErr
+	}
+
+	eventID, userID, parseErr := i.parseInvitationData(eventExternalID, actor.String())
+	if parseErr != nil {
+		return parseErr
+	}
+
+	ia, iaErr := i.inviteFinder.FindBy(ctx, eventID, userID)
+	if iaErr != nil {
+		return iaErr
+	}
+
+	if ia == nil {
 		return ErrParticipantNotFound
 	}
 
-	if err := i.inviter.Remove(ctx, ia); err != nil {
-		return err
+	return i.remove(ctx, ia, UserLeftEvent)
+}
+
+// remove takes the spot or the place on the waitlist away from the user, the freed spot is taken by the waitlist
+func (i Invitation) remove(ctx context.Context, ia *aggregate.Invitation, eventType EventType) error {
+	if !ia.IsAccepted() && !ia.IsWaitlisted() {
+		return ErrParticipantNotFound
+	}
+
+	// observers are notified with the spot or the place on the waitlist the user has been removed from
+	removed := *ia
+	ia.Remove()
+
+	return services.RunInTransaction(ctx, i.transactor, func(ctx context.Context) error {
+		if err := i.inviter.Remove(ctx, ia); err != nil {
+			return err
+		}
+
+		if err := i.runObservers(ctx, eventType, removed); err != nil {
+			return err
+		}
+
+		if !removed.IsAccepted() {
+			return nil
+		}
+
+		ia.Event.Participants = slices.DeleteFunc(ia.Event.Participants, func(u uuid.UUID) bool {
+			return u == ia.InvitedUser
+		})
+
+		return i.PromoteWaitlisted(ctx, ia.Event)
+	})
+}
+
+// PromoteWaitlisted accepts waitlisted users in order they joined as long as there are free spots in the event
+func (i Invitation) PromoteWaitlisted(ctx context.Context, ea *aggregate.Event) (err error) {
+	ctx, span := tracing.Start(ctx, "invitation.PromoteWaitlisted")
+	defer tracing.End(span, &err)
+
+	return services.RunInTransaction(ctx, i.transactor, func(ctx context.Context) error {
+		return i.promoteWaitlisted(ctx, ea)
+	})
+}
+
+func (i Invitation) promoteWaitlisted(ctx context.Context, ea *aggregate.Event) error {
+	for len(ea.Waitlist) > 0 && ea.HasFreeSpot() && !ea.IsCancelled() {
+		user := ea.Waitlist[0]
+		ea.Waitlist = ea.Waitlist[1:]
+
+		ia, iaErr := i.inviteFinder.FindBy(ctx, ea.Event.ExternalID, user)
+		if iaErr != nil {
+			return iaErr
+		}
+
+		if ia == nil || !ia.IsWaitlisted() {
+			continue
+		}
+
+		ia.Event = ea
+		if err := ia.Promote(); err != nil {
+			return err
+		}
+
+		// the spot could have been taken by the concurrent join, the user stays on the waitlist then
+		if err := i.inviter.Accept(ctx, ia); errors.Is(err, aggregate.ErrCapacityFull) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		ea.Participants = append(ea.Participants, user)
+
+		if err := i.runObservers(ctx, UserPromotedEvent, *ia); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
 // Join joins a user to an event
-func (i Invitation) Join(ctx context.Context, eventExternalID, user string) error {
+func (i Invitation) Join(ctx context.Context, eventExternalID, user string) (err error) {
+	ctx, span := tracing.Start(ctx, "invitation.Join")
+	defer tracing.End(span, &err)
+
+	actor, user, authErr := i.participant(ctx, user)
+	if authErr != nil {
+		return authErr
+	}
+
 	eventID, userID, parseErr := i.parseInvitationData(eventExternalID, user)
 	if parseErr != nil {
 		return parseErr
 	}
 
+	if err := policy.CanActAs(userID, actor); err != nil {
+		return err
+	}
+
 	eventAggregate, finderErr := i.eventFinder.FindByExternalID(ctx, eventID)
 	if finderErr != nil {
 		return finderErr
@@ -204,25 +473,66 @@ func (i Invitation) Join(ctx context.Context, eventExternalID, user string) erro
 		return iaErr
 	}
 
-	if ia == nil {
-		ia, iaErr = aggregate.NewInvitation(eventAggregate, userID)
-		if iaErr != nil {
+	// the removed user and the user whose invitation has been revoked join with the new invitation
+	save := i.inviter.Accept
+	if ia == nil || !ia.IsActive() {
+		if ia, iaErr = aggregate.NewInvitation(eventAggregate, userID); iaErr != nil {
 			return iaErr
 		}
+
+		save = i.inviter.Invite
+	}
+
+	return i.accept(ctx, ia, save, UserJoinedEvent)
+}
+
+// accept persists the accepted invitation with the given repository method. The repository checks the capacity
+// again atomically, so the user who lost the race for the last spot is waitlisted the same way as for the full event
+func (i Invitation) accept(ctx context.Context, ia *aggregate.Invitation, save func(context.Context, *aggregate.Invitation) error, eventType EventType) error {
+	if err := ia.Accept(); errors.Is(err, aggregate.ErrCapacityFull) {
+		metrics.CapacityFullRejections.Inc()
+
+		return i.waitlist(ctx, ia, save)
+	} else if err != nil {
+		return err
 	}
 
-	if err := ia.Accept(); err != nil {
+	err := services.RunInTransaction(ctx, i.transactor, func(ctx context.Context) error {
+		if err := save(ctx, ia); err != nil {
+			return err
+		}
+
+		return i.runObservers(ctx, eventType, *ia)
+	})
+	if !errors.Is(err, aggregate.ErrCapacityFull) {
 		return err
 	}
 
-	if err := i.inviter.Invite(ctx, ia); err != nil {
+	metrics.CapacityFullRejections.Inc()
+	ia.AcceptedAt = nil
+
+	return i.waitlist(ctx, ia, save)
+}
+
+// waitlist queues the user of the full event and persists the invitation with the given repository method
+func (i Invitation) waitlist(ctx context.Context, ia *aggregate.Invitation, save func(context.Context, *aggregate.Invitation) error) error {
+	if err := ia.Waitlist(); err != nil {
 		return err
 	}
 
-	return i.runObservers(ctx, UserJoinedEvent, *ia)
+	return services.RunInTransaction(ctx, i.transactor, func(ctx context.Context) error {
+		if err := save(ctx, ia); err != nil {
+			return err
+		}
+
+		return i.runObservers(ctx, UserWaitlistedEvent, *ia)
+	})
 }
 
-func (i Invitation) runObservers(ctx context.Context, eventType EventType, ia aggregate.Invitation) error {
+func (i Invitation) runObservers(ctx context.Context, eventType EventType, ia aggregate.Invitation) (err error) {
+	ctx, span := tracing.Start(ctx, "invitation.observers "+string(eventType))
+	defer tracing.End(span, &err)
+
 	for _, observer := range i.observersList[eventType] {
 		if err := observer.Notify(ctx, ia); err != nil {
 			return err
@@ -4,16 +4,21 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
+	"event-service/internal/auth"
 	"event-service/internal/database/inmemmory/repository"
 	"event-service/internal/domain/common/entity"
 	"event-service/internal/domain/common/valueobject"
 	"event-service/internal/domain/event"
 	"event-service/internal/domain/event/aggregate"
+	"event-service/internal/domain/event/policy"
+	"event-service/internal/metrics"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 type invitationServiceFields struct {
@@ -99,7 +104,7 @@ func TestInvitation_Accept(t *testing.T) {
 			errType: aggregate.ErrInvitationAlreadyAccepted,
 		},
 		{
-			name: "event capacity reached",
+			name: "event capacity reached puts user on the waitlist",
 			fields: newInvitationServiceFields(func(mi aggregate.Invitation) aggregate.Invitation {
 				e := *mi.Event
 				e.Event.Capacity = 2
@@ -112,8 +117,25 @@ func TestInvitation_Accept(t *testing.T) {
 				eventExternalID: mockInvitation.Event.Event.ExternalID.String(),
 				user:            mockInvitation.InvitedUser.String(),
 			},
+			wantErr: false,
+		},
+		{
+			name: "event cancelled",
+			fields: newInvitationServiceFields(func(mi aggregate.Invitation) aggregate.Invitation {
+				e := *mi.Event
+				entity := *e.Event
+				e.Event = &entity
+				_ = e.Cancel("bad weather")
+				mi.Event = &e
+
+				return mi
+			}(mockInvitation)),
+			args: args{
+				eventExternalID: mockInvitation.Event.Event.ExternalID.String(),
+				user:            mockInvitation.InvitedUser.String(),
+			},
 			wantErr: true,
-			errType: aggregate.ErrCapacityFull,
+			errType: aggregate.ErrEventCancelled,
 		},
 		{
 			name: "registration closed",
@@ -142,7 +164,7 @@ func TestInvitation_Accept(t *testing.T) {
 				eventFinder:   tt.fields.eventFinder,
 				observersList: tt.fields.observersList,
 			}
-			err := i.Accept(context.Background(), tt.args.eventExternalID, tt.args.user)
+			err := i.Accept(newAuthenticatedContext(tt.args.user), tt.args.eventExternalID, tt.args.user)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Accept() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -205,7 +227,7 @@ func TestInvitation_Invite(t *testing.T) {
 				eventFinder:   tt.fields.eventFinder,
 				observersList: tt.fields.observersList,
 			}
-			err := i.Invite(context.Background(), tt.args.eventExternalID, tt.args.user)
+			err := i.Invite(newAuthenticatedContext(mockInvitation.Event.UserID.String()), tt.args.eventExternalID, tt.args.user)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Invite() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -217,6 +239,436 @@ func TestInvitation_Invite(t *testing.T) {
 	}
 }
 
+func newAuthenticatedContext(user string) context.Context {
+	return auth.ContextWithIdentity(context.Background(), auth.Identity{UserID: uuid.MustParse(user)})
+}
+
+func TestInvitation_RequiresAuthentication(t *testing.T) {
+	mockInvitation := newInvitationAggregateMock()
+	i := Invitation{
+		inviter:      newInvitationServiceFields(mockInvitation).inviter,
+		inviteFinder: newInvitationServiceFields(mockInvitation).inviteFinder,
+		eventFinder:  newInvitationServiceFields(*mockInvitation.Event).eventFinder,
+	}
+
+	eventID, userID := mockInvitation.Event.Event.ExternalID.String(), mockInvitation.InvitedUser.String()
+
+	tests := []struct {
+		name   string
+		action func(context.Context, string, string) error
+	}{
+		{name: "invite", action: i.Invite},
+		{name: "accept", action: i.Accept},
+		{name: "decline", action: i.Decline},
+		{name: "revoke", action: i.Revoke},
+		{name: "remove", action: i.Remove},
+		{name: "leave", action: func(ctx context.Context, eventID, _ string) error { return i.Leave(ctx, eventID) }},
+		{name: "join", action: i.Join},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.action(context.Background(), eventID, userID); !errors.Is(err, auth.ErrUnauthenticated) {
+				t.Errorf("%s() error = %v, wantErr %v", tt.name, err, auth.ErrUnauthenticated)
+			}
+		})
+	}
+}
+
+func TestInvitation_Forbidden(t *testing.T) {
+	hourAgo := time.Now().Add(-1 * time.Hour)
+	mockInvitation := newInvitationAggregateMock()
+	acceptedInvitation := mockInvitation
+	acceptedInvitation.AcceptedAt = &hourAgo
+
+	eventID := mockInvitation.Event.Event.ExternalID.String()
+	organizer := mockInvitation.Event.UserID.String()
+	invited := mockInvitation.InvitedUser.String()
+	stranger := uuid.NewString()
+
+	tests := []struct {
+		name   string
+		fields *invitationServiceFields
+		action func(Invitation) func(context.Context, string, string) error
+		actor  string
+		user   string
+	}{
+		{
+			name:   "only organizer invites users",
+			fields: newInvitationServiceFields(*mockInvitation.Event),
+			action: func(i Invitation) func(context.Context, string, string) error { return i.Invite },
+			actor:  stranger,
+			user:   invited,
+		},
+		{
+			name:   "invitation can be accepted only by the invited user",
+			fields: newInvitationServiceFields(mockInvitation),
+			action: func(i Invitation) func(context.Context, string, string) error { return i.Accept },
+			actor:  organizer,
+			user:   invited,
+		},
+		{
+			name:   "participant cannot remove other participants",
+			fields: newInvitationServiceFields(acceptedInvitation),
+			action: func(i Invitation) func(context.Context, string, string) error { return i.Remove },
+			actor:  stranger,
+			user:   invited,
+		},
+		{
+			name:   "users join events only on their own behalf",
+			fields: newInvitationServiceFields(*mockInvitation.Event),
+			action: func(i Invitation) func(context.Context, string, string) error { return i.Join },
+			actor:  stranger,
+			user:   invited,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := Invitation{
+				inviter:       tt.fields.inviter,
+				inviteFinder:  tt.fields.inviteFinder,
+				eventFinder:   tt.fields.eventFinder,
+				observersList: tt.fields.observersList,
+			}
+
+			if err := tt.action(i)(newAuthenticatedContext(tt.actor), eventID, tt.user); !errors.Is(err, policy.ErrForbidden) {
+				t.Errorf("error = %v, wantErr %v", err, policy.ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestInvitation_OrganizerRemovesParticipant(t *testing.T) {
+	hourAgo := time.Now().Add(-1 * time.Hour)
+	mockInvitation := newInvitationAggregateMock()
+	mockInvitation.AcceptedAt = &hourAgo
+	fields := newInvitationServiceFields(mockInvitation)
+
+	i := Invitation{
+		inviter:      fields.inviter,
+		inviteFinder: fields.inviteFinder,
+		eventFinder:  fields.eventFinder,
+	}
+
+	ctx := newAuthenticatedContext(mockInvitation.Event.UserID.String())
+	if err := i.Remove(ctx, mockInvitation.Event.Event.ExternalID.String(), mockInvitation.InvitedUser.String()); err != nil {
+		t.Errorf("Remove() error = %v", err)
+	}
+}
+
+func TestInvitation_RemovedUserCanBeInvitedAgain(t *testing.T) {
+	hourAgo := time.Now().Add(-1 * time.Hour)
+	mockInvitation := newInvitationAggregateMock()
+	mockInvitation.AcceptedAt = &hourAgo
+	fields := newInvitationServiceFields(*mockInvitation.Event, mockInvitation)
+
+	i := Invitation{
+		inviter:      fields.inviter,
+		inviteFinder: fields.inviteFinder,
+		eventFinder:  fields.eventFinder,
+	}
+
+	eventID, userID := mockInvitation.Event.Event.ExternalID.String(), mockInvitation.InvitedUser.String()
+	organizer := newAuthenticatedContext(mockInvitation.Event.UserID.String())
+
+	if err := i.Remove(organizer, eventID, userID); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	removed, _ := fields.inviteFinder.FindBy(context.Background(), mockInvitation.Event.Event.ExternalID, mockInvitation.InvitedUser)
+	if removed == nil || removed.Status() != aggregate.InvitationRemoved {
+		t.Fatalf("Remove() invitation = %v, want removed one", removed)
+	}
+
+	if err := i.Accept(newAuthenticatedContext(userID), eventID, userID); !errors.Is(err, ErrInvitationNotFound) {
+		t.Errorf("Accept() of the removed user error = %v, want %v", err, ErrInvitationNotFound)
+	}
+
+	if err := i.Invite(organizer, eventID, userID); err != nil {
+		t.Fatalf("Invite() of the removed user error = %v", err)
+	}
+
+	invited, _ := fields.inviteFinder.FindBy(context.Background(), mockInvitation.Event.Event.ExternalID, mockInvitation.InvitedUser)
+	if invited.Status() != aggregate.InvitationInvited || invited.InvitedBy != mockInvitation.Event.UserID {
+		t.Errorf("Invite() status = %v invited by %v, want %v invited by %v",
+			invited.Status(), invited.InvitedBy, aggregate.InvitationInvited, mockInvitation.Event.UserID)
+	}
+
+	if err := i.Invite(organizer, eventID, userID); !errors.Is(err, ErrInvitationAlreadyExists) {
+		t.Errorf("Invite() twice error = %v, want %v", err, ErrInvitationAlreadyExists)
+	}
+}
+
+func TestInvitation_AcceptDefaultsToAuthenticatedUser(t *testing.T) {
+	mockInvitation := newInvitationAggregateMock()
+	fields := newInvitationServiceFields(mockInvitation)
+	i := Invitation{
+		inviter:      fields.inviter,
+		inviteFinder: fields.inviteFinder,
+		eventFinder:  fields.eventFinder,
+	}
+
+	ctx := newAuthenticatedContext(mockInvitation.InvitedUser.String())
+	if err := i.Accept(ctx, mockInvitation.Event.Event.ExternalID.String(), ""); err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+
+	ia, _ := fields.inviteFinder.FindBy(ctx, mockInvitation.Event.Event.ExternalID, mockInvitation.InvitedUser)
+	if ia == nil || !ia.IsAccepted() {
+		t.Errorf("Accept() invitation of the authenticated user was not accepted")
+	}
+}
+
+type recordingObserver struct {
+	users *[]uuid.UUID
+}
+
+func (o recordingObserver) Notify(_ context.Context, ia aggregate.Invitation) error {
+	*o.users = append(*o.users, ia.InvitedUser)
+
+	return nil
+}
+
+func TestInvitation_WaitlistPromotion(t *testing.T) {
+	mockEvent := *newInvitationAggregateMock().Event
+	mockEvent.Event.Capacity = 1
+	eventID := mockEvent.Event.ExternalID
+
+	events := repository.NewEventsStorage()
+	_ = events.Add(context.Background(), &mockEvent)
+	invitations := repository.NewLinkedInvitationsStorage(events)
+
+	var waitlisted, promoted, removed []uuid.UUID
+	i := Invitation{
+		inviter:      invitations,
+		inviteFinder: invitations,
+		eventFinder:  events,
+		observersList: map[EventType][]Observer{
+			UserWaitlistedEvent: {recordingObserver{&waitlisted}},
+			UserPromotedEvent:   {recordingObserver{&promoted}},
+			UserRemovedEvent:    {recordingObserver{&removed}},
+		},
+	}
+
+	rejected := testutil.ToFloat64(metrics.CapacityFullRejections)
+
+	participant, first, second := uuid.New(), uuid.New(), uuid.New()
+	for _, user := range []uuid.UUID{participant, first, second} {
+		if err := i.Join(newAuthenticatedContext(user.String()), eventID.String(), ""); err != nil {
+			t.Fatalf("Join() error = %v", err)
+		}
+	}
+
+	if err := i.Join(newAuthenticatedContext(first.String()), eventID.String(), ""); !errors.Is(err, aggregate.ErrAlreadyWaitlisted) {
+		t.Errorf("Join() error = %v, wantErr %v", err, aggregate.ErrAlreadyWaitlisted)
+	}
+
+	if !reflect.DeepEqual(waitlisted, []uuid.UUID{first, second}) {
+		t.Errorf("waitlisted users = %v, want %v", waitlisted, []uuid.UUID{first, second})
+	}
+
+	if got := testutil.ToFloat64(metrics.CapacityFullRejections) - rejected; got != 3 {
+		t.Errorf("capacity full rejections = %v, want 3", got)
+	}
+
+	if err := i.Remove(newAuthenticatedContext(participant.String()), eventID.String(), participant.String()); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(removed, []uuid.UUID{participant}) {
+		t.Errorf("removed users = %v, want %v", removed, []uuid.UUID{participant})
+	}
+
+	if !reflect.DeepEqual(promoted, []uuid.UUID{first}) {
+		t.Errorf("promoted users = %v, want %v", promoted, []uuid.UUID{first})
+	}
+
+	stored, _ := events.FindByExternalID(context.Background(), eventID)
+	if !reflect.DeepEqual(stored.Participants, []uuid.UUID{first}) || !reflect.DeepEqual(stored.Waitlist, []uuid.UUID{second}) {
+		t.Errorf("event participants = %v, waitlist = %v", stored.Participants, stored.Waitlist)
+	}
+
+	if position := stored.WaitlistPosition(second); position != 1 {
+		t.Errorf("WaitlistPosition() = %d, want 1", position)
+	}
+}
+
+func TestInvitation_DeclineRevokeAndLeave(t *testing.T) {
+	mockEvent := *newInvitationAggregateMock().Event
+	mockEvent.Event.Capacity = 1
+	eventID := mockEvent.Event.ExternalID.String()
+
+	events := repository.NewEventsStorage()
+	_ = events.Add(context.Background(), &mockEvent)
+	invitations := repository.NewLinkedInvitationsStorage(events)
+
+	var declined, revoked, left, promoted []uuid.UUID
+	i := Invitation{
+		inviter:      invitations,
+		inviteFinder: invitations,
+		eventFinder:  events,
+		observersList: map[EventType][]Observer{
+			UserDeclinedEvent: {recordingObserver{&declined}},
+			UserRevokedEvent:  {recordingObserver{&revoked}},
+			UserLeftEvent:     {recordingObserver{&left}},
+			UserPromotedEvent: {recordingObserver{&promoted}},
+		},
+	}
+
+	organizer := newAuthenticatedContext(mockEvent.UserID.String())
+	decliner, withdrawn, participant, waiting := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	for _, user := range []uuid.UUID{decliner, withdrawn} {
+		if err := i.Invite(organizer, eventID, user.String()); err != nil {
+			t.Fatalf("Invite() error = %v", err)
+		}
+	}
+
+	for _, user := range []uuid.UUID{participant, waiting} {
+		if err := i.Join(newAuthenticatedContext(user.String()), eventID, ""); err != nil {
+			t.Fatalf("Join() error = %v", err)
+		}
+	}
+
+	if err := i.Decline(newAuthenticatedContext(decliner.String()), eventID, ""); err != nil {
+		t.Fatalf("Decline() error = %v", err)
+	}
+
+	if err := i.Revoke(newAuthenticatedContext(participant.String()), eventID, withdrawn.String()); !errors.Is(err, policy.ErrForbidden) {
+		t.Errorf("Revoke() by the participant error = %v, want %v", err, policy.ErrForbidden)
+	}
+
+	if err := i.Revoke(organizer, eventID, participant.String()); !errors.Is(err, aggregate.ErrInvitationNotPending) {
+		t.Errorf("Revoke() of the accepted invitation error = %v, want %v", err, aggregate.ErrInvitationNotPending)
+	}
+
+	if err := i.Revoke(organizer, eventID, withdrawn.String()); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+
+	if err := i.Leave(newAuthenticatedContext(decliner.String()), eventID); !errors.Is(err, ErrParticipantNotFound) {
+		t.Errorf("Leave() of the declined user error = %v, want %v", err, ErrParticipantNotFound)
+	}
+
+	if err := i.Leave(newAuthenticatedContext(participant.String()), eventID); err != nil {
+		t.Fatalf("Leave() error = %v", err)
+	}
+
+	for _, notified := range []struct {
+		name      string
+		got, want []uuid.UUID
+	}{
+		{name: "declined", got: declined, want: []uuid.UUID{decliner}},
+		{name: "revoked", got: revoked, want: []uuid.UUID{withdrawn}},
+		{name: "left", got: left, want: []uuid.UUID{participant}},
+		{name: "promoted", got: promoted, want: []uuid.UUID{waiting}},
+	} {
+		if !reflect.DeepEqual(notified.got, notified.want) {
+			t.Errorf("%s users = %v, want %v", notified.name, notified.got, notified.want)
+		}
+	}
+
+	// the declined user is not invited again but can change their mind, the revoked invitation cannot be accepted
+	if err := i.Invite(organizer, eventID, decliner.String()); !errors.Is(err, ErrInvitationDeclined) {
+		t.Errorf("Invite() of the declined user error = %v, want %v", err, ErrInvitationDeclined)
+	}
+
+	if err := i.Accept(newAuthenticatedContext(decliner.String()), eventID, ""); err != nil {
+		t.Errorf("Accept() of the declined invitation error = %v", err)
+	}
+
+	if err := i.Accept(newAuthenticatedContext(withdrawn.String()), eventID, ""); !errors.Is(err, ErrInvitationNotFound) {
+		t.Errorf("Accept() of the revoked invitation error = %v, want %v", err, ErrInvitationNotFound)
+	}
+
+	if err := i.Invite(organizer, eventID, withdrawn.String()); err != nil {
+		t.Errorf("Invite() of the user whose invitation was revoked error = %v", err)
+	}
+
+	for user, want := range map[uuid.UUID]aggregate.InvitationStatus{
+		decliner:    aggregate.InvitationWaitlisted,
+		withdrawn:   aggregate.InvitationInvited,
+		participant: aggregate.InvitationRemoved,
+		waiting:     aggregate.InvitationAccepted,
+	} {
+		ia, _ := invitations.FindBy(context.Background(), mockEvent.Event.ExternalID, user)
+		if ia.Status() != want {
+			t.Errorf("status of %s = %v, want %v", user, ia.Status(), want)
+		}
+	}
+}
+
+func TestInvitation_ConcurrentJoinsNeverOverbook(t *testing.T) {
+	const capacity, users = 10, 300
+
+	mockEvent := *newInvitationAggregateMock().Event
+	mockEvent.Event.Capacity = capacity
+	eventID := mockEvent.Event.ExternalID
+
+	events := repository.NewEventsStorage()
+	_ = events.Add(context.Background(), &mockEvent)
+	invitations := repository.NewLinkedInvitationsStorage(events)
+
+	loaded := &sync.WaitGroup{}
+	loaded.Add(users)
+
+	i := Invitation{
+		inviter:      invitations,
+		inviteFinder: stalledInviteFinder{InviteFinder: invitations, loaded: loaded},
+		eventFinder:  events,
+	}
+
+	rejected := testutil.ToFloat64(metrics.CapacityFullRejections)
+	wg := sync.WaitGroup{}
+	errs := make(chan error, users)
+
+	for n := 0; n < users; n++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			errs <- i.Join(newAuthenticatedContext(uuid.NewString()), eventID.String(), "")
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Join() error = %v", err)
+		}
+	}
+
+	stored, _ := events.FindByExternalID(context.Background(), eventID)
+	if len(stored.Participants) != capacity {
+		t.Errorf("event participants = %d, want %d", len(stored.Participants), capacity)
+	}
+
+	if len(stored.Waitlist) != users-capacity {
+		t.Errorf("event waitlist = %d, want %d", len(stored.Waitlist), users-capacity)
+	}
+
+	if got := testutil.ToFloat64(metrics.CapacityFullRejections) - rejected; got != users-capacity {
+		t.Errorf("capacity full rejections = %v, want %d", got, users-capacity)
+	}
+}
+
+// stalledInviteFinder holds every request until all of them have loaded the event,
+// so all of them see the free spots and compete for them while saving
+type stalledInviteFinder struct {
+	event.InviteFinder
+	loaded *sync.WaitGroup
+}
+
+func (f stalledInviteFinder) FindBy(ctx context.Context, eventID, userID uuid.UUID) (*aggregate.Invitation, error) {
+	ia, err := f.InviteFinder.FindBy(ctx, eventID, userID)
+
+	f.loaded.Done()
+	f.loaded.Wait()
+
+	return ia, err
+}
+
 func newInvitationAggregateMock() aggregate.Invitation {
 	now := time.Now()
 	period, _ := valueobject.Period{}.WithStartAndEndDate(now.Add(-10*time.Hour), now.Add(10*time.Hour))
@@ -286,7 +738,7 @@ func TestInvitation_Join(t *testing.T) {
 			errType: aggregate.ErrInvitationAlreadyAccepted,
 		},
 		{
-			name: "event capacity reached",
+			name: "event capacity reached puts user on the waitlist",
 			fields: newInvitationServiceFields(func(me aggregate.Event) aggregate.Event {
 				me.Event.Capacity = 2
 				me.Participants = []uuid.UUID{uuid.New(), uuid.New()}
@@ -297,8 +749,7 @@ func TestInvitation_Join(t *testing.T) {
 				eventExternalID: mockInvitation.Event.Event.ExternalID.String(),
 				user:            mockInvitation.InvitedUser.String(),
 			},
-			wantErr: true,
-			errType: aggregate.ErrCapacityFull,
+			wantErr: false,
 		},
 		{
 			name: "registration closed",
@@ -341,7 +792,7 @@ func TestInvitation_Join(t *testing.T) {
 				eventFinder:   tt.fields.eventFinder,
 				observersList: tt.fields.observersList,
 			}
-			err := i.Join(context.Background(), tt.args.eventExternalID, tt.args.user)
+			err := i.Join(newAuthenticatedContext(tt.args.user), tt.args.eventExternalID, tt.args.user)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Join() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -412,7 +863,7 @@ func TestInvitation_Remove(t *testing.T) {
 				eventFinder:   tt.fields.eventFinder,
 				observersList: tt.fields.observersList,
 			}
-			err := i.Remove(context.Background(), tt.args.eventExternalID, tt.args.user)
+			err := i.Remove(newAuthenticatedContext(tt.args.user), tt.args.eventExternalID, tt.args.user)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Remove() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -438,24 +889,24 @@ func TestInvitation_validateRequiredResources(t *testing.T) {
 		{
 			name: "no inviter repository",
 			fields: &invitationServiceFields{
-				inviteFinder: repository.InvitationsStorage{},
-				eventFinder:  repository.EventsStorage{},
+				inviteFinder: &repository.InvitationsStorage{},
+				eventFinder:  &repository.EventsStorage{},
 			},
 			wantErr: true,
 		},
 		{
 			name: "no invite finder  repository",
 			fields: &invitationServiceFields{
-				inviter:     repository.InvitationsStorage{},
-				eventFinder: repository.EventsStorage{},
+				inviter:     &repository.InvitationsStorage{},
+				eventFinder: &repository.EventsStorage{},
 			},
 			wantErr: true,
 		},
 		{
 			name: "no event finder repository",
 			fields: &invitationServiceFields{
-				inviter:      repository.InvitationsStorage{},
-				inviteFinder: repository.InvitationsStorage{},
+				inviter:      &repository.InvitationsStorage{},
+				inviteFinder: &repository.InvitationsStorage{},
 			},
 			wantErr: true,
 		},
@@ -485,14 +936,14 @@ func TestNewInvitation(t *testing.T) {
 		{
 			name: "valid configuration",
 			configuration: []Configuration{
-				WithInvitationRepository(repository.InvitationsStorage{}),
-				WithInviteFinderRepository(repository.InvitationsStorage{}),
-				WithEventFinderRepository(repository.EventsStorage{}),
+				WithInvitationRepository(&repository.InvitationsStorage{}),
+				WithInviteFinderRepository(&repository.InvitationsStorage{}),
+				WithEventFinderRepository(&repository.EventsStorage{}),
 			},
 			want: &Invitation{
-				inviter:       repository.InvitationsStorage{},
-				inviteFinder:  repository.InvitationsStorage{},
-				eventFinder:   repository.EventsStorage{},
+				inviter:       &repository.InvitationsStorage{},
+				inviteFinder:  &repository.InvitationsStorage{},
+				eventFinder:   &repository.EventsStorage{},
 				observersList: map[EventType][]Observer{},
 			},
 			wantErr: false,
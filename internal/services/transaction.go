@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// Transactor runs the function in a transaction, repositories called with the given context take part in it
+// and the changes are rolled back when the function fails
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// WithoutTransaction runs the function directly, it is used by storages without transactions support
+type WithoutTransaction struct{}
+
+func (WithoutTransaction) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// RunInTransaction runs the function in the transaction of the transactor, it runs the function directly without one
+func RunInTransaction(ctx context.Context, t Transactor, fn func(ctx context.Context) error) error {
+	if t == nil {
+		return fn(ctx)
+	}
+
+	return t.Transaction(ctx, fn)
+}
+
+type commitHooksKey struct{}
+
+type commitHooks struct {
+	mu    sync.Mutex
+	hooks []func()
+}
+
+func (h *commitHooks) run() {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+// AfterCommit runs the function once the transaction of the context is committed, functions of rolled back
+// transactions are dropped. Without the transaction, e.g. for storages without transactions support, it runs at once
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(commitHooksKey{}).(*commitHooks)
+	if !ok {
+		fn()
+
+		return
+	}
+
+	hooks.mu.Lock()
+	hooks.hooks = append(hooks.hooks, fn)
+	hooks.mu.Unlock()
+}
+
+// WithCommitHooks prepares the context of the new transaction, the returned function is called by the transactor
+// after the commit. Nested transactions share hooks of the outer one, so they run after the outermost commit
+func WithCommitHooks(ctx context.Context) (context.Context, func()) {
+	if _, ok := ctx.Value(commitHooksKey{}).(*commitHooks); ok {
+		return ctx, func() {}
+	}
+
+	hooks := &commitHooks{}
+
+	return context.WithValue(ctx, commitHooksKey{}, hooks), hooks.run
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// hookingTransactor runs the commit hooks the way the database transactors do
+type hookingTransactor struct{}
+
+func (hookingTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, commit := WithCommitHooks(ctx)
+	if err := fn(ctx); err != nil {
+		return err
+	}
+
+	commit()
+
+	return nil
+}
+
+func TestAfterCommit(t *testing.T) {
+	var calls []string
+	record := func(name string) func() {
+		return func() { calls = append(calls, name) }
+	}
+
+	AfterCommit(context.Background(), record("without transaction"))
+
+	_ = RunInTransaction(context.Background(), hookingTransactor{}, func(ctx context.Context) error {
+		AfterCommit(ctx, record("outer"))
+
+		_ = RunInTransaction(ctx, hookingTransactor{}, func(ctx context.Context) error {
+			AfterCommit(ctx, record("nested"))
+
+			return nil
+		})
+
+		calls = append(calls, "before commit")
+
+		return nil
+	})
+
+	_ = RunInTransaction(context.Background(), hookingTransactor{}, func(ctx context.Context) error {
+		AfterCommit(ctx, record("rolled back"))
+
+		return errors.New("failed")
+	})
+
+	want := []string{"without transaction", "before commit", "outer", "nested"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("AfterCommit() calls = %v, want %v", calls, want)
+	}
+}
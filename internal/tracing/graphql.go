@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GraphQLExtension starts a span for every GraphQL operation and for every field with a resolver
+type GraphQLExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+	graphql.FieldInterceptor
+} = GraphQLExtension{}
+
+func (GraphQLExtension) ExtensionName() string {
+	return "Tracing"
+}
+
+func (GraphQLExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (GraphQLExtension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+
+	name, operationType := oc.OperationName, "unknown"
+	if oc.Operation != nil {
+		operationType = string(oc.Operation.Operation)
+
+		if name == "" {
+			name = oc.Operation.Name
+		}
+	}
+
+	spanName := "graphql." + operationType
+	if name != "" {
+		spanName += " " + name
+	}
+
+	ctx, span := Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		attribute.String("graphql.operation.name", name),
+		attribute.String("graphql.operation.type", operationType),
+	))
+
+	handler := next(ctx)
+
+	return func(ctx context.Context) *graphql.Response {
+		resp := handler(ctx)
+
+		if resp == nil || len(resp.Errors) > 0 {
+			span.SetStatus(codes.Error, "graphql operation failed")
+		}
+
+		// subscriptions respond until the stream ends, the span covers the whole stream
+		if resp == nil || operationType != "subscription" {
+			span.End()
+		}
+
+		return resp
+	}
+}
+
+func (GraphQLExtension) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := Start(ctx, "graphql.resolve "+fc.Object+"."+fc.Field.Name, trace.WithAttributes(
+		attribute.String("graphql.field.path", fc.Path().String()),
+	))
+
+	res, err := next(ctx)
+	End(span, &err)
+
+	return res, err
+}
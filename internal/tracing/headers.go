@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// HeadersCarrier carries the trace context in message headers, e.g. in amqp.Table of published messages
+type HeadersCarrier map[string]any
+
+func (c HeadersCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+
+	return value
+}
+
+func (c HeadersCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c HeadersCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Inject writes the trace context of the span in the context to the headers
+func Inject(ctx context.Context, headers map[string]any) {
+	otel.GetTextMapPropagator().Inject(ctx, HeadersCarrier(headers))
+}
+
+// Extract returns the context with the remote span of the headers, it is the parent of spans started from it
+func Extract(ctx context.Context, headers map[string]any) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, HeadersCarrier(headers))
+}
@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInjectExtract(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	ctx, producer := Start(context.Background(), "outbox.publish event-update")
+	headers := map[string]any{"schema-version": int32(1)}
+	Inject(ctx, headers)
+	producer.End()
+
+	if _, ok := headers["traceparent"].(string); !ok {
+		t.Fatalf("Inject() headers = %v, want traceparent", headers)
+	}
+
+	_, consumer := Start(Extract(context.Background(), headers), "consume event-update")
+	consumer.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+
+	if spans[1].Parent().SpanID() != spans[0].SpanContext().SpanID() || spans[1].SpanContext().TraceID() != spans[0].SpanContext().TraceID() {
+		t.Errorf("Extract() consumer span is not the child of the producer span")
+	}
+
+	if !spans[1].Parent().IsRemote() {
+		t.Errorf("Extract() parent span is not remote")
+	}
+}
@@ -0,0 +1,142 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	InstrumentationName = "event-service"
+
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Parameters configure the exporter of the spans, tracing is disabled when the exporter is empty.
+// The stdout exporter writes to the file when it is given
+type Parameters struct {
+	Exporter       string
+	ServiceName    string
+	ServiceVersion string
+	Endpoint       string
+	Insecure       bool
+	File           string
+	SampleRatio    float64
+}
+
+// Setup registers the global tracer provider with W3C trace context propagation, the returned function
+// flushes the spans and shuts the provider down
+func Setup(ctx context.Context, params Parameters) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if params.Exporter == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeOutput, err := newExporter(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(params.ServiceName),
+		semconv.ServiceVersion(params.ServiceVersion),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := sdktrace.ParentBased(sdktrace.AlwaysSample())
+	if params.SampleRatio > 0 && params.SampleRatio < 1 {
+		sampler = sdktrace.ParentBased(sdktrace.TraceIDRatioBased(params.SampleRatio))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeErr := closeOutput(); err == nil {
+			err = closeErr
+		}
+
+		return err
+	}, nil
+}
+
+func newExporter(ctx context.Context, params Parameters) (sdktrace.SpanExporter, func() error, error) {
+	noop := func() error { return nil }
+
+	switch params.Exporter {
+	case ExporterOTLP:
+		options := []otlptracehttp.Option{}
+		if params.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(params.Endpoint))
+		}
+
+		if params.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, options...)
+
+		return exporter, noop, err
+	case ExporterStdout:
+		var (
+			output io.Writer = os.Stdout
+			closer           = noop
+		)
+
+		if params.File != "" {
+			f, err := os.OpenFile(params.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			output, closer = f, f.Close
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(output))
+
+		return exporter, closer, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported tracing exporter %q", params.Exporter)
+	}
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// Start starts the span as a child of the span in the context
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// End records the error returned by the traced call and ends the span, it is meant to be deferred
+// with the pointer to the returned error
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+
+	span.End()
+}
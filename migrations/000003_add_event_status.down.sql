@@ -0,0 +1,4 @@
+ALTER TABLE `events`
+    DROP COLUMN `cancelled_at`,
+    DROP COLUMN `cancellation_reason`,
+    DROP COLUMN `status`;
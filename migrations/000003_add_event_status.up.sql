@@ -0,0 +1,4 @@
+ALTER TABLE `events`
+    ADD COLUMN `status`              VARCHAR(20) NOT NULL DEFAULT 'scheduled' AFTER `public`,
+    ADD COLUMN `cancellation_reason` TEXT        NULL AFTER `status`,
+    ADD COLUMN `cancelled_at`        DATETIME    NULL AFTER `cancellation_reason`;
@@ -0,0 +1,2 @@
+ALTER TABLE `invitations`
+    ADD COLUMN `waitlisted_at` DATETIME NULL AFTER `accepted_at`;
@@ -0,0 +1,6 @@
+DROP TABLE `event_occurrence_overrides`;
+
+ALTER TABLE `events`
+    DROP COLUMN `recurrence_end`,
+    DROP COLUMN `recurrence_exceptions`,
+    DROP COLUMN `recurrence_rule`;
@@ -0,0 +1,22 @@
+ALTER TABLE `events`
+    ADD COLUMN `recurrence_rule`       VARCHAR(255) NOT NULL DEFAULT '' AFTER `cancelled_at`,
+    ADD COLUMN `recurrence_exceptions` TEXT         NULL AFTER `recurrence_rule`,
+    ADD COLUMN `recurrence_end`        DATETIME     NULL AFTER `recurrence_exceptions`;
+
+CREATE TABLE IF NOT EXISTS `event_occurrence_overrides`
+(
+    `event_id`            INT UNSIGNED NOT NULL,
+    `original_start_date` DATETIME     NOT NULL,
+    `start_date`          DATETIME     NULL,
+    `end_date`            DATETIME     NULL,
+    `cancelled`           BOOL         NOT NULL DEFAULT false,
+    CONSTRAINT `event_occurrence_overrides_pk`
+        PRIMARY KEY (event_id, original_start_date),
+    CONSTRAINT `fk_event_occurrence_overrides_events`
+        FOREIGN KEY (event_id) REFERENCES events (id)
+            ON DELETE RESTRICT
+            ON UPDATE RESTRICT
+)
+    ENGINE = InnoDB
+    DEFAULT CHARACTER SET = utf8
+    COLLATE = utf8_unicode_ci;
@@ -0,0 +1,2 @@
+ALTER TABLE `events`
+    ADD COLUMN `sequence` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `recurrence_end`;
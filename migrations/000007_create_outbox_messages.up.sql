@@ -0,0 +1,17 @@
+create table outbox_messages
+(
+    id              int UNSIGNED auto_increment primary key,
+    exchange        varchar(255)           not null,
+    routing_key     varchar(255)           not null,
+    type            varchar(255)           not null default '',
+    headers         text                   null,
+    body            mediumblob             not null,
+    attempts        int UNSIGNED           not null default 0,
+    last_error      text                   null,
+    next_attempt_at datetime(6)            not null,
+    created_at      datetime(6) default NOW(6) not null,
+    sent_at         datetime(6)            null,
+    index outbox_messages_pending_idx (sent_at, id)
+) ENGINE = InnoDB
+    DEFAULT CHARACTER SET = utf8
+    COLLATE = utf8_unicode_ci;
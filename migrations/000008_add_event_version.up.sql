@@ -0,0 +1,2 @@
+ALTER TABLE `events`
+    ADD COLUMN `version` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `sequence`;
@@ -0,0 +1,4 @@
+ALTER TABLE `invitations`
+    DROP COLUMN `invited_by`,
+    DROP COLUMN `declined_at`,
+    DROP COLUMN `removed_at`;
@@ -0,0 +1,4 @@
+ALTER TABLE `invitations`
+    ADD COLUMN `invited_by` VARCHAR(50) NULL AFTER `user_id`,
+    ADD COLUMN `declined_at` DATETIME NULL AFTER `waitlisted_at`,
+    ADD COLUMN `removed_at` DATETIME NULL AFTER `declined_at`;
@@ -0,0 +1,2 @@
+ALTER TABLE `invitations`
+    ADD COLUMN `revoked_at` DATETIME NULL AFTER `declined_at`;
@@ -0,0 +1,2 @@
+ALTER TABLE `invitations`
+    MODIFY COLUMN `waitlisted_at` DATETIME(6) NULL;
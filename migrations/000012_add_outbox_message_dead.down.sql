@@ -0,0 +1,2 @@
+ALTER TABLE `outbox_messages`
+    DROP COLUMN `dead_at`;
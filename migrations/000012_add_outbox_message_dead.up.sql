@@ -0,0 +1,2 @@
+ALTER TABLE `outbox_messages`
+    ADD COLUMN `dead_at` DATETIME(6) NULL AFTER `sent_at`;
@@ -0,0 +1,7 @@
+// Package migrations embeds the sql migrations of the database, so the binary migrates the schema from any directory
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS